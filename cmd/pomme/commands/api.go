@@ -0,0 +1,326 @@
+package commands
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/marcusziade/pomme/internal/api"
+	"github.com/marcusziade/pomme/internal/client"
+	"github.com/marcusziade/pomme/internal/config"
+	"github.com/marcusziade/pomme/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api [method] <path>",
+	Short: "Make an authenticated App Store Connect API request",
+	Long: `Calls any App Store Connect API endpoint with your credentials and prints
+the response, for resources pomme doesn't wrap yet.
+
+The method defaults to GET, or POST when --input is given. Paths without a
+version get /v1/ prepended, so "apps" and "/v1/apps" are the same. Gzipped
+report bodies are decompressed automatically.`,
+	Example: `  pomme api apps -f "fields[apps]=name,bundleId" -f limit=200
+  pomme api /v1/apps --paginate --filter '.data[].attributes.name'
+  pomme api salesReports -f "filter[vendorNumber]=12345678" -f "filter[frequency]=DAILY" \
+    -f "filter[reportType]=SALES" -f "filter[reportSubType]=SUMMARY" -f "filter[reportDate]=2025-03-01"
+  pomme api PATCH apps/123456789 --input body.json
+  echo '{"data":{...}}' | pomme api POST customerReviewResponses --input -`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runAPI,
+}
+
+func init() {
+	RootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().StringArrayP("field", "f", nil, "Add a query parameter in key=value form (repeatable)")
+	apiCmd.Flags().StringArrayP("header", "H", nil, "Add a request header in 'Name: value' form (repeatable)")
+	apiCmd.Flags().String("input", "", "File containing the JSON request body, or - for standard input")
+	apiCmd.Flags().Bool("paginate", false, "Follow links.next and merge the data of every page")
+	apiCmd.Flags().BoolP("include", "i", false, "Print the response status line and headers")
+	apiCmd.Flags().StringP("filter", "q", "", "Select values with a jq-style expression, e.g. '.data[].id'")
+}
+
+// apiVersionPrefix matches paths that already name an API version
+var apiVersionPrefix = regexp.MustCompile(`^/v\d+/`)
+
+func runAPI(cmd *cobra.Command, args []string) error {
+	input := mustGetString(cmd, "input")
+	paginate := mustGetBool(cmd, "paginate")
+	include := mustGetBool(cmd, "include")
+
+	method := http.MethodGet
+	if input != "" {
+		method = http.MethodPost
+	}
+	path := args[0]
+	if len(args) == 2 {
+		method = strings.ToUpper(args[0])
+		path = args[1]
+	}
+	if paginate && method != http.MethodGet {
+		return fmt.Errorf("--paginate only works with GET requests")
+	}
+
+	var filter *output.Filter
+	if expr := mustGetString(cmd, "filter"); expr != "" {
+		var err error
+		if filter, err = output.ParseFilter(expr); err != nil {
+			return err
+		}
+	}
+
+	fields, _ := cmd.Flags().GetStringArray("field")
+	path, err := apiRequestPath(path, fields)
+	if err != nil {
+		return err
+	}
+
+	rawHeaders, _ := cmd.Flags().GetStringArray("header")
+	header, err := parseHeaders(rawHeaders)
+	if err != nil {
+		return err
+	}
+	// Report endpoints only answer with gzipped TSV
+	if isReportPath(path) && header.Get("Accept") == "" {
+		header.Set("Accept", "application/a-gzip")
+	}
+
+	var body []byte
+	if input != "" {
+		if body, err = readInput(input); err != nil {
+			return err
+		}
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", "application/json")
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiClient, err := client.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	ctx := context.Background()
+	var merged map[string]interface{}
+
+	for next := path; next != ""; {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+
+		resp, err := apiClient.API().RequestWithHeaders(ctx, method, next, reader, header)
+		if err != nil {
+			var statusErr *api.StatusError
+			if include && errors.As(err, &statusErr) {
+				writeResponseHeaders(os.Stdout, statusErr.Response)
+			}
+			return err
+		}
+		data, err := readResponse(resp)
+		if err != nil {
+			return err
+		}
+		if include {
+			writeResponseHeaders(os.Stdout, resp)
+		}
+
+		if !paginate {
+			return writeAPIResponse(data, filter)
+		}
+
+		var page map[string]interface{}
+		if err := decodeJSON(data, &page); err != nil {
+			return fmt.Errorf("--paginate needs JSON responses: %w", err)
+		}
+		merged = mergePage(merged, page)
+		next = nextLink(page)
+	}
+
+	if merged == nil {
+		return nil
+	}
+	delete(merged, "links")
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return writeAPIResponse(encoded, filter)
+}
+
+// apiRequestPath normalizes the path and appends the -f query parameters.
+// Absolute URLs are kept as they are.
+func apiRequestPath(path string, fields []string) (string, error) {
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		if !apiVersionPrefix.MatchString(path) {
+			path = "/v1" + path
+		}
+	}
+	if len(fields) == 0 {
+		return path, nil
+	}
+
+	query := url.Values{}
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return "", fmt.Errorf("invalid field %q: expected key=value", field)
+		}
+		query.Add(key, value)
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + query.Encode(), nil
+}
+
+// parseHeaders parses "Name: value" header flags
+func parseHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, value := range values {
+		name, content, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q: expected 'Name: value'", value)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(content))
+	}
+	return header, nil
+}
+
+// isReportPath reports whether the path is a sales or finance report download
+func isReportPath(path string) bool {
+	if u, err := url.Parse(path); err == nil {
+		path = u.Path
+	}
+	return strings.HasSuffix(path, "/salesReports") || strings.HasSuffix(path, "/financeReports")
+}
+
+// readInput reads the request body from a file or standard input
+func readInput(name string) ([]byte, error) {
+	if name == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body from stdin: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	return data, nil
+}
+
+// readResponse reads the body, decompressing gzip report downloads
+func readResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer reader.Close()
+
+		if data, err = io.ReadAll(reader); err != nil {
+			return nil, fmt.Errorf("failed to read gzipped data: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// writeResponseHeaders prints the status line and headers like curl -i
+func writeResponseHeaders(w io.Writer, resp *http.Response) {
+	fmt.Fprintf(w, "%s %s\n", resp.Proto, resp.Status)
+
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range resp.Header[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// writeAPIResponse pretty-prints JSON, applies the filter, or passes other
+// content such as report TSV through unchanged
+func writeAPIResponse(data []byte, filter *output.Filter) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	var document interface{}
+	if err := decodeJSON(data, &document); err != nil {
+		if filter != nil {
+			return fmt.Errorf("--filter needs a JSON response: %w", err)
+		}
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if filter != nil {
+		return filter.WriteFiltered(os.Stdout, document)
+	}
+	return output.JSON(document)
+}
+
+// decodeJSON decodes keeping numbers exact, so large IDs survive re-encoding
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// mergePage appends a page's data and included resources to the pages so far
+func mergePage(merged, page map[string]interface{}) map[string]interface{} {
+	if merged == nil {
+		return page
+	}
+	for _, key := range []string{"data", "included"} {
+		items, ok := page[key].([]interface{})
+		if !ok {
+			continue
+		}
+		existing, _ := merged[key].([]interface{})
+		merged[key] = append(existing, items...)
+	}
+	return merged
+}
+
+// nextLink returns the page's links.next URL, if any
+func nextLink(page map[string]interface{}) string {
+	links, _ := page["links"].(map[string]interface{})
+	next, _ := links["next"].(string)
+	return next
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/marcusziade/pomme/internal/config"
 	"github.com/marcusziade/pomme/internal/output"
-	"github.com/marcusziade/pomme/pkg/pomme"
 	"github.com/spf13/cobra"
 )
 
@@ -33,26 +32,16 @@ var appsListCmd = &cobra.Command{
 		if cfg.Auth.KeyID == "" {
 			return fmt.Errorf("key ID not set in config")
 		}
-		if cfg.Auth.IssuerID == "" {
+		if cfg.Auth.IssuerID == "" && !cfg.Auth.IsIndividual() {
 			return fmt.Errorf("issuer ID not set in config")
 		}
-		if cfg.Auth.PrivateKeyPath == "" {
-			return fmt.Errorf("private key path not set in config")
-		}
 		
-		// Read private key
-		privateKeyData, err := os.ReadFile(cfg.Auth.PrivateKeyPath)
+		// Create client
+		client, err := newPommeClient(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to read private key: %w", err)
+			return err
 		}
 		
-		// Create client
-		client := pomme.NewClient(
-			cfg.Auth.KeyID,
-			cfg.Auth.IssuerID,
-			string(privateKeyData),
-		)
-		
 		// Get output format
 		outputFormat, _ := cmd.Flags().GetString("output")
 		if outputFormat == "" {
@@ -94,26 +83,16 @@ var appsInfoCmd = &cobra.Command{
 		if cfg.Auth.KeyID == "" {
 			return fmt.Errorf("key ID not set in config")
 		}
-		if cfg.Auth.IssuerID == "" {
+		if cfg.Auth.IssuerID == "" && !cfg.Auth.IsIndividual() {
 			return fmt.Errorf("issuer ID not set in config")
 		}
-		if cfg.Auth.PrivateKeyPath == "" {
-			return fmt.Errorf("private key path not set in config")
-		}
 		
-		// Read private key
-		privateKeyData, err := os.ReadFile(cfg.Auth.PrivateKeyPath)
+		// Create client
+		client, err := newPommeClient(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to read private key: %w", err)
+			return err
 		}
 		
-		// Create client
-		client := pomme.NewClient(
-			cfg.Auth.KeyID,
-			cfg.Auth.IssuerID,
-			string(privateKeyData),
-		)
-		
 		// Get output format
 		outputFormat, _ := cmd.Flags().GetString("output")
 		if outputFormat == "" {
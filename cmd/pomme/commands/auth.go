@@ -1,11 +1,22 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/marcusziade/pomme/internal/auth"
+	"github.com/marcusziade/pomme/internal/client"
 	"github.com/marcusziade/pomme/internal/config"
+	"github.com/marcusziade/pomme/internal/output"
+	"github.com/marcusziade/pomme/pkg/jsonapi"
+	"github.com/marcusziade/pomme/pkg/pomme"
 	"github.com/spf13/cobra"
 )
 
@@ -18,64 +29,643 @@ var authCmd = &cobra.Command{
 var authTestCmd = &cobra.Command{
 	Use:   "test",
 	Short: "Test authentication",
-	Long:  `Tests your authentication credentials by generating a JWT token.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Load config
-		cfg, err := config.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+	Long: `Tests your credentials against the App Store Connect API. Signs a token,
+makes a lightweight authenticated request, checks your clock against Apple's
+and probes which parts of the API the key can access.`,
+	RunE: runAuthTest,
+}
+
+var authSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Set up authentication credentials",
+	Long: `Interactive setup for App Store Connect API credentials. Validates the .p8
+key, key ID and issuer ID before saving them to the config file.`,
+	RunE: runAuthSetup,
+}
+
+// maxClockSkew is how far the local clock may drift from Apple's before
+// tokens risk being rejected
+const maxClockSkew = time.Minute
+
+// accessProbe is an API area checked by auth test
+type accessProbe struct {
+	name    string
+	path    string
+	allowed bool
+	status  int
+	note    string
+}
+
+func runAuthTest(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	fmt.Println(colorBold + "🔐 Testing Authentication" + colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	
+	// Credentials
+	fmt.Print("\nChecking credentials... ")
+	if err := auth.ValidateKeyID(cfg.Auth.KeyID); err != nil {
+		fmt.Println(colorRed + "✗" + colorReset)
+		return err
+	}
+	if !cfg.Auth.IsIndividual() {
+		if err := auth.ValidateIssuerID(cfg.Auth.IssuerID); err != nil {
+			fmt.Println(colorRed + "✗" + colorReset)
+			return err
 		}
-		
-		// Validate config
-		if cfg.Auth.KeyID == "" {
-			return fmt.Errorf("key ID not set in config")
+	}
+	if err := newJWTConfig(cfg, "").Validate(); err != nil {
+		fmt.Println(colorRed + "✗" + colorReset)
+		return err
+	}
+	fmt.Println(colorGreen + "✓" + colorReset)
+	
+	// Private key
+	source, err := auth.KeySourceFromConfig(cfg.Auth)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Reading private key from %s... ", source)
+	privateKeyData, err := source.PrivateKey(context.Background())
+	if err == nil {
+		err = auth.ValidatePrivateKey(privateKeyData)
+	}
+	if err != nil {
+		fmt.Println(colorRed + "✗" + colorReset)
+		return err
+	}
+	fmt.Println(colorGreen + "✓" + colorReset)
+	
+	// Local signing
+	fmt.Print("Signing token... ")
+	if _, err := auth.GenerateToken(newJWTConfig(cfg, privateKeyData)); err != nil {
+		fmt.Println(colorRed + "✗" + colorReset)
+		return fmt.Errorf("failed to generate JWT token: %w", err)
+	}
+	fmt.Println(colorGreen + "✓" + colorReset)
+	
+	apiClient, err := client.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	
+	// Authenticated request
+	fmt.Print("Calling GET /v1/apps?limit=1... ")
+	sent := time.Now()
+	resp, err := rawGet(ctx, apiClient, "/v1/apps?limit=1")
+	if err != nil {
+		fmt.Println(colorRed + "✗" + colorReset)
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	elapsed := time.Since(sent)
+	
+	var apps jsonapi.Document[[]jsonapi.RawResource]
+	if resp.StatusCode == http.StatusOK {
+		json.NewDecoder(resp.Body).Decode(&apps)
+	}
+	resp.Body.Close()
+	
+	// Apple's Date header has second precision, so compare with the midpoint
+	skew, hasSkew := clockSkew(resp.Header.Get("Date"), sent.Add(elapsed/2))
+	
+	switch resp.StatusCode {
+	case http.StatusOK:
+		fmt.Printf("%s✓%s %s(%d, %dms)%s\n", colorGreen, colorReset, colorGray, resp.StatusCode, elapsed.Milliseconds(), colorReset)
+	case http.StatusUnauthorized:
+		fmt.Println(colorRed + "✗ 401 Unauthorized" + colorReset)
+		fmt.Println("\nApple rejected the token. Make sure:")
+		fmt.Println("  • The key hasn't been revoked")
+		fmt.Println("  • The key ID and issuer ID belong to this key")
+		if hasSkew && absDuration(skew) > maxClockSkew {
+			fmt.Printf("  • Your clock is off by %s; sync it (e.g. enable NTP)\n", skew.Round(time.Second))
 		}
-		if cfg.Auth.IssuerID == "" {
-			return fmt.Errorf("issuer ID not set in config")
+		return fmt.Errorf("authentication failed")
+	default:
+		fmt.Printf("%s✗ %d%s\n", colorRed, resp.StatusCode, colorReset)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	
+	// Clock skew
+	fmt.Print("Checking clock... ")
+	switch {
+	case !hasSkew:
+		fmt.Println(colorYellow + "? no Date header in response" + colorReset)
+	case absDuration(skew) > maxClockSkew:
+		fmt.Printf("%s⚠️  %s off from Apple%s\n", colorYellow, formatSkew(skew), colorReset)
+		fmt.Println(colorGray + "   Tokens are time-limited; a drifting clock will cause intermittent 401s. Enable NTP." + colorReset)
+	default:
+		fmt.Printf("%s✓%s %s(%s)%s\n", colorGreen, colorReset, colorGray, formatSkew(skew), colorReset)
+	}
+	
+	// Access
+	appID := ""
+	if len(apps.Data) > 0 {
+		appID = apps.Data[0].ID
+	}
+	probes := probeAccess(ctx, apiClient, appID, cfg.Defaults.VendorNumber)
+	
+	fmt.Println("\n" + colorBold + "Access" + colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	for _, probe := range probes {
+		mark := colorGreen + "✓" + colorReset
+		if !probe.allowed {
+			mark = colorRed + "✗" + colorReset
 		}
-		if cfg.Auth.PrivateKeyPath == "" {
-			return fmt.Errorf("private key path not set in config")
+		if probe.status == 0 {
+			mark = colorGray + "-" + colorReset
 		}
-		
-		// Read private key
-		privateKeyData, err := os.ReadFile(cfg.Auth.PrivateKeyPath)
+		note := probe.note
+		if note == "" && probe.status != 0 {
+			note = fmt.Sprintf("%d", probe.status)
+		}
+		fmt.Printf("  %s %-18s %s%s%s\n", mark, probe.name, colorGray, note, colorReset)
+	}
+	
+	fmt.Printf("\nLikely role: %s%s%s\n", colorCyan, inferRole(probes), colorReset)
+	fmt.Println(colorGray + "The API doesn't report a key's role; it's inferred from the endpoints above." + colorReset)
+	
+	fmt.Println("\n" + colorGreen + "✅ Authentication works" + colorReset)
+	return nil
+}
+
+// probeAccess checks which API areas the key can read. Probes that need an
+// app or vendor number are skipped when those aren't available.
+func probeAccess(ctx context.Context, apiClient *client.Client, appID, vendorNumber string) []accessProbe {
+	probes := []accessProbe{
+		{name: "Apps", path: "/v1/apps?limit=1"},
+		{name: "Users", path: "/v1/users?limit=1"},
+	}
+	
+	if appID != "" {
+		probes = append(probes, accessProbe{
+			name: "Customer reviews",
+			path: fmt.Sprintf("/v1/apps/%s/customerReviews?limit=1", appID),
+		})
+	} else {
+		probes = append(probes, accessProbe{name: "Customer reviews", note: "skipped: no apps"})
+	}
+	
+	if vendorNumber != "" {
+		// 404 means there's no report for that day, which still proves access
+		reportDate := time.Now().AddDate(0, 0, -3).Format("2006-01-02")
+		probes = append(probes, accessProbe{
+			name: "Sales reports",
+			path: fmt.Sprintf("/v1/salesReports?filter[frequency]=DAILY&filter[reportDate]=%s&filter[reportSubType]=SUMMARY&filter[reportType]=SALES&filter[vendorNumber]=%s",
+				reportDate, vendorNumber),
+		})
+	} else {
+		probes = append(probes, accessProbe{name: "Sales reports", note: "skipped: no vendor number"})
+	}
+	
+	for i := range probes {
+		if probes[i].path == "" {
+			continue
+		}
+		resp, err := rawGet(ctx, apiClient, probes[i].path)
 		if err != nil {
-			return fmt.Errorf("failed to read private key: %w", err)
+			probes[i].note = err.Error()
+			continue
 		}
+		resp.Body.Close()
 		
-		// Create JWT config
-		jwtConfig := auth.JWTConfig{
-			KeyID:         cfg.Auth.KeyID,
-			IssuerID:      cfg.Auth.IssuerID,
-			PrivateKeyPEM: string(privateKeyData),
+		probes[i].status = resp.StatusCode
+		probes[i].allowed = resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNotFound
+		if resp.StatusCode == http.StatusForbidden {
+			probes[i].note = "forbidden for this key"
 		}
-		
-		// Generate token
-		token, err := auth.GenerateToken(jwtConfig)
-		if err != nil {
-			return fmt.Errorf("failed to generate JWT token: %w", err)
+	}
+	
+	return probes
+}
+
+// rawGet makes an authenticated GET and returns the response whatever its
+// status, unlike Client.Get which turns error statuses into errors
+func rawGet(ctx context.Context, apiClient *client.Client, path string) (*http.Response, error) {
+	req, err := apiClient.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return apiClient.Do(req)
+}
+
+// inferRole guesses the key's role from what it can access
+func inferRole(probes []accessProbe) string {
+	allowed := make(map[string]bool)
+	for _, probe := range probes {
+		allowed[probe.name] = probe.allowed
+	}
+	
+	switch {
+	case allowed["Users"]:
+		return "Admin"
+	case allowed["Sales reports"]:
+		return "Finance or Sales"
+	default:
+		return "Developer, App Manager, Marketing or Customer Support"
+	}
+}
+
+// clockSkew returns how far the local clock is ahead of the server's
+func clockSkew(dateHeader string, local time.Time) (time.Duration, bool) {
+	if dateHeader == "" {
+		return 0, false
+	}
+	server, err := http.ParseTime(dateHeader)
+	if err != nil {
+		return 0, false
+	}
+	return local.Sub(server), true
+}
+
+// formatSkew describes a clock offset in words
+func formatSkew(skew time.Duration) string {
+	// The Date header only has second precision
+	if absDuration(skew) < 2*time.Second {
+		return "in sync"
+	}
+	skew = skew.Round(time.Second)
+	switch {
+	case skew > 0:
+		return fmt.Sprintf("%s ahead", skew)
+	default:
+		return fmt.Sprintf("%s behind", -skew)
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func runAuthSetup(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	fmt.Println(colorBold + "🔐 App Store Connect Credentials" + colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Println("\nCreate a key under Users and Access → Integrations → App Store Connect API,")
+	fmt.Println("then download its .p8 file. Press Enter on an empty prompt to cancel.")
+	fmt.Println()
+	
+	// Private key
+	var keyPath string
+	for {
+		keyPath = expandPath(askString("Path to your .p8 file", cfg.Auth.PrivateKeyPath))
+		if keyPath == "" {
+			fmt.Println("\nSetup cancelled.")
+			return nil
 		}
 		
-		fmt.Println("Authentication successful!")
-		fmt.Println("JWT token generated:")
-		fmt.Printf("%s...\n", token[:40])
+		data, err := os.ReadFile(keyPath)
+		if err == nil {
+			err = auth.ValidatePrivateKey(string(data))
+		}
+		if err == nil {
+			break
+		}
+		fmt.Printf("%s✗ %v%s\n", colorRed, err, colorReset)
+	}
+	
+	if info, err := os.Stat(keyPath); err == nil && info.Mode().Perm()&0o077 != 0 {
+		if err := os.Chmod(keyPath, 0o600); err != nil {
+			return fmt.Errorf("failed to restrict private key permissions: %w", err)
+		}
+		fmt.Println(colorGray + "Restricted the key file to your user (chmod 600)." + colorReset)
+	}
+	
+	// Apple names downloaded keys AuthKey_<KEY ID>.p8
+	defaultKeyID := cfg.Auth.KeyID
+	if name := strings.TrimSuffix(filepath.Base(keyPath), filepath.Ext(keyPath)); strings.HasPrefix(name, "AuthKey_") {
+		defaultKeyID = strings.TrimPrefix(name, "AuthKey_")
+	}
+	
+	// Key IDs are uppercase, but accept them typed in lowercase
+	keyID := strings.ToUpper(askValid("Key ID", defaultKeyID, func(value string) error {
+		return auth.ValidateKeyID(strings.ToUpper(value))
+	}))
+	if keyID == "" {
+		fmt.Println("\nSetup cancelled.")
 		return nil
-	},
+	}
+	
+	defaultKeyType := auth.KeyTypeTeam
+	if cfg.Auth.IsIndividual() {
+		defaultKeyType = auth.KeyTypeIndividual
+	}
+	keyType := strings.ToLower(askValid("Key type (team or individual)", defaultKeyType, func(value string) error {
+		switch strings.ToLower(value) {
+		case auth.KeyTypeTeam, auth.KeyTypeIndividual:
+			return nil
+		}
+		return fmt.Errorf("enter team or individual")
+	}))
+	
+	// Individual keys have no issuer
+	issuerID := ""
+	if keyType == auth.KeyTypeTeam {
+		issuerID = askValid("Issuer ID", cfg.Auth.IssuerID, auth.ValidateIssuerID)
+		if issuerID == "" {
+			fmt.Println("\nSetup cancelled.")
+			return nil
+		}
+	}
+	
+	cfg.Auth.KeyID = keyID
+	cfg.Auth.IssuerID = strings.ToLower(issuerID)
+	cfg.Auth.KeyType = ""
+	if keyType == auth.KeyTypeIndividual {
+		cfg.Auth.KeyType = auth.KeyTypeIndividual
+	}
+	cfg.Auth.PrivateKeyPath = keyPath
+	cfg.Auth.KeySource = ""
+	cfg.Auth.KeyRef = ""
+	
+	configPath, err := config.Save(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	
+	fmt.Println("\n" + colorGreen + "✅ Credentials saved to: " + configPath + colorReset)
+	fmt.Println(colorGray + "Tip: move the key into your keyring with 'pomme auth import-key " + keyPath + "'" + colorReset)
+	
+	if askYesNo("\nTest the credentials against the API now?", true) {
+		fmt.Println()
+		return runAuthTest(cmd, args)
+	}
+	
+	return nil
 }
 
-var authSetupCmd = &cobra.Command{
-	Use:   "setup",
-	Short: "Set up authentication credentials",
-	Long:  `Interactive setup for App Store Connect API credentials.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// TODO: Implement interactive credential setup
-		fmt.Println("Interactive setup will be implemented in a future version.")
-		fmt.Println("For now, please edit your config file manually.")
-		return nil
-	},
+// askValid prompts until the answer passes validation. An empty answer with
+// no default returns "".
+func askValid(prompt, defaultValue string, validate func(string) error) string {
+	for {
+		answer := askString(prompt, defaultValue)
+		if answer == "" {
+			return ""
+		}
+		if err := validate(answer); err != nil {
+			fmt.Printf("%s✗ %v%s\n", colorRed, err, colorReset)
+			continue
+		}
+		return answer
+	}
+}
+
+// expandPath expands a leading ~ to the user's home directory
+func expandPath(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print a signed API token",
+	Long: `Signs a JWT for the App Store Connect API and prints it, for use with curl
+or other tools. Tokens can be restricted to specific requests with --scope and
+live at most 20 minutes.`,
+	Example: `  curl -H "Authorization: Bearer $(pomme auth token)" https://api.appstoreconnect.apple.com/v1/apps
+  pomme auth token --decode
+  pomme auth token --ttl 5m --scope "GET /v1/salesReports?filter[vendorNumber]=12345678"`,
+	RunE: runAuthToken,
+}
+
+var authImportKeyCmd = &cobra.Command{
+	Use:   "import-key <path-to-p8>",
+	Short: "Move a private key into a secret store",
+	Long: `Imports an App Store Connect .p8 key into the chosen store and points the
+config at it. Stores: keyring (Secret Service via secret-tool), pass, or file
+(copied into the config directory with owner-only permissions).`,
+	Example: `  pomme auth import-key ~/Downloads/AuthKey_ABC123DEFG.p8 --to keyring
+  pomme auth import-key AuthKey_ABC123DEFG.p8 --to pass --ref asc/pomme --remove`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAuthImportKey,
 }
 
 func init() {
 	authCmd.AddCommand(authTestCmd)
 	authCmd.AddCommand(authSetupCmd)
+	authCmd.AddCommand(authImportKeyCmd)
+	authCmd.AddCommand(authTokenCmd)
+	
+	authTokenCmd.Flags().StringArray("scope", nil, "Restrict the token to a request, e.g. \"GET /v1/apps\" (repeatable; default from config)")
+	authTokenCmd.Flags().Duration("ttl", 0, "Token lifetime, at most 20m (default from config, or 20m)")
+	authTokenCmd.Flags().Bool("decode", false, "Print the token's header and claims as JSON instead of the token")
+	
+	authImportKeyCmd.Flags().String("to", auth.SourceKeyring, "Destination store (keyring, pass, file)")
+	authImportKeyCmd.Flags().String("ref", "", "Entry name or file path in the store (default: derived from the key ID)")
+	authImportKeyCmd.Flags().Bool("remove", false, "Delete the original .p8 file after importing")
+}
+
+func runAuthImportKey(cmd *cobra.Command, args []string) error {
+	keyPath := args[0]
+	to := strings.ToLower(mustGetString(cmd, "to"))
+	ref := mustGetString(cmd, "ref")
+	
+	switch to {
+	case auth.SourceKeyring, auth.SourcePass, auth.SourceFile:
+	default:
+		return fmt.Errorf("can't import into %q (valid: keyring, pass, file)", to)
+	}
+	
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+	if _, err := auth.ParsePrivateKey(string(data)); err != nil {
+		return fmt.Errorf("%s is not a valid App Store Connect key: %w", keyPath, err)
+	}
+	
+	// Apple names downloaded keys AuthKey_<KEY ID>.p8
+	keyID := cfg.Auth.KeyID
+	if name := strings.TrimSuffix(filepath.Base(keyPath), filepath.Ext(keyPath)); strings.HasPrefix(name, "AuthKey_") {
+		keyID = strings.TrimPrefix(name, "AuthKey_")
+	}
+	
+	if ref == "" {
+		switch to {
+		case auth.SourceFile:
+			dir, err := config.DefaultDir()
+			if err != nil {
+				return err
+			}
+			ref = filepath.Join(dir, filepath.Base(keyPath))
+		default:
+			if keyID == "" {
+				return fmt.Errorf("can't derive an entry name without a key ID; pass --ref")
+			}
+			ref = auth.DefaultKeyRef(to, keyID)
+		}
+	}
+	
+	source, err := auth.NewKeySource(to, ref)
+	if err != nil {
+		return err
+	}
+	store := source.(auth.KeyStore)
+	
+	// Importing a file onto itself leaves the only copy at keyPath
+	inPlace := false
+	if file, ok := source.(*auth.FileKeySource); ok {
+		inPlace = file.SameFile(keyPath)
+	}
+	
+	ctx := context.Background()
+	if err := store.StoreKey(ctx, string(data)); err != nil {
+		return err
+	}
+	
+	// Read it back so a broken store is caught before the config changes
+	stored, err := source.PrivateKey(ctx)
+	if err != nil {
+		return fmt.Errorf("key was stored but can't be read back: %w", err)
+	}
+	if strings.TrimSpace(stored) != strings.TrimSpace(string(data)) {
+		return fmt.Errorf("key read back from %s doesn't match the imported key", source)
+	}
+	
+	cfg.Auth.KeySource = to
+	cfg.Auth.KeyRef = ref
+	if to == auth.SourceFile {
+		cfg.Auth.PrivateKeyPath = ref
+	} else {
+		cfg.Auth.PrivateKeyPath = ""
+	}
+	if cfg.Auth.KeyID == "" {
+		cfg.Auth.KeyID = keyID
+	}
+	
+	configPath, err := config.Save(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	
+	fmt.Printf("%s✅ Imported key into %s%s\n", colorGreen, source, colorReset)
+	fmt.Printf("   Config updated: %s\n", configPath)
+	
+	remove := mustGetBool(cmd, "remove")
+	switch {
+	case remove && inPlace:
+		fmt.Printf("   Kept %s: it's the file the key was imported to\n", keyPath)
+	case remove:
+		if err := os.Remove(keyPath); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", keyPath, err)
+		}
+		fmt.Printf("   Removed %s\n", keyPath)
+	case to != auth.SourceFile:
+		fmt.Printf("%s   The original file is still at %s; delete it once you've confirmed everything works.%s\n",
+			colorGray, keyPath, colorReset)
+	}
+	
+	return nil
+}
+
+func runAuthToken(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	if scopes, _ := cmd.Flags().GetStringArray("scope"); len(scopes) > 0 {
+		cfg.Auth.Scopes = scopes
+	}
+	if ttl, _ := cmd.Flags().GetDuration("ttl"); ttl != 0 {
+		cfg.Auth.TokenTTL = ttl
+	}
+	
+	// Fail on bad flags before touching the key store
+	if err := newJWTConfig(cfg, "").Validate(); err != nil {
+		return err
+	}
+	
+	privateKeyData, err := loadPrivateKey(cfg)
+	if err != nil {
+		return err
+	}
+	
+	token, err := auth.GenerateToken(newJWTConfig(cfg, privateKeyData))
+	if err != nil {
+		return fmt.Errorf("failed to generate JWT token: %w", err)
+	}
+	
+	if mustGetBool(cmd, "decode") {
+		header, claims, err := auth.DecodeToken(token)
+		if err != nil {
+			return err
+		}
+		return output.JSON(map[string]interface{}{
+			"header": header,
+			"claims": claims,
+		})
+	}
+	
+	fmt.Println(token)
+	return nil
+}
+
+// newJWTConfig builds the token settings for the configured credentials
+func newJWTConfig(cfg *config.Config, privateKeyPEM string) auth.JWTConfig {
+	return auth.JWTConfig{
+		KeyID:         cfg.Auth.KeyID,
+		IssuerID:      cfg.Auth.IssuerID,
+		KeyType:       cfg.Auth.KeyType,
+		PrivateKeyPEM: privateKeyPEM,
+		Expiration:    cfg.Auth.TokenTTL,
+		Scopes:        cfg.Auth.Scopes,
+	}
+}
+
+// newPommeClient creates an API client from the configured credentials. The
+// key is read from its source whenever a token is signed. Extra options are
+// applied last.
+func newPommeClient(cfg *config.Config, extra ...pomme.Option) (*pomme.Client, error) {
+	keySource, err := auth.KeySourceFromConfig(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	
+	jwtConfig := newJWTConfig(cfg, "")
+	jwtConfig.KeySource = keySource
+	if err := jwtConfig.Validate(); err != nil {
+		return nil, err
+	}
+	
+	opts := []pomme.Option{
+		pomme.WithTokenProvider(auth.NewJWTProvider(jwtConfig)),
+		pomme.WithBaseURL(cfg.API.BaseURL),
+		pomme.WithVendorNumber(cfg.Defaults.VendorNumber),
+	}
+	if os.Getenv("POMME_DEBUG") != "" {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		opts = append(opts, pomme.WithLogger(logger))
+	}
+	return pomme.New(append(opts, extra...)...)
+}
+
+// loadPrivateKey reads the configured private key from its key source
+func loadPrivateKey(cfg *config.Config) (string, error) {
+	source, err := auth.KeySourceFromConfig(cfg.Auth)
+	if err != nil {
+		return "", err
+	}
+	return source.PrivateKey(context.Background())
 }
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/marcusziade/pomme/internal/auth"
 	"github.com/marcusziade/pomme/internal/client"
 	"github.com/marcusziade/pomme/internal/config"
 	"github.com/spf13/cobra"
@@ -133,6 +134,14 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("private key file not found")
 	}
 
+	// Key files must not be readable by other users
+	if info, err := os.Stat(privateKeyPath); err == nil && info.Mode().Perm()&0o077 != 0 {
+		if err := os.Chmod(privateKeyPath, 0o600); err != nil {
+			return fmt.Errorf("failed to restrict private key permissions: %w", err)
+		}
+		fmt.Println(colorGray + "Restricted the key file to your user (chmod 600)." + colorReset)
+	}
+
 	fmt.Println("\n" + colorBold + "🔑 Step 3: Enter Your Credentials" + colorReset)
 	fmt.Println(strings.Repeat("─", 40))
 	fmt.Println()
@@ -221,13 +230,18 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	fmt.Println("\n" + colorBold + "Authentication:" + colorReset)
 	fmt.Printf("  Key ID: %s\n", maskString(cfg.Auth.KeyID))
 	fmt.Printf("  Issuer ID: %s\n", maskString(cfg.Auth.IssuerID))
-	fmt.Printf("  Private Key: %s\n", cfg.Auth.PrivateKeyPath)
 	
-	// Check if private key exists
-	if _, err := os.Stat(cfg.Auth.PrivateKeyPath); os.IsNotExist(err) {
-		fmt.Printf("               " + colorRed + "⚠️  File not found!" + colorReset + "\n")
+	// Check that the private key can be read from its source
+	source, err := auth.KeySourceFromConfig(cfg.Auth)
+	if err != nil {
+		fmt.Printf("  Private Key: " + colorRed + "⚠️  %v" + colorReset + "\n", err)
 	} else {
-		fmt.Printf("               " + colorGreen + "✓ File exists" + colorReset + "\n")
+		fmt.Printf("  Private Key: %s\n", source)
+		if _, err := source.PrivateKey(context.Background()); err != nil {
+			fmt.Printf("               " + colorRed + "⚠️  %v" + colorReset + "\n", err)
+		} else {
+			fmt.Printf("               " + colorGreen + "✓ Key readable" + colorReset + "\n")
+		}
 	}
 	
 	fmt.Println("\n" + colorBold + "Defaults:" + colorReset)
@@ -235,6 +249,9 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	if cfg.Defaults.VendorNumber != "" {
 		fmt.Printf("  Vendor Number: %s\n", maskString(cfg.Defaults.VendorNumber))
 	}
+	if cfg.Defaults.FiscalYearStart != "" {
+		fmt.Printf("  Fiscal Year Start: %s\n", cfg.Defaults.FiscalYearStart)
+	}
 	
 	fmt.Println("\n" + colorGray + "Run 'pomme config validate' to test your configuration." + colorReset)
 	
@@ -265,10 +282,10 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 	if cfg.Auth.KeyID == "" {
 		missing = append(missing, "Key ID")
 	}
-	if cfg.Auth.IssuerID == "" {
+	if cfg.Auth.IssuerID == "" && !cfg.Auth.IsIndividual() {
 		missing = append(missing, "Issuer ID")
 	}
-	if cfg.Auth.PrivateKeyPath == "" {
+	if cfg.Auth.PrivateKeyPath == "" && cfg.Auth.KeySource == "" && os.Getenv(auth.PrivateKeyEnv) == "" {
 		missing = append(missing, "Private Key Path")
 	}
 	
@@ -280,12 +297,16 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println(colorGreen + "✓" + colorReset)
 
-	// Check private key file
-	fmt.Print("Checking private key file... ")
-	if _, err := os.Stat(cfg.Auth.PrivateKeyPath); os.IsNotExist(err) {
+	// Check the private key can be read and parsed
+	fmt.Print("Checking private key... ")
+	privateKeyData, err := loadPrivateKey(cfg)
+	if err == nil {
+		_, err = auth.ParsePrivateKey(privateKeyData)
+	}
+	if err != nil {
 		fmt.Println(colorRed + "✗" + colorReset)
-		fmt.Printf("\nPrivate key not found: %s\n", cfg.Auth.PrivateKeyPath)
-		return fmt.Errorf("private key file not found")
+		fmt.Printf("\n%v\n", err)
+		return err
 	}
 	fmt.Println(colorGreen + "✓" + colorReset)
 
@@ -300,7 +321,7 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 
 	// Try to list apps as a simple test
 	ctx := context.Background()
-	resp, err := apiClient.Get(ctx, "/apps?limit=1")
+	resp, err := apiClient.Get(ctx, "/v1/apps?limit=1")
 	if err != nil {
 		fmt.Println(colorRed + "✗" + colorReset)
 		fmt.Printf("\nAPI request failed: %v\n", err)
@@ -394,9 +415,12 @@ func runConfigHelp(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// stdinReader is shared by the prompts so buffered input isn't lost between them
+var stdinReader = bufio.NewReader(os.Stdin)
+
 // Helper functions
 func askYesNo(prompt string, defaultYes bool) bool {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 	defaultStr := "y/N"
 	if defaultYes {
 		defaultStr = "Y/n"
@@ -414,7 +438,7 @@ func askYesNo(prompt string, defaultYes bool) bool {
 }
 
 func askString(prompt, defaultValue string) string {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 	
 	if defaultValue != "" {
 		fmt.Printf("%s [%s]: ", prompt, defaultValue)
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/api"
+	"github.com/marcusziade/pomme/internal/config"
+	"github.com/marcusziade/pomme/internal/exporter"
+	"github.com/marcusziade/pomme/internal/metrics"
+	"github.com/marcusziade/pomme/internal/server"
+	"github.com/marcusziade/pomme/internal/services/cache"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/pomme"
+	"github.com/spf13/cobra"
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Expose sales and ratings as Prometheus metrics",
+	Long: `Serves sales, ratings and API usage metrics at /metrics in the Prometheus text
+format, refreshing them from App Store Connect every --interval.
+
+Metrics:
+  pomme_sales_units{app_id,app,country}                  Latest DAILY report
+  pomme_sales_proceeds{app_id,app,country,currency}      Latest DAILY report
+  pomme_sales_report_date_seconds                        Day the report covers
+  pomme_reviews{app_id,app,territory}                    Recent reviews
+  pomme_review_rating_average{app_id,app,territory}      Recent average rating
+  pomme_app_reviews{app_id,app}                          Recent reviews, all territories
+  pomme_app_rating_average{app_id,app}                   Recent average rating, all territories
+  pomme_api_requests_total{method,endpoint,code}         API requests
+  pomme_api_errors_total{method,endpoint,reason}         Network, 4xx and 5xx failures
+  pomme_api_request_duration_seconds{method,endpoint}    API latency histogram
+  pomme_exporter_last_success_seconds{source}            Last successful refresh
+  pomme_exporter_refresh_duration_seconds{source}        Last refresh duration
+  pomme_exporter_refresh_failures_total{source}          Failed refreshes
+
+Ratings cover each app's 200 most recent reviews. The exporter stops
+gracefully on SIGINT or SIGTERM.`,
+	Example: `  # Scrape localhost:9464/metrics, refreshing every 30 minutes
+  pomme exporter --interval 30m
+
+  # Listen on all interfaces
+  pomme exporter --addr :9464`,
+	Args: cobra.NoArgs,
+	RunE: runExporter,
+}
+
+func init() {
+	RootCmd.AddCommand(exporterCmd)
+
+	exporterCmd.Flags().String("addr", "127.0.0.1:9464", "Address to listen on")
+	exporterCmd.Flags().Duration("interval", time.Hour, "How often to refresh from App Store Connect (at least 1m)")
+	exporterCmd.Flags().String("vendor", "", "Vendor number (overrides config)")
+	exporterCmd.Flags().Bool("quiet", false, "Don't log refreshes")
+}
+
+func runExporter(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Auth.KeyID == "" || (cfg.Auth.IssuerID == "" && !cfg.Auth.IsIndividual()) {
+		return fmt.Errorf("authentication not configured. Run 'pomme config init' first")
+	}
+
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+
+	registry := metrics.NewRegistry()
+	httpClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &api.ObservingTransport{Observer: metrics.NewAPIMetrics(registry)},
+	}
+
+	store := cache.NewMemoryCache()
+	client, err := newPommeClient(cfg, pomme.WithCache(store), pomme.WithHTTPClient(httpClient))
+	if err != nil {
+		return err
+	}
+
+	exporterConfig := exporter.Config{
+		Sales:        sales.NewService(client, store),
+		Reviews:      client.Reviews(),
+		Apps:         client.Apps(),
+		VendorNumber: getVendorNumber(cmd, cfg),
+		Registry:     registry,
+	}
+	if !mustGetBool(cmd, "quiet") {
+		exporterConfig.Logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+	exp := exporter.New(exporterConfig)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go exp.Run(ctx, interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+
+	addr := mustGetString(cmd, "addr")
+	fmt.Fprintf(os.Stderr, "📡 Serving metrics on http://%s/metrics (Ctrl+C to stop)\n", addr)
+	if err := server.Serve(ctx, addr, mux); err != nil {
+		return fmt.Errorf("exporter failed: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, "👋 Exporter stopped")
+	return nil
+}
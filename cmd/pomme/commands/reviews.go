@@ -10,8 +10,11 @@ import (
 
 	"github.com/marcusziade/pomme/internal/client"
 	"github.com/marcusziade/pomme/internal/config"
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/internal/output"
 	"github.com/marcusziade/pomme/internal/services/reviews"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
 	"github.com/spf13/cobra"
 )
 
@@ -26,15 +29,21 @@ var reviewsListCmd = &cobra.Command{
 	Use:     "list <app-id>",
 	Short:   "List customer reviews",
 	Long:    "Display customer reviews with filtering options",
+	Example: `  pomme reviews list 1234567890 --since 7d --max-rating 2
+  pomme reviews list 1234567890 --territory USA,GBR --has-response=false
+  pomme reviews list 1234567890 --text "crash|freez" --since 2025-03-01 --until 2025-03-31`,
 	Args:    cobra.ExactArgs(1),
 	RunE:    runReviewsList,
 }
 
 var reviewsSummaryCmd = &cobra.Command{
-	Use:     "summary <app-id>",
+	Use:     "summary [app-id]",
 	Short:   "Show review summary and statistics",
-	Long:    "Display aggregated review statistics including ratings distribution and territory breakdown",
-	Args:    cobra.ExactArgs(1),
+	Long:    "Display aggregated review statistics including ratings distribution and territory breakdown, for one app or for your whole portfolio",
+	Example: `  pomme reviews summary 1234567890
+  pomme reviews summary --all --sort rating
+  pomme reviews summary --apps com.example.foo,com.example.bar --output csv`,
+	Args:    cobra.MaximumNArgs(1),
 	RunE:    runReviewsSummary,
 }
 
@@ -46,12 +55,69 @@ var reviewsRespondCmd = &cobra.Command{
 	RunE:    runReviewsRespond,
 }
 
+var reviewsByVersionCmd = &cobra.Command{
+	Use:     "by-version <app-id>",
+	Short:   "Correlate ratings with app versions",
+	Long:    "Bucket reviews into the release window of each App Store version and flag significant rating drops between consecutive versions",
+	Example: `  pomme reviews by-version 1234567890
+  pomme reviews by-version 1234567890 --platform MAC_OS --reviews 2000`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runReviewsByVersion,
+}
+
+var reviewsTriageCmd = &cobra.Command{
+	Use:     "triage <app-id>",
+	Short:   "Prioritized queue of reviews to answer",
+	Long:    `Rank reviews by how urgently they deserve a response. Each review is scored
+on its rating, recency, length, whether it is unanswered and how much revenue
+its territory brings in, based on the latest monthly sales report.`,
+	Example: `  pomme reviews triage 1234567890
+  pomme reviews triage 1234567890 --limit 50 --include-answered
+  pomme reviews triage 1234567890 --no-sales --output json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runReviewsTriage,
+}
+
+var reviewsExportCmd = &cobra.Command{
+	Use:     "export <app-id>",
+	Short:   "Export reviews to a file",
+	Long:    "Export reviews with full bodies and developer responses as CSV, JSON, NDJSON, Markdown or a self-contained HTML report",
+	Example: `  pomme reviews export 1234567890 --format csv > reviews.csv
+  pomme reviews export 1234567890 --format html --since 7d --file weekly.html
+  pomme reviews export 1234567890 --format ndjson --since 2025-01-01 --until 2025-03-31`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runReviewsExport,
+}
+
 var (
 	// Flags
-	reviewsRating    int
-	reviewsLimit     int
-	reviewsSort      string
-	reviewsVerbose   bool
+	reviewsRating            int
+	reviewsLimit             int
+	reviewsSort              string
+	reviewsVerbose           bool
+	reviewsPlatform          string
+	reviewsMaxFetch          int
+	reviewsAll               bool
+	reviewsApps              []string
+	reviewsOrderBy           string
+	reviewsParallel          int
+	reviewsSince             string
+	reviewsUntil             string
+	reviewsTerritory         []string
+	reviewsMinRating         int
+	reviewsMaxRating         int
+	reviewsAnswered          bool
+	reviewsText              string
+	reviewsNickname          string
+	reviewsPool              int
+	reviewsAllStates         bool
+	reviewsNoSales           bool
+	reviewsFormat            string
+	reviewsFile              string
+	reviewsFrom              string
+	reviewsTo                string
+	reviewsExportTerritories []string
+	reviewsRegion            []string
 )
 
 func init() {
@@ -59,12 +125,48 @@ func init() {
 	reviewsCmd.AddCommand(reviewsListCmd)
 	reviewsCmd.AddCommand(reviewsSummaryCmd)
 	reviewsCmd.AddCommand(reviewsRespondCmd)
+	reviewsCmd.AddCommand(reviewsByVersionCmd)
+	reviewsCmd.AddCommand(reviewsTriageCmd)
+	reviewsCmd.AddCommand(reviewsExportCmd)
 	
 	// Add flags for list command
 	reviewsListCmd.Flags().IntVar(&reviewsRating, "rating", 0, "Filter by rating (1-5)")
 	reviewsListCmd.Flags().IntVar(&reviewsLimit, "limit", 20, "Number of reviews to display")
-	reviewsListCmd.Flags().StringVar(&reviewsSort, "sort", "recent", "Sort order (recent, critical, helpful)")
+	reviewsListCmd.Flags().StringVar(&reviewsSort, "sort", "recent", "Sort order (recent, oldest, highest, lowest, critical, helpful)")
 	reviewsListCmd.Flags().BoolVar(&reviewsVerbose, "verbose", false, "Show full review content")
+	reviewsListCmd.Flags().StringVar(&reviewsSince, "since", "", "Only reviews on or after this date (YYYY-MM-DD or relative, e.g. 7d, 2w, 3m)")
+	reviewsListCmd.Flags().StringVar(&reviewsUntil, "until", "", "Only reviews on or before this date (YYYY-MM-DD or relative)")
+	reviewsListCmd.Flags().StringSliceVar(&reviewsTerritory, "territory", nil, "Filter by territories (e.g. USA,GBR)")
+	reviewsListCmd.Flags().StringSliceVar(&reviewsRegion, "region", nil, "Filter by regions (NA, LATAM, EMEA, APAC, EU, EUROZONE, apple:<finance region>)")
+	reviewsListCmd.Flags().IntVar(&reviewsMinRating, "min-rating", 0, "Minimum rating (1-5)")
+	reviewsListCmd.Flags().IntVar(&reviewsMaxRating, "max-rating", 0, "Maximum rating (1-5)")
+	reviewsListCmd.Flags().BoolVar(&reviewsAnswered, "has-response", false, "Only reviews with a response (--has-response=false for unanswered)")
+	reviewsListCmd.Flags().StringVar(&reviewsText, "text", "", "Regular expression matched against title and body (case-insensitive)")
+	reviewsListCmd.Flags().StringVar(&reviewsNickname, "nickname", "", "Filter by reviewer nickname (case-insensitive substring)")
+	
+	// Add flags for summary command
+	reviewsSummaryCmd.Flags().BoolVar(&reviewsAll, "all", false, "Summarize every app in your account")
+	reviewsSummaryCmd.Flags().StringSliceVar(&reviewsApps, "apps", nil, "Summarize these apps (bundle IDs or app IDs)")
+	reviewsSummaryCmd.Flags().StringVar(&reviewsOrderBy, "sort", "name", "Portfolio sort order ("+strings.Join(reviews.PortfolioSortFields, ", ")+")")
+	reviewsSummaryCmd.Flags().IntVar(&reviewsParallel, "concurrency", 4, "Maximum apps fetched in parallel")
+	
+	// Add flags for by-version command
+	reviewsByVersionCmd.Flags().StringVar(&reviewsPlatform, "platform", "IOS", "Version platform (IOS, MAC_OS, TV_OS, VISION_OS)")
+	reviewsByVersionCmd.Flags().IntVar(&reviewsMaxFetch, "reviews", 1000, "Maximum number of recent reviews to analyze")
+	
+	// Add flags for triage command
+	reviewsTriageCmd.Flags().IntVar(&reviewsLimit, "limit", 20, "Number of reviews to show")
+	reviewsTriageCmd.Flags().IntVar(&reviewsPool, "reviews", 500, "Maximum number of recent reviews to rank")
+	reviewsTriageCmd.Flags().BoolVar(&reviewsAllStates, "include-answered", false, "Include reviews that already have a response")
+	reviewsTriageCmd.Flags().BoolVar(&reviewsNoSales, "no-sales", false, "Don't weight territories by sales")
+	
+	// Add flags for export command
+	reviewsExportCmd.Flags().StringVar(&reviewsFormat, "format", "csv", "Export format (csv, json, ndjson, md, html)")
+	reviewsExportCmd.Flags().StringVar(&reviewsFrom, "since", "30d", "Only reviews on or after this date (YYYY-MM-DD or relative, e.g. 7d); empty for all")
+	reviewsExportCmd.Flags().StringVar(&reviewsTo, "until", "", "Only reviews on or before this date (YYYY-MM-DD or relative)")
+	reviewsExportCmd.Flags().StringSliceVar(&reviewsExportTerritories, "territory", nil, "Filter by territories (e.g. USA,GBR)")
+	reviewsExportCmd.Flags().StringSliceVar(&reviewsRegion, "region", nil, "Filter by regions (NA, LATAM, EMEA, APAC, EU, EUROZONE, apple:<finance region>)")
+	reviewsExportCmd.Flags().StringVarP(&reviewsFile, "file", "f", "", "Write to this file instead of stdout")
 }
 
 func runReviewsList(cmd *cobra.Command, args []string) error {
@@ -84,14 +186,48 @@ func runReviewsList(cmd *cobra.Command, args []string) error {
 	
 	svc := reviews.NewService(apiClient)
 	
-	fmt.Printf("📱 Fetching reviews for app %s...\n\n", appID)
+	outputFormat := mustGetString(cmd, "output")
+	if outputFormat == string(output.FormatTable) {
+		fmt.Printf("📱 Fetching reviews for app %s...\n\n", appID)
+	}
 	
+	territories, err := reviewTerritories(reviewsTerritory, reviewsRegion)
+	if err != nil {
+		return err
+	}
+
 	// Create filter
 	filter := models.ReviewFilter{
-		AppID:     appID,
-		Rating:    reviewsRating,
-		Limit:     reviewsLimit,
-		Sort:      reviewsSort,
+		AppID:       appID,
+		Territories: territories,
+		Rating:      reviewsRating,
+		MinRating:   reviewsMinRating,
+		MaxRating:   reviewsMaxRating,
+		Text:        reviewsText,
+		Nickname:    reviewsNickname,
+		Limit:       reviewsLimit,
+		Sort:        reviewsSort,
+	}
+	
+	if err := filter.Validate(); err != nil {
+		return fmt.Errorf("invalid rating filter: %w", err)
+	}
+	
+	if cmd.Flags().Changed("has-response") {
+		hasResponse := reviewsAnswered
+		filter.HasResponse = &hasResponse
+	}
+	
+	now := time.Now()
+	if reviewsSince != "" {
+		if filter.StartDate, err = reviews.ParseTime(reviewsSince, now, false); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if reviewsUntil != "" {
+		if filter.EndDate, err = reviews.ParseTime(reviewsUntil, now, true); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
 	}
 	
 	// Fetch reviews
@@ -101,13 +237,29 @@ func runReviewsList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch reviews: %w", err)
 	}
 	
-	// Display reviews
-	displayReviews(reviewList, reviewsVerbose)
+	switch output.Format(outputFormat) {
+	case output.FormatTable:
+		displayReviews(reviewList, reviewsVerbose)
+	case output.FormatJSON:
+		return output.JSON(reviewList)
+	default:
+		rows := make([]models.ReviewExportRow, len(reviewList))
+		for i, review := range reviewList {
+			rows[i] = models.NewReviewExportRow(review)
+		}
+		return output.NewFormatter(output.Format(outputFormat), os.Stdout).Format(rows)
+	}
 	
 	return nil
 }
 
 func runReviewsSummary(cmd *cobra.Command, args []string) error {
+	if reviewsAll || len(reviewsApps) > 0 {
+		return runPortfolioSummary(cmd)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("specify an app ID, --apps or --all")
+	}
 	appID := args[0]
 	
 	// Load config
@@ -139,6 +291,88 @@ func runReviewsSummary(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runPortfolioSummary(cmd *cobra.Command) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create client and service
+	apiClient, err := client.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	
+	svc := reviews.NewService(apiClient)
+	
+	outputFormat := mustGetString(cmd, "output")
+	ctx := context.Background()
+	
+	// Reject a bad sort field before making any requests
+	if err := reviews.SortPortfolio(nil, reviewsOrderBy); err != nil {
+		return err
+	}
+	
+	// Resolve the apps to summarize
+	apps, err := resolvePortfolioApps(ctx, cfg, reviewsApps)
+	if err != nil {
+		return err
+	}
+	
+	if outputFormat == string(output.FormatTable) {
+		fmt.Printf("📊 Fetching reviews for %d apps...\n\n", len(apps))
+	}
+	
+	stats := svc.GetPortfolioSummary(ctx, apps, reviewsParallel)
+	if err := reviews.SortPortfolio(stats, reviewsOrderBy); err != nil {
+		return err
+	}
+	
+	if outputFormat != string(output.FormatTable) {
+		return output.NewFormatter(output.Format(outputFormat), os.Stdout).Format(stats)
+	}
+	
+	displayPortfolioSummary(stats)
+	
+	return nil
+}
+
+// resolvePortfolioApps lists the account's apps and keeps those matching the
+// given bundle IDs or app IDs, or all of them when none are given
+func resolvePortfolioApps(ctx context.Context, cfg *config.Config, wanted []string) ([]models.App, error) {
+	appClient, err := newPommeClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	
+	allApps, err := appClient.ListApps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list apps: %w", err)
+	}
+	
+	if len(wanted) == 0 {
+		return allApps, nil
+	}
+	
+	var apps []models.App
+	for _, id := range wanted {
+		found := false
+		for _, app := range allApps {
+			if app.ID == id || app.Attributes.BundleID == id {
+				apps = append(apps, app)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("app not found in your account: %s", id)
+		}
+	}
+	
+	return apps, nil
+}
+
 func runReviewsRespond(cmd *cobra.Command, args []string) error {
 	reviewID := args[0]
 	responseText := args[1]
@@ -170,6 +404,211 @@ func runReviewsRespond(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runReviewsByVersion(cmd *cobra.Command, args []string) error {
+	appID := args[0]
+	
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create client and service
+	apiClient, err := client.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	
+	svc := reviews.NewService(apiClient)
+	
+	jsonOutput := mustGetString(cmd, "output") == "json"
+	if !jsonOutput {
+		fmt.Printf("🏷️  Correlating reviews with versions for app %s...\n\n", appID)
+	}
+	
+	// Fetch and bucket reviews
+	ctx := context.Background()
+	stats, err := svc.GetReviewsByVersion(ctx, appID, strings.ToUpper(reviewsPlatform), reviewsMaxFetch)
+	if err != nil {
+		return fmt.Errorf("failed to analyze reviews by version: %w", err)
+	}
+	
+	if jsonOutput {
+		return output.JSON(stats)
+	}
+	
+	displayVersionStats(stats)
+	
+	return nil
+}
+
+func runReviewsTriage(cmd *cobra.Command, args []string) error {
+	appID := args[0]
+	
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create client and service
+	apiClient, err := client.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	
+	svc := reviews.NewService(apiClient)
+	
+	jsonOutput := mustGetString(cmd, "output") == "json"
+	if !jsonOutput {
+		fmt.Printf("🚨 Building triage queue for app %s...\n\n", appID)
+	}
+	
+	ctx := context.Background()
+	
+	filter := models.ReviewFilter{
+		AppID: appID,
+		Limit: reviewsPool,
+		Sort:  "recent",
+	}
+	if !reviewsAllStates {
+		unanswered := false
+		filter.HasResponse = &unanswered
+	}
+	
+	reviewList, err := svc.GetReviews(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	
+	// Sales only sharpen the ranking, so a failure here isn't fatal
+	var weights map[string]float64
+	if !reviewsNoSales {
+		weights, err = triageTerritoryWeights(ctx, cmd, appID)
+		if err != nil && !jsonOutput {
+			fmt.Printf("%s⚠️  Ranking without sales data: %v%s\n\n", colorYellow, err, colorReset)
+		}
+	}
+	
+	ranked := reviews.NewRanker(weights).Rank(reviewList)
+	if len(ranked) > reviewsLimit {
+		ranked = ranked[:reviewsLimit]
+	}
+	
+	if jsonOutput {
+		return output.JSON(ranked)
+	}
+	
+	displayTriage(ranked, weights != nil)
+	
+	return nil
+}
+
+func runReviewsExport(cmd *cobra.Command, args []string) error {
+	appID := args[0]
+	
+	format, err := reviews.ParseExportFormat(reviewsFormat)
+	if err != nil {
+		return err
+	}
+	
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create client and service
+	apiClient, err := client.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	
+	svc := reviews.NewService(apiClient)
+	
+	now := time.Now()
+	report := reviews.ExportReport{
+		AppID:       appID,
+		GeneratedAt: now,
+	}
+	if reviewsFrom != "" {
+		if report.Since, err = reviews.ParseTime(reviewsFrom, now, false); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if reviewsTo != "" {
+		if report.Until, err = reviews.ParseTime(reviewsTo, now, true); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	
+	territories, err := reviewTerritories(reviewsExportTerritories, reviewsRegion)
+	if err != nil {
+		return err
+	}
+
+	// Fetch every review in the period, up to the scan limit
+	ctx := context.Background()
+	report.Reviews, err = svc.GetReviews(ctx, models.ReviewFilter{
+		AppID:       appID,
+		Territories: territories,
+		StartDate:   report.Since,
+		EndDate:     report.Until,
+		Limit:       reviews.MaxExportReviews,
+		Sort:        "recent",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	
+	if reviewsFile == "" {
+		return reviews.Export(os.Stdout, format, report)
+	}
+	
+	file, err := os.Create(reviewsFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+	
+	if err := reviews.Export(file, format, report); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	
+	fmt.Fprintf(os.Stderr, "✅ Exported %d reviews to %s\n", len(report.Reviews), reviewsFile)
+	return nil
+}
+
+// triageTerritoryWeights weights review territories by the app's sales in
+// the latest available month
+func triageTerritoryWeights(ctx context.Context, cmd *cobra.Command, appID string) (map[string]float64, error) {
+	cfg, service, err := setupSalesService(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Defaults.VendorNumber == "" {
+		return nil, fmt.Errorf("no vendor number configured")
+	}
+	
+	report, err := service.GetReport(ctx, sales.ReportOptions{
+		Period:       models.ReportFrequencyMonthly,
+		Date:         calculateLatestAvailableMonth(),
+		ReportType:   models.ReportTypeSales,
+		VendorNumber: cfg.Defaults.VendorNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	weights := reviews.TerritoryWeightsFromSales(report, appID)
+	if weights == nil {
+		return nil, fmt.Errorf("no sales found for app %s", appID)
+	}
+	return weights, nil
+}
 
 // displayReviews shows reviews in a formatted table
 func displayReviews(reviews []models.CustomerReview, verbose bool) {
@@ -294,8 +733,9 @@ func displayReviewSummary(summary *models.ReviewSummary) {
 		
 		for i := 0; i < limit; i++ {
 			territory := summary.TerritoryStats[i]
-			fmt.Fprintf(w, "  %s\t%d\t%.1f\n",
+			fmt.Fprintf(w, "  %s %s\t%d\t%.1f\n",
 				territory.Territory,
+				countries.Name(territory.Territory),
 				territory.ReviewCount,
 				territory.AverageRating,
 			)
@@ -331,4 +771,195 @@ func displayReviewSummary(summary *models.ReviewSummary) {
 	}
 	
 	fmt.Printf("\n%sGenerated: %s%s\n", colorGray, time.Now().Format("2006-01-02 15:04:05"), colorReset)
-}
\ No newline at end of file
+}
+
+// displayVersionStats shows ratings per version, newest first
+func displayVersionStats(stats []models.VersionReviewStats) {
+	fmt.Printf("%s🏷️  Ratings by Version%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("═", 80))
+	
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  Version\tReleased\tReviews\tAvg Rating\tChange\tTop Topics\n")
+	fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+		strings.Repeat("─", 7),
+		strings.Repeat("─", 10),
+		strings.Repeat("─", 7),
+		strings.Repeat("─", 10),
+		strings.Repeat("─", 6),
+		strings.Repeat("─", 10))
+	
+	flagged := 0
+	for i := len(stats) - 1; i >= 0; i-- {
+		stat := stats[i]
+		
+		avg := "-"
+		if stat.ReviewCount > 0 {
+			avg = fmt.Sprintf("%.2f", stat.AverageRating)
+		}
+		
+		change := "-"
+		if stat.RatingChange != 0 {
+			change = fmt.Sprintf("%+.2f", stat.RatingChange)
+		}
+		if stat.SignificantDrop {
+			// No color codes here, they would break tabwriter alignment
+			change += " ⚠️"
+			flagged++
+		}
+		
+		topics := make([]string, 0, len(stat.TopTopics))
+		for _, topic := range stat.TopTopics {
+			topics = append(topics, topic.Term)
+		}
+		
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%s\t%s\t%s\n",
+			stat.Version,
+			stat.ReleaseDate.Format("2006-01-02"),
+			stat.ReviewCount,
+			avg,
+			change,
+			strings.Join(topics, ", "),
+		)
+	}
+	w.Flush()
+	
+	if flagged > 0 {
+		fmt.Printf("\n%s⚠️  %d version(s) with a statistically significant rating drop (p < 0.05)%s\n",
+			colorRed, flagged, colorReset)
+	}
+	
+	fmt.Printf("\n%sGenerated: %s%s\n", colorGray, time.Now().Format("2006-01-02 15:04:05"), colorReset)
+}
+
+// displayTriage shows the triage queue, most urgent first
+func displayTriage(ranked []models.PrioritizedReview, salesWeighted bool) {
+	if len(ranked) == 0 {
+		fmt.Println("No reviews need attention. 🎉")
+		return
+	}
+	
+	fmt.Printf("%s🚨 Review Triage Queue%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 80))
+	
+	for i, item := range ranked {
+		review := item.Review
+		stars := strings.Repeat("⭐", review.Attributes.Rating)
+		emptyStars := strings.Repeat("☆", 5-review.Attributes.Rating)
+		
+		status := ""
+		if review.HasResponse() {
+			status = fmt.Sprintf("  %s(answered)%s", colorGray, colorReset)
+		}
+		
+		fmt.Printf("\n%s%2d. %s%5.1f%s  %s%s  %s%s%s  %s%s%s  %s%s\n",
+			colorBold, i+1, colorYellow, item.Score, colorReset,
+			stars, emptyStars,
+			colorCyan, review.Attributes.ReviewerNickname, colorReset,
+			colorGray, review.Attributes.Territory, colorReset,
+			review.Attributes.CreatedDate.Format("2006-01-02"),
+			status,
+		)
+		
+		if review.Attributes.Title != "" {
+			fmt.Printf("    %s%s%s\n", colorBold, review.Attributes.Title, colorReset)
+		}
+		
+		body := review.Attributes.Body
+		if len([]rune(body)) > 160 {
+			body = string([]rune(body)[:160]) + "..."
+		}
+		fmt.Printf("    %s\n", body)
+		fmt.Printf("    %sReview ID: %s%s\n", colorGray, review.ID, colorReset)
+	}
+	
+	weighting := "not weighted by sales"
+	if salesWeighted {
+		weighting = "territories weighted by latest monthly sales"
+	}
+	fmt.Printf("\n%sScore 0-100 from rating, recency, length, response status; %s%s\n",
+		colorGray, weighting, colorReset)
+	fmt.Printf("%sRespond with: pomme reviews respond <review-id> \"...\"%s\n", colorGray, colorReset)
+}
+
+// displayPortfolioSummary shows one row of review metrics per app
+func displayPortfolioSummary(stats []models.AppReviewStats) {
+	fmt.Printf("%s📊 Portfolio Review Dashboard%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("═", 80))
+	
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  App\tAvg Rating\t7 Days\t30 Days\tUnanswered\tWorst Territory\n")
+	fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+		strings.Repeat("─", 20),
+		strings.Repeat("─", 10),
+		strings.Repeat("─", 6),
+		strings.Repeat("─", 7),
+		strings.Repeat("─", 10),
+		strings.Repeat("─", 15))
+	
+	var failed []models.AppReviewStats
+	for _, stat := range stats {
+		if stat.Error != "" {
+			failed = append(failed, stat)
+			continue
+		}
+		
+		name := stat.Name
+		if len(name) > 30 {
+			name = name[:27] + "..."
+		}
+		
+		avg := "-"
+		if stat.Reviews > 0 {
+			avg = fmt.Sprintf("%.2f", stat.AverageRating)
+		}
+		
+		worst := "-"
+		if stat.WorstTerritory != "" {
+			worst = fmt.Sprintf("%s (%.1f)", stat.WorstTerritory, stat.WorstTerritoryRating)
+		}
+		
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%d\t%d\t%s\n",
+			name,
+			avg,
+			stat.Last7Days,
+			stat.Last30Days,
+			stat.Unanswered,
+			worst,
+		)
+	}
+	w.Flush()
+	
+	for _, stat := range failed {
+		fmt.Printf("\n  %s⚠️  %s: %s%s", colorRed, stat.Name, stat.Error, colorReset)
+	}
+	if len(failed) > 0 {
+		fmt.Println()
+	}
+	
+	fmt.Printf("\n%sBased on the %d most recent reviews per app • Generated: %s%s\n",
+		colorGray, reviews.PortfolioReviewLimit, time.Now().Format("2006-01-02 15:04:05"), colorReset)
+}
+
+// reviewTerritories converts --territory codes, alpha-2 or alpha-3, to the
+// alpha-3 codes reviews use and adds every territory in the --region regions
+func reviewTerritories(territories, regions []string) ([]string, error) {
+	var codes []string
+	for _, territory := range territories {
+		code := countries.Alpha3(territory)
+		if code == "" {
+			return nil, fmt.Errorf("unknown territory %q, use a country code such as US or USA", territory)
+		}
+		codes = append(codes, code)
+	}
+
+	for _, region := range regions {
+		members, err := countries.Members(region)
+		if err != nil {
+			return nil, err
+		}
+		for _, country := range members {
+			codes = append(codes, country.Alpha3)
+		}
+	}
+	return codes, nil
+}
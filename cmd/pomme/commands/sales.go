@@ -3,16 +3,15 @@ package commands
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/marcusziade/pomme/internal/config"
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/internal/countries"
 	"github.com/marcusziade/pomme/internal/output"
 	"github.com/marcusziade/pomme/internal/services/cache"
 	"github.com/marcusziade/pomme/internal/services/sales"
-	"github.com/marcusziade/pomme/pkg/pomme"
+	"github.com/marcusziade/pomme/pkg/models"
 	"github.com/spf13/cobra"
 )
 
@@ -63,8 +62,16 @@ var salesCompareCmd = &cobra.Command{
 	Use:     "compare",
 	Aliases: []string{"comp", "vs"},
 	Short:   "Compare sales between two periods",
+	Long: `Compares sales between two periods. Periods can be months (2025-03),
+calendar quarters (2025-Q2), years (2025), fiscal quarters and years
+(FY2026-Q1, FY2026) or Apple fiscal months (apple:2025-10).
+
+Fiscal years start in the month set by --fiscal-start or defaults.fiscal_year_start
+in the config, and are named after the calendar year they end in.`,
 	Example: `  pomme sales compare --months 2   # Compare last 2 months
-  pomme sales compare --current 2025-03 --previous 2025-02`,
+  pomme sales compare --current 2025-03 --previous 2025-02
+  pomme sales compare --current 2025-Q2 --previous 2024-Q2
+  pomme sales compare --current FY2026 --previous FY2025 --fiscal-start april`,
 	RunE: runCompare,
 }
 
@@ -73,22 +80,129 @@ var salesTrendsCmd = &cobra.Command{
 	Use:     "trends",
 	Aliases: []string{"trend", "t"},
 	Short:   "Analyze sales trends over time",
-	Example: `  pomme sales trends --months 6    # Last 6 months
-  pomme sales trends --quarters 4  # Last 4 quarters
-  pomme sales trends --years 2     # Last 2 years`,
+	Long: `Analyzes sales over the last complete months, quarters, years or Apple
+fiscal months. Quarters and years are rolled up from monthly reports. With a
+fiscal year start (--fiscal-start or defaults.fiscal_year_start in the config),
+quarters and years follow the fiscal calendar.`,
+	Example: `  pomme sales trends --months 6         # Last 6 months
+  pomme sales trends --quarters 8       # Last 8 quarters
+  pomme sales trends --years 2          # Last 2 years
+  pomme sales trends --quarters 4 --fiscal-start april
+  pomme sales trends --apple-months 6   # Apple fiscal months`,
 	RunE: runTrends,
 }
 
+// Sales over arbitrary dates
+var salesRangeCmd = &cobra.Command{
+	Use:   "range",
+	Short: "Sales between two dates",
+	Long: `Shows sales between any two dates, both included.
+
+Whole months come from monthly reports, whole Monday to Sunday weeks from weekly
+reports and the remaining days from daily reports, so every day is counted once.
+When Apple hasn't published a report yet, its days are fetched from finer
+reports instead. The reports used and those that were unavailable are listed
+below the totals.`,
+	Example: `  pomme sales range --from 2025-01-15 --to 2025-04-10
+  pomme sales range --from 2025-03-01 --by-country   # Up to yesterday`,
+	RunE: runRange,
+}
+
 // Export data
 var salesExportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export sales data in various formats",
+	Long: `Exports monthly sales as CSV or JSON, by default for the latest available month.
+
+CSV has one row per app, country and proceeds currency, or one row per sale
+with --detailed. JSON has one report per month; --detailed includes the
+individual sales. --filter applies before anything is totalled.`,
 	Example: `  pomme sales export --month 2025-03 --format csv
-  pomme sales export --last 3 --format excel
-  pomme sales export --year 2025 --format json`,
+  pomme sales export --last 3 --detailed --output sales.csv
+  pomme sales export --year 2025 --format json
+  pomme sales export --month 2025-03 --filter "country in (US, CA)"`,
 	RunE: runExport,
 }
 
+// Forecast future months
+var salesForecastCmd = &cobra.Command{
+	Use:     "forecast",
+	Aliases: []string{"fc"},
+	Short:   "Forecast monthly sales",
+	Long: `Forecasts units and proceeds for the coming months, in total and per app.
+
+Each series is fitted with a linear trend over the last --months complete
+months. With at least 24 months of history the model also learns how much
+each calendar month usually sells above or below the trend. Forecasts come
+with prediction intervals at --confidence.
+
+--backtest N refits the models without the last N known months, forecasts
+them and compares the forecasts with what was actually sold, reporting the
+mean absolute error (MAE), the mean absolute percentage error (MAPE) and how
+many actuals fell within the prediction intervals.`,
+	Example: `  pomme sales forecast --months 6 --horizon 3
+  pomme sales forecast --months 36 --horizon 12 --backtest 6
+  pomme sales forecast --filter "app = Foo" --confidence 80`,
+	RunE: runForecast,
+}
+
+// Detect unusual days
+var salesAnomaliesCmd = &cobra.Command{
+	Use:     "anomalies",
+	Aliases: []string{"anomaly", "outliers"},
+	Short:   "Find days with unusual sales",
+	Long: `Checks daily reports for days on which an app or a country sold unusually
+much or little.
+
+Each app's and country's daily units are adjusted for the day of the week and
+compared with the median of the trailing --window days. Days whose robust
+z-score, the distance from that median in median absolute deviations scaled
+to standard deviations, reaches --threshold are reported. Each anomaly lists
+the countries or apps, product types and promo codes that drove it.
+
+Days Apple hasn't published a report for are skipped, not counted as zero.`,
+	Example: `  pomme sales anomalies --days 90
+  pomme sales anomalies --days 30 --threshold 5
+  pomme sales anomalies --days 90 --filter "app = Foo" --json`,
+	RunE: runAnomalies,
+}
+
+// Promo and offer code analytics
+var salesPromosCmd = &cobra.Command{
+	Use:     "promos",
+	Aliases: []string{"promo", "codes"},
+	Short:   "Promo and offer code performance",
+	Long: `Shows the units and proceeds sold with promo and offer codes, by code, app
+and country, and how redemptions developed month by month. Each app's code
+sales are compared with its sales without a code.
+
+Months are chosen as for export, by default the latest available month.
+With --output csv there's one row per month, code, app, country and proceeds
+currency; --output json has every breakdown.`,
+	Example: `  pomme sales promos --last 6
+  pomme sales promos --year 2025 --filter "app = Foo"
+  pomme sales promos --last 3 --output csv > promos.csv`,
+	RunE: runPromos,
+}
+
+// Refunds and returns
+var salesRefundsCmd = &cobra.Command{
+	Use:     "refunds",
+	Aliases: []string{"returns"},
+	Short:   "Refunds and returns by app and country",
+	Long: `Separates gross sales from returns over the last --months months: units sold
+and returned, proceeds before and after refunds, and the refund rate, the
+share of units sold that were returned, per app and per country.
+
+An app is flagged when its refund rate in the latest month is at least
+--spike-factor times its rate over the earlier months, it rose by at least one
+percentage point and at least --min-returns units were returned.`,
+	Example: `  pomme sales refunds
+  pomme sales refunds --months 12 --spike-factor 3
+  pomme sales refunds --region EU --json`,
+	RunE: runRefunds,
+}
+
 // Watch for updates
 var salesWatchCmd = &cobra.Command{
 	Use:   "watch",
@@ -102,7 +216,12 @@ func init() {
 	salesCmd.AddCommand(salesMonthlyCmd)
 	salesCmd.AddCommand(salesCompareCmd)
 	salesCmd.AddCommand(salesTrendsCmd)
+	salesCmd.AddCommand(salesRangeCmd)
 	salesCmd.AddCommand(salesExportCmd)
+	salesCmd.AddCommand(salesForecastCmd)
+	salesCmd.AddCommand(salesAnomaliesCmd)
+	salesCmd.AddCommand(salesPromosCmd)
+	salesCmd.AddCommand(salesRefundsCmd)
 	salesCmd.AddCommand(salesWatchCmd)
 
 	// Set monthly as the default when no subcommand is specified
@@ -112,38 +231,83 @@ func init() {
 	salesCmd.PersistentFlags().String("vendor", "", "Vendor number (default: from config)")
 	salesCmd.PersistentFlags().Bool("no-cache", false, "Skip cache and fetch fresh data")
 	salesCmd.PersistentFlags().Bool("json", false, "Output raw JSON")
+	salesCmd.PersistentFlags().String("filter", "", "Only include matching sales, e.g. 'app in (Foo, Bar) and country != CN'")
+	salesCmd.PersistentFlags().StringSlice("region", nil, "Only include sales from these regions (NA, LATAM, EMEA, APAC, EU, EUROZONE, apple:<finance region>)")
 
 	// Report command flags
 	salesReportCmd.Flags().String("period", "MONTHLY", "Report period (DAILY, WEEKLY, MONTHLY, YEARLY)")
 	salesReportCmd.Flags().String("date", "latest", "Report date (YYYY-MM-DD or 'latest')")
 	salesReportCmd.Flags().String("type", "SALES", "Report type (SALES, SUBSCRIPTION, SUBSCRIPTION_EVENT)")
+	salesReportCmd.Flags().Bool("product-type", false, "Break down by product type")
+	salesReportCmd.Flags().String("view", viewNested, "In-app purchases under their apps (nested) or as separate apps (flat), in the table and JSON")
 
 	// Monthly command flags
 	salesMonthlyCmd.Flags().Bool("details", false, "Show detailed breakdown")
 	salesMonthlyCmd.Flags().Bool("by-country", false, "Group by country")
 	salesMonthlyCmd.Flags().Bool("by-app", false, "Group by app")
+	salesMonthlyCmd.Flags().Bool("product-type", false, "Break down by product type")
+	salesMonthlyCmd.Flags().String("view", viewNested, "In-app purchases under their apps (nested) or as separate apps (flat), in the table and JSON")
 
 	// Compare command flags
-	salesCompareCmd.Flags().String("current", "", "Current period (YYYY-MM)")
-	salesCompareCmd.Flags().String("previous", "", "Previous period (YYYY-MM)")
+	salesCompareCmd.Flags().String("current", "", "Current period (YYYY-MM, YYYY-QN, YYYY, FYYYYY-QN, FYYYYY or apple:YYYY-MM)")
+	salesCompareCmd.Flags().String("previous", "", "Previous period, in the same forms as --current")
 	salesCompareCmd.Flags().Int("months", 0, "Compare last N months")
 	salesCompareCmd.Flags().Bool("percentage", false, "Show percentage changes")
+	salesCompareCmd.Flags().String("fiscal-start", "", "First month of the fiscal year, e.g. april (default: from config)")
+	salesCompareCmd.Flags().Bool("product-type", false, "Break down by product category")
 
 	// Trends command flags
 	salesTrendsCmd.Flags().Int("months", 0, "Analyze last N months")
 	salesTrendsCmd.Flags().Int("quarters", 0, "Analyze last N quarters")
 	salesTrendsCmd.Flags().Int("years", 0, "Analyze last N years")
+	salesTrendsCmd.Flags().Int("apple-months", 0, "Analyze last N Apple fiscal months")
+	salesTrendsCmd.Flags().String("fiscal-start", "", "First month of the fiscal year, e.g. april (default: from config)")
 	salesTrendsCmd.Flags().String("group", "total", "Group by (total, app, country, platform)")
 	salesTrendsCmd.Flags().Bool("chart", false, "Display ASCII chart")
+	salesTrendsCmd.Flags().Bool("product-type", false, "Break down by product category")
+
+	// Range command flags
+	salesRangeCmd.Flags().String("from", "", "First day (YYYY-MM-DD)")
+	salesRangeCmd.Flags().String("to", "", "Last day (YYYY-MM-DD, default: yesterday)")
+	salesRangeCmd.Flags().String("type", "SALES", "Report type (only SALES reports can be combined over a range)")
+	salesRangeCmd.Flags().Bool("by-country", false, "Group by country")
+	salesRangeCmd.Flags().Bool("product-type", false, "Break down by product type")
+	salesRangeCmd.Flags().String("view", viewNested, "In-app purchases under their apps (nested) or as separate apps (flat), in the table and JSON")
 
 	// Export command flags
 	salesExportCmd.Flags().String("month", "", "Specific month (YYYY-MM)")
 	salesExportCmd.Flags().Int("last", 0, "Last N months")
 	salesExportCmd.Flags().String("year", "", "Full year (YYYY)")
-	salesExportCmd.Flags().String("format", "csv", "Export format (csv, json, excel)")
+	salesExportCmd.Flags().String("format", "csv", "Export format (csv, json)")
 	salesExportCmd.Flags().String("output", "", "Output file (default: stdout)")
 	salesExportCmd.Flags().Bool("detailed", false, "Include all transaction details")
 
+	// Forecast command flags
+	salesForecastCmd.Flags().Int("months", 24, "Months of history to fit")
+	salesForecastCmd.Flags().Int("horizon", 3, "Months to forecast")
+	salesForecastCmd.Flags().Int("backtest", 0, "Hold out and forecast the last N known months to measure accuracy")
+	salesForecastCmd.Flags().Float64("confidence", 95, "Prediction interval coverage in percent")
+	salesForecastCmd.Flags().Int("apps", 5, "Number of apps to show forecasts for, 0 for all")
+
+	// Anomalies command flags
+	salesAnomaliesCmd.Flags().Int("days", 90, "Days to check, ending yesterday")
+	salesAnomaliesCmd.Flags().Int("window", sales.DefaultAnomalyWindow, "Trailing days each day is compared with")
+	salesAnomaliesCmd.Flags().Float64("threshold", sales.DefaultAnomalyThreshold, "Robust z-score from which a day is unusual")
+	salesAnomaliesCmd.Flags().Float64("min-units", sales.DefaultAnomalyMinUnits, "Ignore days within this many units of the expected")
+	salesAnomaliesCmd.Flags().Int("limit", 20, "Number of anomalies to show, 0 for all")
+
+	// Promos command flags
+	salesPromosCmd.Flags().String("month", "", "Specific month (YYYY-MM)")
+	salesPromosCmd.Flags().Int("last", 0, "Last N months")
+	salesPromosCmd.Flags().String("year", "", "Full year (YYYY)")
+	salesPromosCmd.Flags().Int("limit", 10, "Number of codes and countries to show, 0 for all")
+
+	// Refunds command flags
+	salesRefundsCmd.Flags().Int("months", 6, "Months to analyze, ending with the latest available")
+	salesRefundsCmd.Flags().Float64("spike-factor", sales.DefaultRefundSpikeFactor, "Flag apps whose latest refund rate is this many times their earlier rate")
+	salesRefundsCmd.Flags().Int("min-returns", sales.DefaultRefundMinReturns, "Fewest units returned in the latest month to flag an app")
+	salesRefundsCmd.Flags().Int("limit", 10, "Number of apps and countries to show, 0 for all")
+
 	// Watch command flags
 	salesWatchCmd.Flags().Duration("interval", 1*time.Hour, "Check interval")
 	salesWatchCmd.Flags().Bool("notify", false, "Send desktop notification")
@@ -165,6 +329,14 @@ func runMonthlyReport(cmd *cobra.Command, args []string) error {
 		targetMonth = calculateLatestAvailableMonth()
 	}
 
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkView(cmd); err != nil {
+		return err
+	}
+
 	// Get configuration
 	cfg, service, err := setupSalesService(cmd)
 	if err != nil {
@@ -179,6 +351,7 @@ func runMonthlyReport(cmd *cobra.Command, args []string) error {
 		VendorNumber: cfg.Defaults.VendorNumber,
 		NoCache:      mustGetBool(cmd, "no-cache"),
 		IncludeAnalysis: true,
+		Filter:       filter,
 	}
 
 	// Show what we're fetching
@@ -190,6 +363,11 @@ func runMonthlyReport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch report: %w", err)
 	}
 
+	if report != nil && len(report.Apps) == 0 && filter != nil {
+		fmt.Printf("\n❌ No sales in %s match the filter\n", targetMonth.Format("January 2006"))
+		return nil
+	}
+
 	if report == nil || len(report.Apps) == 0 {
 		fmt.Printf("\n❌ No sales data available for %s\n", targetMonth.Format("January 2006"))
 		
@@ -204,7 +382,7 @@ func runMonthlyReport(cmd *cobra.Command, args []string) error {
 
 	// Display the report
 	if mustGetBool(cmd, "json") {
-		return output.JSON(report)
+		return output.JSON(viewReport(cmd, report))
 	}
 
 	displayMonthlyReport(cmd, report)
@@ -236,6 +414,14 @@ func runSalesReport(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkView(cmd); err != nil {
+		return err
+	}
+
 	// Create report options
 	options := sales.ReportOptions{
 		Period:       period,
@@ -243,6 +429,7 @@ func runSalesReport(cmd *cobra.Command, args []string) error {
 		ReportType:   reportType,
 		VendorNumber: getVendorNumber(cmd, cfg),
 		NoCache:      mustGetBool(cmd, "no-cache"),
+		Filter:       filter,
 	}
 
 	// Fetch the report
@@ -251,18 +438,28 @@ func runSalesReport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch report: %w", err)
 	}
 
+	if report == nil {
+		fmt.Printf("\n❌ No sales data available for %s %s\n", options.Period, options.FormatDate())
+		return nil
+	}
+
 	// Display the report
 	if mustGetBool(cmd, "json") {
-		return output.JSON(report)
+		return output.JSON(viewReport(cmd, report))
 	}
 
-	displayDetailedReport(report)
+	displayDetailedReport(cmd, report)
 	return nil
 }
 
 func runCompare(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return err
+	}
+
 	cfg, service, err := setupSalesService(cmd)
 	if err != nil {
 		return err
@@ -290,7 +487,7 @@ func runCompare(cmd *cobra.Command, args []string) error {
 			VendorNumber: getVendorNumber(cmd, cfg),
 		}
 	} else {
-		// Use specific months
+		// Use specific periods
 		currentStr := mustGetString(cmd, "current")
 		previousStr := mustGetString(cmd, "previous")
 
@@ -298,31 +495,48 @@ func runCompare(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("specify either --months or both --current and --previous")
 		}
 
-		current, err := time.Parse("2006-01", currentStr)
+		calendar, err := salesCalendar(cmd, cfg)
 		if err != nil {
-			return fmt.Errorf("invalid current month: %w", err)
+			return err
 		}
 
-		previous, err := time.Parse("2006-01", previousStr)
+		current, err := calendar.ParsePeriod(currentStr)
 		if err != nil {
-			return fmt.Errorf("invalid previous month: %w", err)
+			return fmt.Errorf("invalid current period: %w", err)
+		}
+
+		previous, err := calendar.ParsePeriod(previousStr)
+		if err != nil {
+			return fmt.Errorf("invalid previous period: %w", err)
+		}
+
+		if current.Kind != sales.PeriodMonth || previous.Kind != sales.PeriodMonth {
+			return comparePeriods(ctx, cmd, service, current, previous, sales.RangeOptions{
+				ReportType:   models.ReportTypeSales,
+				VendorNumber: getVendorNumber(cmd, cfg),
+				NoCache:      mustGetBool(cmd, "no-cache"),
+				Filter:       filter,
+			})
 		}
 
 		currentOpt = sales.ReportOptions{
 			Period:       models.ReportFrequencyMonthly,
-			Date:         current,
+			Date:         current.Start,
 			ReportType:   models.ReportTypeSales,
 			VendorNumber: getVendorNumber(cmd, cfg),
 		}
 
 		previousOpt = sales.ReportOptions{
 			Period:       models.ReportFrequencyMonthly,
-			Date:         previous,
+			Date:         previous.Start,
 			ReportType:   models.ReportTypeSales,
 			VendorNumber: getVendorNumber(cmd, cfg),
 		}
 	}
 
+	currentOpt.Filter = filter
+	previousOpt.Filter = filter
+
 	fmt.Printf("📊 Comparing %s vs %s...\n", 
 		currentOpt.Date.Format("January 2006"),
 		previousOpt.Date.Format("January 2006"))
@@ -342,6 +556,30 @@ func runCompare(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// comparePeriods compares two periods rolled up from several reports
+func comparePeriods(ctx context.Context, cmd *cobra.Command, service *sales.Service, current, previous sales.Period, options sales.RangeOptions) error {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	for _, period := range []sales.Period{current, previous} {
+		if period.End.After(yesterday) {
+			return fmt.Errorf("%s isn't over yet", period.Label)
+		}
+	}
+
+	fmt.Printf("📊 Comparing %s vs %s...\n", current.Label, previous.Label)
+
+	comparison, err := service.ComparePeriods(ctx, current, previous, options)
+	if err != nil {
+		return fmt.Errorf("failed to get comparison: %w", err)
+	}
+
+	if mustGetBool(cmd, "json") {
+		return output.JSON(comparison)
+	}
+
+	displayComparison(cmd, comparison)
+	return nil
+}
+
 func runTrends(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -350,6 +588,16 @@ func runTrends(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	calendar, err := salesCalendar(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Determine trend period
 	var trendOpt sales.TrendOptions
 
@@ -363,16 +611,43 @@ func runTrends(cmd *cobra.Command, args []string) error {
 			GroupBy:      mustGetString(cmd, "group"),
 		}
 	} else if quarters := mustGetInt(cmd, "quarters"); quarters > 0 {
-		// For quarters, we'll use monthly reports and aggregate
+		// Quarters are rolled up from monthly reports
 		trendOpt = sales.TrendOptions{
 			Frequency:    models.ReportFrequencyMonthly,
-			EndDate:      calculateLatestAvailableMonth(),
-			Periods:      quarters * 3,
+			EndDate:      calendar.LatestComplete(sales.PeriodQuarter, time.Now()).Start,
+			Periods:      quarters,
+			ReportType:   models.ReportTypeSales,
+			VendorNumber: getVendorNumber(cmd, cfg),
+			GroupBy:      mustGetString(cmd, "group"),
+			Bucket:       sales.PeriodQuarter,
+			Calendar:     calendar,
+		}
+	} else if years := mustGetInt(cmd, "years"); years > 0 && calendar.IsFiscal() {
+		// Fiscal years are rolled up from monthly reports
+		trendOpt = sales.TrendOptions{
+			Frequency:    models.ReportFrequencyMonthly,
+			EndDate:      calendar.LatestComplete(sales.PeriodYear, time.Now()).Start,
+			Periods:      years,
 			ReportType:   models.ReportTypeSales,
 			VendorNumber: getVendorNumber(cmd, cfg),
 			GroupBy:      mustGetString(cmd, "group"),
+			Bucket:       sales.PeriodYear,
+			Calendar:     calendar,
 		}
-	} else if years := mustGetInt(cmd, "years"); years > 0 {
+	} else if appleMonths := mustGetInt(cmd, "apple-months"); appleMonths > 0 {
+		// Apple fiscal months don't line up with calendar months, so they're
+		// stitched from weekly and daily reports
+		trendOpt = sales.TrendOptions{
+			Frequency:    models.ReportFrequencyWeekly,
+			EndDate:      calendar.LatestComplete(sales.PeriodAppleMonth, time.Now()).Start,
+			Periods:      appleMonths,
+			ReportType:   models.ReportTypeSales,
+			VendorNumber: getVendorNumber(cmd, cfg),
+			GroupBy:      mustGetString(cmd, "group"),
+			Bucket:       sales.PeriodAppleMonth,
+			Calendar:     calendar,
+		}
+	} else if years > 0 {
 		trendOpt = sales.TrendOptions{
 			Frequency:    models.ReportFrequencyYearly,
 			EndDate:      time.Now().AddDate(-1, 0, 0), // Last complete year
@@ -393,7 +668,13 @@ func runTrends(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("📈 Analyzing trends over %d %s...\n", trendOpt.Periods, trendOpt.Frequency)
+	trendOpt.Filter = filter
+
+	if trendOpt.Bucket != "" {
+		fmt.Printf("📈 Analyzing trends over %d %ss...\n", trendOpt.Periods, trendOpt.Bucket)
+	} else {
+		fmt.Printf("📈 Analyzing trends over %d %s...\n", trendOpt.Periods, trendOpt.Frequency)
+	}
 
 	// Fetch trends
 	trends, err := service.GetTrends(ctx, trendOpt)
@@ -410,9 +691,75 @@ func runTrends(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runExport(cmd *cobra.Command, args []string) error {
-	// TODO: Implement export functionality
-	fmt.Println("Export functionality coming soon!")
+func runRange(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if mustGetString(cmd, "from") == "" {
+		return fmt.Errorf("--from is required")
+	}
+	from, err := time.Parse("2006-01-02", mustGetString(cmd, "from"))
+	if err != nil {
+		return fmt.Errorf("invalid --from date, use YYYY-MM-DD: %w", err)
+	}
+
+	to := time.Now().AddDate(0, 0, -1)
+	if value := mustGetString(cmd, "to"); value != "" {
+		to, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return fmt.Errorf("invalid --to date, use YYYY-MM-DD: %w", err)
+		}
+	}
+
+	reportType, err := parseReportType(mustGetString(cmd, "type"))
+	if err != nil {
+		return err
+	}
+
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkView(cmd); err != nil {
+		return err
+	}
+
+	cfg, service, err := setupSalesService(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !mustGetBool(cmd, "json") {
+		fmt.Printf("📊 Fetching sales from %s to %s...\n", from.Format("Jan 2, 2006"), to.Format("Jan 2, 2006"))
+	}
+
+	result, err := service.GetRange(ctx, sales.RangeOptions{
+		DateRange:    sales.DateRange{Start: from, End: to},
+		ReportType:   reportType,
+		VendorNumber: getVendorNumber(cmd, cfg),
+		NoCache:      mustGetBool(cmd, "no-cache"),
+		Filter:       filter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch sales: %w", err)
+	}
+
+	if mustGetBool(cmd, "json") {
+		return output.JSON(struct {
+			From        string
+			To          string
+			Report      *models.SalesReport
+			Used        []string
+			Unavailable []string
+		}{
+			From:        result.Range.Start.Format("2006-01-02"),
+			To:          result.Range.End.Format("2006-01-02"),
+			Report:      viewReport(cmd, result.Report),
+			Used:        reportNames(result.Used),
+			Unavailable: reportNames(result.Unavailable),
+		})
+	}
+
+	displayRangeReport(cmd, result)
 	return nil
 }
 
@@ -424,7 +771,7 @@ func runWatch(cmd *cobra.Command, args []string) error {
 
 // Helper functions
 
-func setupSalesService(cmd *cobra.Command) (*config.Config, *salesService, error) {
+func setupSalesService(cmd *cobra.Command) (*config.Config, *sales.Service, error) {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -432,42 +779,27 @@ func setupSalesService(cmd *cobra.Command) (*config.Config, *salesService, error
 	}
 
 	// Validate config
-	if cfg.Auth.KeyID == "" || cfg.Auth.IssuerID == "" || cfg.Auth.PrivateKeyPath == "" {
+	if cfg.Auth.KeyID == "" || (cfg.Auth.IssuerID == "" && !cfg.Auth.IsIndividual()) {
 		return nil, nil, fmt.Errorf("authentication not configured. Run 'pomme config init' first")
 	}
 
-	// Read private key
-	privateKeyData, err := os.ReadFile(cfg.Auth.PrivateKeyPath)
+	// Create client
+	client, err := newPommeClient(cfg)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read private key: %w", err)
+		return nil, nil, err
 	}
 
-	// Create client
-	client := pomme.NewClient(cfg.Auth.KeyID, cfg.Auth.IssuerID, string(privateKeyData))
-
 	// Create cache
 	cacheService := cache.NewMemoryCache()
 
 	// Create sales service
-	// For now, we'll use the client directly until we refactor the service layer
-	service := &salesService{
-		client: client,
-		cache:  cacheService,
-	}
+	service := sales.NewService(client, cacheService)
 
 	return cfg, service, nil
 }
 
 func calculateLatestAvailableMonth() time.Time {
-	now := time.Now()
-	
-	// If we're within the first 5 days of the month, go back 2 months
-	if now.Day() <= 5 {
-		return now.AddDate(0, -2, 0)
-	}
-	
-	// Otherwise, last month's data should be available
-	return now.AddDate(0, -1, 0)
+	return sales.LatestAvailableMonth(time.Now())
 }
 
 func parseReportPeriod(period string) (models.ReportFrequency, error) {
@@ -493,10 +825,13 @@ func parseReportDate(dateStr string, period models.ReportFrequency) (time.Time,
 		case models.ReportFrequencyDaily:
 			return time.Now().AddDate(0, 0, -1), nil
 		case models.ReportFrequencyWeekly:
-			// Find last Saturday
+			// Find the last complete week, which ends on a Sunday
 			now := time.Now()
-			daysSinceSaturday := (int(now.Weekday()) + 1) % 7
-			return now.AddDate(0, 0, -daysSinceSaturday), nil
+			daysSinceSunday := int(now.Weekday())
+			if daysSinceSunday == 0 {
+				daysSinceSunday = 7
+			}
+			return now.AddDate(0, 0, -daysSinceSunday), nil
 		case models.ReportFrequencyYearly:
 			return time.Now().AddDate(-1, 0, 0), nil
 		}
@@ -528,6 +863,89 @@ func parseReportType(typeStr string) (models.ReportType, error) {
 	}
 }
 
+// Views of in-app purchases for --view
+const (
+	viewNested = "nested"
+	viewFlat   = "flat"
+)
+
+// checkView validates --view
+func checkView(cmd *cobra.Command) error {
+	switch view := mustGetString(cmd, "view"); strings.ToLower(view) {
+	case "", viewNested, viewFlat:
+		return nil
+	default:
+		return fmt.Errorf("invalid view %q (valid: %s, %s)", view, viewNested, viewFlat)
+	}
+}
+
+// viewReport returns the report as --view shows it, with in-app purchases
+// nested under their apps unless the view is flat
+func viewReport(cmd *cobra.Command, report *models.SalesReport) *models.SalesReport {
+	if strings.EqualFold(mustGetString(cmd, "view"), viewFlat) {
+		return report
+	}
+	return nestReport(report)
+}
+
+// nestReport returns a copy of the report with in-app purchases nested under
+// their apps
+func nestReport(report *models.SalesReport) *models.SalesReport {
+	nested := *report
+	nested.Apps = sales.NestApps(report.Apps)
+	return &nested
+}
+
+// salesFilter parses --filter and --region, returning nil when neither is set
+func salesFilter(cmd *cobra.Command) (*sales.FilterOptions, error) {
+	value := mustGetString(cmd, "filter")
+	regions, _ := cmd.Flags().GetStringSlice("region")
+	if value == "" && len(regions) == 0 {
+		return nil, nil
+	}
+
+	filter := &sales.FilterOptions{Regions: regions}
+	for _, region := range regions {
+		if err := countries.ValidateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+	if value != "" {
+		expression, err := sales.ParseFilter(value)
+		if err != nil {
+			return nil, err
+		}
+		filter.Expression = expression
+	}
+	return filter, nil
+}
+
+// salesCalendar returns the calendar set by --fiscal-start or the config
+func salesCalendar(cmd *cobra.Command, cfg *config.Config) (sales.Calendar, error) {
+	value := mustGetString(cmd, "fiscal-start")
+	if value == "" {
+		value = cfg.Defaults.FiscalYearStart
+	}
+	if value == "" {
+		return sales.Calendar{}, nil
+	}
+
+	month, err := sales.ParseMonth(value)
+	if err != nil {
+		return sales.Calendar{}, fmt.Errorf("invalid fiscal year start: %w", err)
+	}
+	return sales.Calendar{FiscalYearStart: month}, nil
+}
+
+// reportNames names each report, e.g. "MONTHLY 2025-03"
+func reportNames(requests []sales.ReportOptions) []string {
+	names := make([]string, len(requests))
+	for i, request := range requests {
+		names[i] = request.String()
+	}
+	return names
+}
+
 func getVendorNumber(cmd *cobra.Command, cfg *config.Config) string {
 	if vendor := mustGetString(cmd, "vendor"); vendor != "" {
 		return vendor
@@ -549,4 +967,8 @@ func mustGetBool(cmd *cobra.Command, flag string) bool {
 func mustGetInt(cmd *cobra.Command, flag string) int {
 	val, _ := cmd.Flags().GetInt(flag)
 	return val
-}
\ No newline at end of file
+}
+func mustGetFloat64(cmd *cobra.Command, flag string) float64 {
+	val, _ := cmd.Flags().GetFloat64(flag)
+	return val
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/output"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+func runAnomalies(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	days := mustGetInt(cmd, "days")
+	window := mustGetInt(cmd, "window")
+	if days < 1 {
+		return fmt.Errorf("--days must be positive")
+	}
+	if window < 1 {
+		return fmt.Errorf("--window must be positive")
+	}
+	if mustGetFloat64(cmd, "threshold") <= 0 {
+		return fmt.Errorf("--threshold must be positive")
+	}
+
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, service, err := setupSalesService(cmd)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput := mustGetBool(cmd, "json")
+	if !jsonOutput {
+		fmt.Printf("🔎 Checking %d days of daily sales...\n", days)
+	}
+
+	report, err := service.GetAnomalies(ctx, sales.AnomalyOptions{
+		EndDate:      time.Now().AddDate(0, 0, -1),
+		Days:         days,
+		Window:       window,
+		Threshold:    mustGetFloat64(cmd, "threshold"),
+		MinUnits:     mustGetFloat64(cmd, "min-units"),
+		ReportType:   models.ReportTypeSales,
+		VendorNumber: getVendorNumber(cmd, cfg),
+		NoCache:      mustGetBool(cmd, "no-cache"),
+		Filter:       filter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detect anomalies: %w", err)
+	}
+
+	if jsonOutput {
+		return output.JSON(report)
+	}
+
+	displayAnomalies(report, mustGetInt(cmd, "limit"))
+	return nil
+}
+
+// displayAnomalies shows the newest anomalies as insights
+func displayAnomalies(report *sales.AnomalyReport, limit int) {
+	fmt.Printf("\n%s🔎 Sales Anomalies%s\n", colorBold, colorReset)
+	fmt.Printf("Period: %s to %s (%d days, %d apps and countries)\n",
+		report.Days[0].Format("Jan 2, 2006"),
+		report.Days[len(report.Days)-1].Format("Jan 2, 2006"),
+		len(report.Days),
+		report.Series)
+	if len(report.Missing) > 0 {
+		fmt.Printf("%sNo reports for %s%s\n", colorGray, formatMissingDays(report.Missing), colorReset)
+	}
+	fmt.Println(strings.Repeat("═", 60))
+
+	if len(report.Insights) == 0 {
+		fmt.Printf("\n  %s✅ No unusual days found%s\n", colorGreen, colorReset)
+		return
+	}
+
+	insights := report.Insights
+	if limit > 0 && len(insights) > limit {
+		insights = insights[:limit]
+	}
+	displayInsights(insights)
+
+	if len(insights) < len(report.Insights) {
+		fmt.Printf("\n  %s... and %d older (--limit 0 shows all)%s\n", colorGray, len(report.Insights)-len(insights), colorReset)
+	}
+}
+
+// formatMissingDays lists days, collapsing runs of consecutive days into
+// ranges, e.g. "Oct 3, Oct 17 – Oct 18"
+func formatMissingDays(days []time.Time) string {
+	var parts []string
+	for i := 0; i < len(days); {
+		j := i
+		for j+1 < len(days) && days[j+1].Sub(days[j]) == 24*time.Hour {
+			j++
+		}
+		part := days[i].Format("Jan 2")
+		if j > i {
+			part += " – " + days[j].Format("Jan 2")
+		}
+		parts = append(parts, part)
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
@@ -5,8 +5,9 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/internal/countries"
 	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
 	"github.com/spf13/cobra"
 )
 
@@ -30,7 +31,8 @@ func displayMonthlyReport(cmd *cobra.Command, report *models.SalesReport) {
 		fmt.Printf("\n%s📱 App Performance%s\n", colorBold, colorReset)
 		fmt.Println(strings.Repeat("─", 60))
 		
-		displayAppTable(report.Apps)
+		displayAppTable(viewReport(cmd, report).Apps)
+		displayIAPLeaderboard(sales.NestApps(report.Apps))
 	}
 
 	// Country breakdown if requested
@@ -38,6 +40,10 @@ func displayMonthlyReport(cmd *cobra.Command, report *models.SalesReport) {
 		displayCountryBreakdown(report)
 	}
 
+	if mustGetBool(cmd, "product-type") {
+		displayProductTypeBreakdown(report)
+	}
+
 	// Trends and insights
 	if report.Summary.Trends != nil {
 		displayTrends(report.Summary.Trends)
@@ -52,6 +58,7 @@ func displaySummaryMetrics(summary models.ReportSummary) {
 	// Total units card
 	fmt.Printf("\n  %s📦 Total Units%s\n", colorBold, colorReset)
 	fmt.Printf("  %s%s%s\n", colorCyan, formatNumber(summary.TotalUnits), colorReset)
+	displayUnitBreakdown(summary.Breakdown)
 
 	// Revenue cards by currency
 	if len(summary.TotalProceeds) > 0 {
@@ -66,8 +73,13 @@ func displaySummaryMetrics(summary models.ReportSummary) {
 
 		for _, currency := range currencies {
 			amount := summary.TotalProceeds[currency]
-			if amount > 0 {
-				fmt.Printf("  %s%s %.2f%s", colorGreen, currency, amount, colorReset)
+			if amount != 0 {
+				color := colorGreen
+				if amount < 0 {
+					// Refunds outweighed sales
+					color = colorRed
+				}
+				fmt.Printf("  %s%s %.2f%s", color, currency, amount, colorReset)
 				
 				// Add original currency if available
 				if origAmount, ok := summary.TotalProceeds[currency+"_ORIG"]; ok {
@@ -79,13 +91,15 @@ func displaySummaryMetrics(summary models.ReportSummary) {
 	} else {
 		fmt.Printf("  %sNo revenue (free apps only)%s\n", colorGray, colorReset)
 	}
+	displayRefundSummary(summary.Refunds)
 
 	// Countries
 	fmt.Printf("\n  %s🌍 Countries%s\n", colorBold, colorReset)
 	fmt.Printf("  %s%d markets%s\n", colorCyan, summary.TotalCountries, colorReset)
 }
 
-// displayAppTable shows apps in a formatted table
+// displayAppTable shows apps in a formatted table. Nested apps show their
+// rolled-up totals, followed by their in-app purchases.
 func displayAppTable(apps []models.AppSales) {
 	// Calculate column widths
 	maxAppName := 20
@@ -93,6 +107,11 @@ func displayAppTable(apps []models.AppSales) {
 		if len(app.AppName) > maxAppName {
 			maxAppName = len(app.AppName)
 		}
+		for _, child := range app.Children {
+			if len(child.AppName)+4 > maxAppName {
+				maxAppName = len(child.AppName) + 4
+			}
+		}
 	}
 
 	// Header
@@ -106,32 +125,84 @@ func displayAppTable(apps []models.AppSales) {
 
 	// Rows
 	for _, app := range apps {
-		// App name (truncate if needed)
-		appName := app.AppName
-		if len(appName) > maxAppName {
-			appName = appName[:maxAppName-3] + "..."
+		summary := app.Summary
+		if app.RollUp != nil {
+			summary = *app.RollUp
+		}
+		displayAppRow("", app.AppName, summary, maxAppName)
+
+		for _, child := range app.Children {
+			displayAppRow("  └ ", child.AppName, child.Summary, maxAppName-4)
+		}
+	}
+}
+
+// displayAppRow prints one row of the app table, indenting children
+func displayAppRow(indent, appName string, summary models.AppSummary, maxAppName int) {
+	// App name (truncate if needed)
+	if len(appName) > maxAppName {
+		appName = appName[:maxAppName-3] + "..."
+	}
+
+	// Top markets
+	topMarkets := ""
+	for i, country := range summary.TopCountries {
+		if i > 2 {
+			break
 		}
+		if i > 0 {
+			topMarkets += ", "
+		}
+		topMarkets += country.Country
+	}
 
-		// Format revenue
-		revenueStr := formatRevenue(app.Summary.TotalProceeds)
+	fmt.Printf("  %s%-*s  %8s  %12s  %s\n",
+		indent, maxAppName, appName,
+		formatNumber(summary.TotalUnits),
+		formatRevenue(summary.TotalProceeds),
+		topMarkets)
+}
 
-		// Top markets
-		topMarkets := ""
-		for i, country := range app.Summary.TopCountries {
-			if i > 2 {
+// displayIAPLeaderboard ranks each app's in-app purchases and subscriptions
+// by units sold
+func displayIAPLeaderboard(apps []models.AppSales) {
+	hasChildren := false
+	for _, app := range apps {
+		if len(app.Children) > 0 {
+			hasChildren = true
+			break
+		}
+	}
+	if !hasChildren {
+		return
+	}
+
+	fmt.Printf("\n%s🏆 In-App Purchase Leaderboard%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 60))
+
+	for _, app := range apps {
+		if len(app.Children) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n  %s%s%s\n", colorBold, app.AppName, colorReset)
+		for i, child := range app.Children {
+			if i >= 5 {
+				fmt.Printf("  %s... and %d more%s\n", colorGray, len(app.Children)-5, colorReset)
 				break
 			}
-			if i > 0 {
-				topMarkets += ", "
+
+			category := models.ProductOther
+			if len(child.Sales) > 0 {
+				category = child.Sales[0].ProductCategory()
 			}
-			topMarkets += country.Country
+			fmt.Printf("  %2d. %-28s  %-16s  %8s  %s\n",
+				i+1,
+				child.AppName,
+				category.Label(),
+				formatNumber(child.Summary.TotalUnits),
+				formatRevenue(child.Summary.TotalProceeds))
 		}
-
-		fmt.Printf("  %-*s  %8s  %12s  %s\n",
-			maxAppName, appName,
-			formatNumber(app.Summary.TotalUnits),
-			revenueStr,
-			topMarkets)
 	}
 }
 
@@ -140,65 +211,239 @@ func displayCountryBreakdown(report *models.SalesReport) {
 	fmt.Printf("\n%s🌍 Country Breakdown%s\n", colorBold, colorReset)
 	fmt.Println(strings.Repeat("─", 60))
 
-	// Aggregate by country
-	countryData := make(map[string]*models.CountrySales)
-	
-	for _, app := range report.Apps {
-		for _, sale := range app.Sales {
-			if _, exists := countryData[sale.Country]; !exists {
-				countryData[sale.Country] = &models.CountrySales{
-					Country:     sale.Country,
-					CountryName: sale.CountryName,
-					Proceeds:    make(map[string]float64),
-				}
-			}
-			
-			countryData[sale.Country].Units += sale.Units
-			
-			if sale.DeveloperProceeds.Amount > 0 {
-				countryData[sale.Country].Proceeds[sale.DeveloperProceeds.Currency] += sale.DeveloperProceeds.Amount
-			}
-		}
-	}
-
-	// Convert to slice and sort
-	countries := make([]models.CountrySales, 0, len(countryData))
-	for _, country := range countryData {
-		countries = append(countries, *country)
-	}
-	
-	sort.Slice(countries, func(i, j int) bool {
-		return countries[i].Units > countries[j].Units
-	})
+	breakdown := sales.CountryBreakdown(report)
 
 	// Display top countries
-	fmt.Printf("\n  %-20s  %8s  %s\n", "Country", "Units", "Revenue")
+	fmt.Printf("\n  %-23s  %8s  %s\n", "Country", "Units", "Revenue")
 	fmt.Printf("  %s  %s  %s\n",
-		strings.Repeat("─", 20),
+		strings.Repeat("─", 23),
 		strings.Repeat("─", 8),
 		strings.Repeat("─", 20))
 
-	for i, country := range countries {
+	for i, country := range breakdown {
 		if i >= 10 {
 			break
 		}
 		
 		countryName := country.CountryName
 		if countryName == "" {
-			countryName = country.Country
+			countryName = countries.Name(country.Country)
 		}
-		if len(countryName) > 20 {
-			countryName = countryName[:17] + "..."
+		if len([]rune(countryName)) > 20 {
+			countryName = string([]rune(countryName)[:17]) + "..."
+		}
+		flag := "  "
+		if info, ok := countries.Lookup(country.Country); ok {
+			flag = info.Flag()
 		}
 
-		fmt.Printf("  %-20s  %8s  %s\n",
-			countryName,
+		fmt.Printf("  %s %s  %8s  %s\n",
+			flag, padRight(countryName, 20),
 			formatNumber(country.Units),
 			formatRevenue(country.Proceeds))
 	}
 
-	if len(countries) > 10 {
-		fmt.Printf("  %s... and %d more countries%s\n", colorGray, len(countries)-10, colorReset)
+	if len(breakdown) > 10 {
+		fmt.Printf("  %s... and %d more countries%s\n", colorGray, len(breakdown)-10, colorReset)
+	}
+}
+
+// displayUnitBreakdown shows what kind of units the total is made of
+func displayUnitBreakdown(breakdown models.UnitBreakdown) {
+	fmt.Printf("  %sNew downloads %s · Updates %s · IAP units %s · Revenue-bearing %s%s\n",
+		colorGray,
+		formatNumber(breakdown.Downloads),
+		formatNumber(breakdown.Updates),
+		formatNumber(breakdown.InAppUnits()),
+		formatNumber(breakdown.RevenueUnits),
+		colorReset)
+}
+
+// displayRefundSummary notes the returns netted out of units and revenue
+func displayRefundSummary(refunds models.Refunds) {
+	if refunds.ReturnedUnits == 0 {
+		return
+	}
+	fmt.Printf("  %sNet of %s returned units (%.1f%%), refunded %s%s\n",
+		colorGray,
+		formatNumber(refunds.ReturnedUnits),
+		refunds.Rate(),
+		formatRevenue(refunds.RefundedProceeds),
+		colorReset)
+}
+
+// displayProductTypeBreakdown shows sales by product type
+func displayProductTypeBreakdown(report *models.SalesReport) {
+	fmt.Printf("\n%s🏷️  Product Types%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 60))
+
+	fmt.Printf("\n  %-36s  %-16s  %8s  %s\n", "Product Type", "Category", "Units", "Revenue")
+	fmt.Printf("  %s  %s  %s  %s\n",
+		strings.Repeat("─", 36),
+		strings.Repeat("─", 16),
+		strings.Repeat("─", 8),
+		strings.Repeat("─", 20))
+
+	for _, productType := range sales.ProductTypeBreakdown(report) {
+		name := fmt.Sprintf("%s (%s)", productType.Name, productType.ID)
+		if productType.Name == productType.ID {
+			name = productType.ID
+		}
+
+		fmt.Printf("  %-36s  %-16s  %8s  %s\n",
+			name,
+			productType.Category.Label(),
+			formatNumber(productType.Units),
+			formatRevenue(productType.Proceeds))
+	}
+}
+
+// displayBreakdownComparison shows units by product category in both periods
+func displayBreakdownComparison(previous, current models.UnitBreakdown) {
+	fmt.Printf("\n%s🏷️  Product Types%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+
+	for _, category := range models.ProductCategories {
+		prevUnits := previous.Units(category)
+		currUnits := current.Units(category)
+		if prevUnits == 0 && currUnits == 0 {
+			continue
+		}
+		fmt.Printf("  %-18s %s → %s %s\n", category.Label()+":",
+			formatNumber(prevUnits), formatNumber(currUnits), formatUnitsChange(prevUnits, currUnits))
+	}
+	fmt.Printf("  %-18s %s → %s %s\n", "Revenue-bearing:",
+		formatNumber(previous.RevenueUnits), formatNumber(current.RevenueUnits),
+		formatUnitsChange(previous.RevenueUnits, current.RevenueUnits))
+}
+
+// formatUnitsChange formats the change between two unit counts
+func formatUnitsChange(previous, current int) string {
+	change := current - previous
+	switch {
+	case change == 0:
+		return fmt.Sprintf("%s(no change)%s", colorGray, colorReset)
+	case previous == 0:
+		return fmt.Sprintf("%s(+%s)%s", colorGreen, formatNumber(change), colorReset)
+	case change > 0:
+		return fmt.Sprintf("%s(+%s, +%.1f%%)%s", colorGreen, formatNumber(change), float64(change)/float64(previous)*100, colorReset)
+	default:
+		return fmt.Sprintf("%s(%s, %.1f%%)%s", colorRed, formatNumber(change), float64(change)/float64(previous)*100, colorReset)
+	}
+}
+
+// displayTrendBreakdown shows units by product category for each period
+func displayTrendBreakdown(trends *sales.TrendReport) {
+	fmt.Printf("\n%s🏷️  Product Types%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+
+	fmt.Printf("\n  %-12s  %10s  %8s  %11s  %9s  %15s\n",
+		"Period", "Downloads", "Updates", "Redownloads", "IAP units", "Revenue-bearing")
+	for i := range trends.Periods {
+		breakdown := trends.Breakdowns[i]
+		fmt.Printf("  %-12s  %10s  %8s  %11s  %9s  %15s\n",
+			trendLabel(trends, i),
+			formatNumber(breakdown.Downloads),
+			formatNumber(breakdown.Updates),
+			formatNumber(breakdown.Redownloads),
+			formatNumber(breakdown.InAppUnits()),
+			formatNumber(breakdown.RevenueUnits))
+	}
+}
+
+// displayRangeReport shows sales for a date range and the reports behind them
+func displayRangeReport(cmd *cobra.Command, result *sales.RangeReport) {
+	report := result.Report
+
+	// Header
+	fmt.Printf("\n%s📊 Sales from %s to %s%s %s(%d days)%s\n",
+		colorBold, result.Range.Start.Format("Jan 2, 2006"), result.Range.End.Format("Jan 2, 2006"), colorReset,
+		colorGray, result.Range.Days(), colorReset)
+	fmt.Println(strings.Repeat("─", 60))
+
+	if len(report.Apps) == 0 {
+		fmt.Printf("\n  %sNo sales in this range%s\n", colorGray, colorReset)
+	} else {
+		displaySummaryMetrics(report.Summary)
+
+		fmt.Printf("\n%s📱 App Performance%s\n", colorBold, colorReset)
+		fmt.Println(strings.Repeat("─", 60))
+		displayAppTable(viewReport(cmd, report).Apps)
+
+		if mustGetBool(cmd, "by-country") {
+			displayCountryBreakdown(report)
+		}
+
+		if mustGetBool(cmd, "product-type") {
+			displayProductTypeBreakdown(report)
+		}
+	}
+
+	displayRangeSources(result)
+}
+
+// displayRangeSources lists the reports a range was stitched from
+func displayRangeSources(result *sales.RangeReport) {
+	fmt.Printf("\n%s📚 Reports Used%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 60))
+	if len(result.Used) == 0 {
+		fmt.Printf("  %sNone%s\n", colorGray, colorReset)
+	}
+	displayReportList(result.Used, "")
+
+	if len(result.Unavailable) == 0 {
+		return
+	}
+	fmt.Printf("\n%s⚠️  Unavailable%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 60))
+	displayReportList(result.Unavailable, colorYellow)
+
+	missingDays := 0
+	for _, request := range result.Unavailable {
+		if request.Period == models.ReportFrequencyDaily {
+			missingDays++
+		}
+	}
+	if missingDays < len(result.Unavailable) {
+		fmt.Printf("\n  %sMissing monthly and weekly reports were replaced by finer reports.%s\n", colorGray, colorReset)
+	}
+	if missingDays > 0 {
+		fmt.Printf("  %s%d day(s) had no report: nothing sold or Apple hasn't published it yet.%s\n", colorGray, missingDays, colorReset)
+	}
+}
+
+// displayReportList prints report dates grouped by period
+func displayReportList(requests []sales.ReportOptions, color string) {
+	periods := []struct {
+		period models.ReportFrequency
+		label  string
+	}{
+		{models.ReportFrequencyMonthly, "Monthly"},
+		{models.ReportFrequencyWeekly, "Weekly"},
+		{models.ReportFrequencyDaily, "Daily"},
+	}
+	for _, p := range periods {
+		var dates []string
+		for i := 0; i < len(requests); i++ {
+			if requests[i].Period != p.period {
+				continue
+			}
+			// Collapse runs of consecutive days, e.g. "2025-04-01 to 2025-04-10"
+			first, last := requests[i], requests[i]
+			for p.period == models.ReportFrequencyDaily && i+1 < len(requests) &&
+				requests[i+1].Period == p.period && requests[i+1].Date.Equal(last.Date.AddDate(0, 0, 1)) {
+				i++
+				last = requests[i]
+			}
+			if first.Date.Equal(last.Date) {
+				dates = append(dates, first.FormatDate())
+			} else {
+				dates = append(dates, first.FormatDate()+" to "+last.FormatDate())
+			}
+		}
+		if len(dates) > 0 {
+			fmt.Printf("  %-8s %s%s%s\n", p.label, color, strings.Join(dates, ", "), colorReset)
+		}
 	}
 }
 
@@ -250,8 +495,8 @@ func displayTrends(trends *models.TrendAnalysis) {
 func displayComparison(cmd *cobra.Command, comp *sales.Comparison) {
 	fmt.Printf("\n%s📊 Sales Comparison%s\n", colorBold, colorReset)
 	fmt.Printf("%s vs %s\n",
-		comp.Previous.Date.Format("January 2006"),
-		comp.Current.Date.Format("January 2006"))
+		reportLabel(comp.Previous),
+		reportLabel(comp.Current))
 	fmt.Println(strings.Repeat("═", 60))
 
 	// Overall metrics
@@ -323,6 +568,10 @@ func displayComparison(cmd *cobra.Command, comp *sales.Comparison) {
 		}
 	}
 
+	if mustGetBool(cmd, "product-type") {
+		displayBreakdownComparison(comp.Previous.Summary.Breakdown, comp.Current.Summary.Breakdown)
+	}
+
 	// New and removed apps
 	if len(comp.NewApps) > 0 {
 		fmt.Printf("\n  %s✨ New Apps:%s %s\n",
@@ -341,8 +590,8 @@ func displayComparison(cmd *cobra.Command, comp *sales.Comparison) {
 func displayTrendsReport(cmd *cobra.Command, trends *sales.TrendReport) {
 	fmt.Printf("\n%s📈 Sales Trends Analysis%s\n", colorBold, colorReset)
 	fmt.Printf("Period: %s to %s (%d periods)\n",
-		trends.Periods[0].Format("Jan 2006"),
-		trends.Periods[len(trends.Periods)-1].Format("Jan 2006"),
+		trendLabel(trends, 0),
+		trendLabel(trends, len(trends.Periods)-1),
 		len(trends.Periods))
 	fmt.Println(strings.Repeat("═", 60))
 
@@ -372,9 +621,9 @@ func displayTrendsReport(cmd *cobra.Command, trends *sales.TrendReport) {
 
 	// Period summary
 	fmt.Printf("\n  Period Summary:\n")
-	for i, period := range trends.Periods {
+	for i := range trends.Periods {
 		fmt.Printf("    %s: %s units",
-			period.Format("Jan 2006"),
+			trendLabel(trends, i),
 			formatNumber(trends.TotalUnits[i]))
 		
 		// Show revenue for main currency
@@ -397,18 +646,29 @@ func displayTrendsReport(cmd *cobra.Command, trends *sales.TrendReport) {
 		fmt.Println()
 	}
 
+	if mustGetBool(cmd, "product-type") {
+		displayTrendBreakdown(trends)
+	}
+
 	// Insights
-	if len(trends.Insights) > 0 {
-		fmt.Printf("\n%s💡 Insights%s\n", colorBold, colorReset)
-		fmt.Println(strings.Repeat("─", 40))
+	displayInsights(trends.Insights)
+}
 
-		for _, insight := range trends.Insights {
-			icon := getInsightIcon(insight.Type)
-			color := getInsightColor(insight.Severity)
-			
-			fmt.Printf("\n  %s%s %s%s\n", color, icon, insight.Title, colorReset)
-			fmt.Printf("  %s\n", insight.Description)
-		}
+// displayInsights lists insights with their icons and colors
+func displayInsights(insights []sales.Insight) {
+	if len(insights) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s💡 Insights%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+
+	for _, insight := range insights {
+		icon := getInsightIcon(insight.Type)
+		color := getInsightColor(insight.Severity)
+
+		fmt.Printf("\n  %s%s %s%s\n", color, icon, insight.Title, colorReset)
+		fmt.Printf("  %s\n", insight.Description)
 	}
 }
 
@@ -465,16 +725,41 @@ func displayASCIIChart(trends *sales.TrendReport) {
 	}
 	fmt.Println()
 	
-	// Period labels
+	// Period labels: the month, or the last digit of a quarter or year
 	fmt.Print("        ")
-	for _, period := range trends.Periods {
-		fmt.Printf("%-2s", period.Format("1"))
+	for i, period := range trends.Periods {
+		label := period.Format("1")
+		if trends.Bucket != "" {
+			name := trends.Labels[i]
+			label = strings.TrimLeft(name[len(name)-2:], "-Q0")
+			if trends.Bucket == sales.PeriodYear {
+				label = name[len(name)-1:]
+			}
+		}
+		fmt.Printf("%-2s", label)
 	}
 	fmt.Println()
 }
 
+// trendLabel names the i-th period of a trend, e.g. "Mar 2025" or "2025-Q2"
+func trendLabel(trends *sales.TrendReport, i int) string {
+	if trends.Bucket != "" {
+		return trends.Labels[i]
+	}
+	return trends.Periods[i].Format("Jan 2006")
+}
+
+// reportLabel names the period a report covers, e.g. "March 2025" or "2025-Q2"
+func reportLabel(report *models.SalesReport) string {
+	if report.Period == "" {
+		// Reports rolled up from several periods carry their own name
+		return report.Summary.Period
+	}
+	return report.Date.Format("January 2006")
+}
+
 // displayDetailedReport shows a detailed report with all information
-func displayDetailedReport(report *models.SalesReport) {
+func displayDetailedReport(cmd *cobra.Command, report *models.SalesReport) {
 	// This would show more detailed information including:
 	// - Individual transactions
 	// - Device breakdowns
@@ -482,11 +767,19 @@ func displayDetailedReport(report *models.SalesReport) {
 	// - Detailed country metrics
 	// etc.
 	
-	displayMonthlyReport(nil, report)
+	displayMonthlyReport(cmd, report)
 }
 
 // Helper functions
 
+// padRight pads s with spaces to width runes
+func padRight(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
 func formatNumber(n int) string {
 	if n < 1000 {
 		return fmt.Sprintf("%d", n)
@@ -523,7 +816,7 @@ func formatRevenue(amounts map[string]float64) string {
 	// Format each currency
 	parts := make([]string, 0, len(currencies))
 	for _, currency := range currencies {
-		if amount := amounts[currency]; amount > 0 {
+		if amount := amounts[currency]; amount != 0 {
 			parts = append(parts, fmt.Sprintf("%s %.2f", currency, amount))
 		}
 	}
@@ -0,0 +1,284 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+func runExport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format := mustGetString(cmd, "format")
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("invalid format %q: use csv or json", format)
+	}
+
+	months, err := selectMonths(cmd)
+	if err != nil {
+		return err
+	}
+
+	fetched, err := fetchMonths(ctx, cmd, months)
+	if err != nil {
+		return err
+	}
+
+	var reports []*models.SalesReport
+	for _, report := range fetched {
+		if report != nil {
+			reports = append(reports, report)
+		}
+	}
+	if len(reports) == 0 {
+		return fmt.Errorf("no sales data available to export")
+	}
+
+	var w io.Writer = os.Stdout
+	path := mustGetString(cmd, "output")
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	detailed := mustGetBool(cmd, "detailed")
+	var rows int
+	if format == "json" {
+		rows, err = exportJSON(w, reports, detailed)
+	} else {
+		rows, err = exportCSV(w, reports, detailed)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export sales: %w", err)
+	}
+
+	if path != "" {
+		fmt.Fprintf(os.Stderr, "✅ Exported %d rows to %s\n", rows, path)
+	}
+	return nil
+}
+
+// fetchMonths fetches the monthly sales reports of months, applying
+// --filter. Months Apple has no report for are nil, with a warning.
+func fetchMonths(ctx context.Context, cmd *cobra.Command, months []time.Time) ([]*models.SalesReport, error) {
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, service, err := setupSalesService(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]sales.ReportOptions, len(months))
+	for i, month := range months {
+		requests[i] = sales.ReportOptions{
+			Period:       models.ReportFrequencyMonthly,
+			Date:         month,
+			ReportType:   models.ReportTypeSales,
+			VendorNumber: getVendorNumber(cmd, cfg),
+			NoCache:      mustGetBool(cmd, "no-cache"),
+			Filter:       filter,
+		}
+	}
+
+	reports, err := service.GetMultipleReports(ctx, requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sales: %w", err)
+	}
+
+	for i, report := range reports {
+		if report == nil {
+			fmt.Fprintf(os.Stderr, "⚠️  No sales data available for %s\n", requests[i].FormatDate())
+		}
+	}
+	return reports, nil
+}
+
+// selectMonths returns the months selected by --month, --last or --year,
+// oldest first. Without any of them it's the latest available month.
+func selectMonths(cmd *cobra.Command) ([]time.Time, error) {
+	month := mustGetString(cmd, "month")
+	last := mustGetInt(cmd, "last")
+	year := mustGetString(cmd, "year")
+
+	set := 0
+	for _, given := range []bool{month != "", last != 0, year != ""} {
+		if given {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("use only one of --month, --last and --year")
+	}
+
+	latest := lastMonths(1)[0]
+	switch {
+	case month != "":
+		date, err := time.Parse("2006-01", month)
+		if err != nil {
+			return nil, fmt.Errorf("invalid month format. Use YYYY-MM")
+		}
+		return []time.Time{date}, nil
+
+	case last != 0:
+		if last < 0 {
+			return nil, fmt.Errorf("--last must be positive")
+		}
+		return lastMonths(last), nil
+
+	case year != "":
+		date, err := time.Parse("2006", year)
+		if err != nil {
+			return nil, fmt.Errorf("invalid year format. Use YYYY")
+		}
+		var months []time.Time
+		for m := date; m.Year() == date.Year() && !m.After(latest); m = m.AddDate(0, 1, 0) {
+			months = append(months, m)
+		}
+		if len(months) == 0 {
+			return nil, fmt.Errorf("no sales reports are available for %s yet", year)
+		}
+		return months, nil
+
+	default:
+		return []time.Time{latest}, nil
+	}
+}
+
+// lastMonths returns the last n available months, oldest first
+func lastMonths(n int) []time.Time {
+	latest := calculateLatestAvailableMonth()
+	latest = time.Date(latest.Year(), latest.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	months := make([]time.Time, n)
+	for i := range months {
+		months[i] = latest.AddDate(0, i-n+1, 0)
+	}
+	return months
+}
+
+// exportJSON writes the reports as a JSON array. Individual sales are left
+// out unless detailed is set. It returns the number of reports written.
+func exportJSON(w io.Writer, reports []*models.SalesReport, detailed bool) (int, error) {
+	if !detailed {
+		stripped := make([]*models.SalesReport, len(reports))
+		for i, report := range reports {
+			copied := *report
+			copied.Apps = make([]models.AppSales, len(report.Apps))
+			for j, app := range report.Apps {
+				app.Sales = nil
+				copied.Apps[j] = app
+			}
+			stripped[i] = &copied
+		}
+		reports = stripped
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return len(reports), encoder.Encode(reports)
+}
+
+// exportCSV writes one row per app, country and currency, or one row per
+// sale when detailed is set. It returns the number of rows written.
+func exportCSV(w io.Writer, reports []*models.SalesReport, detailed bool) (int, error) {
+	writer := csv.NewWriter(w)
+
+	var rows [][]string
+	if detailed {
+		writer.Write([]string{
+			"period", "date", "app_id", "app", "sku", "country", "product_type",
+			"platform", "device", "units", "customer_price", "customer_currency",
+			"proceeds", "proceeds_currency", "promo_code", "parent_id", "category",
+		})
+		for _, report := range reports {
+			for _, app := range report.Apps {
+				for _, sale := range app.Sales {
+					rows = append(rows, []string{
+						report.Date.Format("2006-01"),
+						sale.Date.Format("2006-01-02"),
+						app.AppID, app.AppName, app.SKU, sale.Country, sale.ProductType,
+						sale.Platform, sale.Device, strconv.Itoa(sale.Units),
+						formatAmount(sale.CustomerPrice.Amount), sale.CustomerPrice.Currency,
+						formatAmount(sale.DeveloperProceeds.Amount), sale.DeveloperProceeds.Currency,
+						sale.PromoCode, sale.ParentID, sale.Category,
+					})
+				}
+			}
+		}
+	} else {
+		writer.Write([]string{"period", "app_id", "app", "sku", "country", "country_name", "units", "proceeds", "currency"})
+		for _, report := range reports {
+			rows = append(rows, summaryRows(report)...)
+		}
+	}
+
+	for _, row := range rows {
+		writer.Write(row)
+	}
+	writer.Flush()
+	return len(rows), writer.Error()
+}
+
+// summaryRows totals a report's sales by app, country and proceeds currency
+func summaryRows(report *models.SalesReport) [][]string {
+	type key struct{ country, currency string }
+	type total struct {
+		name     string
+		units    int
+		proceeds float64
+	}
+
+	var rows [][]string
+	for _, app := range report.Apps {
+		totals := make(map[key]*total)
+		var keys []key
+		for _, sale := range app.Sales {
+			k := key{sale.Country, sale.DeveloperProceeds.Currency}
+			t, ok := totals[k]
+			if !ok {
+				t = &total{name: sale.CountryName}
+				totals[k] = t
+				keys = append(keys, k)
+			}
+			t.units += sale.Units
+			t.proceeds += sale.Proceeds()
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].country != keys[j].country {
+				return keys[i].country < keys[j].country
+			}
+			return keys[i].currency < keys[j].currency
+		})
+		for _, k := range keys {
+			t := totals[k]
+			rows = append(rows, []string{
+				report.Date.Format("2006-01"),
+				app.AppID, app.AppName, app.SKU, k.country, t.name,
+				strconv.Itoa(t.units), formatAmount(t.proceeds), k.currency,
+			})
+		}
+	}
+	return rows
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
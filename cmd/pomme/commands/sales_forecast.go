@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/marcusziade/pomme/internal/output"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+func runForecast(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	months := mustGetInt(cmd, "months")
+	horizon := mustGetInt(cmd, "horizon")
+	backtest := mustGetInt(cmd, "backtest")
+	confidence := mustGetFloat64(cmd, "confidence")
+	if months < 1 {
+		return fmt.Errorf("--months must be positive")
+	}
+	if horizon < 1 {
+		return fmt.Errorf("--horizon must be positive")
+	}
+	if backtest < 0 || backtest >= months {
+		return fmt.Errorf("--backtest must be between 0 and --months")
+	}
+	if confidence <= 0 || confidence >= 100 {
+		return fmt.Errorf("--confidence must be between 0 and 100")
+	}
+
+	filter, err := salesFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, service, err := setupSalesService(cmd)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput := mustGetBool(cmd, "json")
+	if !jsonOutput {
+		fmt.Printf("🔮 Fitting forecasts to %d months of sales...\n", months)
+	}
+
+	trends, err := service.GetTrends(ctx, sales.TrendOptions{
+		Frequency:    models.ReportFrequencyMonthly,
+		EndDate:      calculateLatestAvailableMonth(),
+		Periods:      months,
+		ReportType:   models.ReportTypeSales,
+		VendorNumber: getVendorNumber(cmd, cfg),
+		Filter:       filter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch sales history: %w", err)
+	}
+
+	forecast, err := sales.Forecast(trends, sales.ForecastOptions{
+		Horizon:    horizon,
+		Backtest:   backtest,
+		Confidence: confidence / 100,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to forecast sales: %w", err)
+	}
+
+	if jsonOutput {
+		return output.JSON(forecast)
+	}
+
+	displayForecast(forecast, mustGetInt(cmd, "apps"))
+	return nil
+}
+
+// displayForecast shows the forecasts for total units, proceeds and the top
+// apps
+func displayForecast(forecast *sales.ForecastReport, apps int) {
+	confidence := formatConfidence(forecast.Confidence)
+
+	fmt.Printf("\n%s🔮 Sales Forecast%s\n", colorBold, colorReset)
+	fmt.Printf("Fitted on %s to %s (%d months) · %s prediction intervals\n",
+		forecast.History[0].Format("Jan 2006"),
+		forecast.History[len(forecast.History)-1].Format("Jan 2006"),
+		len(forecast.History),
+		confidence)
+	fmt.Println(strings.Repeat("═", 60))
+
+	fmt.Printf("\n%s📦 Units%s %s(%s)%s\n", colorBold, colorReset, colorGray, forecast.Units.Model, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	displayForecastSeries(forecast.Units, confidence, func(v float64) string {
+		return formatNumber(int(math.Round(v)))
+	})
+
+	currencies := make([]string, 0, len(forecast.Proceeds))
+	for currency := range forecast.Proceeds {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		return forecast.Proceeds[currencies[i]].Total() > forecast.Proceeds[currencies[j]].Total()
+	})
+	for _, currency := range currencies {
+		series := forecast.Proceeds[currency]
+		fmt.Printf("\n%s💰 Proceeds (%s)%s %s(%s)%s\n", colorBold, currency, colorReset, colorGray, series.Model, colorReset)
+		fmt.Println(strings.Repeat("─", 40))
+		displayForecastSeries(series, confidence, func(v float64) string {
+			return fmt.Sprintf("%.2f", v)
+		})
+	}
+
+	displayAppForecasts(forecast, apps)
+}
+
+// displayForecastSeries lists a series' monthly forecasts with their
+// intervals, followed by its backtest
+func displayForecastSeries(series *sales.ForecastSeries, confidence string, format func(float64) string) {
+	fmt.Printf("  %-10s %12s   %s\n", "Month", "Forecast", confidence+" interval")
+	for _, point := range series.Points {
+		fmt.Printf("  %-10s %12s   %s – %s\n",
+			point.Period.Format("Jan 2006"),
+			format(point.Value),
+			format(point.Lower),
+			format(point.Upper))
+	}
+	if len(series.Points) > 1 {
+		fmt.Printf("  %-10s %12s\n", "Total", format(series.Total()))
+	}
+
+	if backtest := series.Backtest; backtest != nil {
+		fmt.Printf("\n  %sBacktest (%d months, %s):%s MAPE %s · MAE %s · %d/%d within interval\n",
+			colorGray, len(backtest.Actual), backtest.Model, colorReset,
+			formatMAPE(backtest),
+			format(backtest.MAE),
+			backtest.Covered, len(backtest.Actual))
+		for i, actual := range backtest.Actual {
+			predicted := backtest.Predicted[i]
+			fmt.Printf("    %-10s actual %10s   forecast %10s\n",
+				backtest.Periods[i].Format("Jan 2006"),
+				format(actual),
+				format(predicted.Value))
+		}
+	}
+}
+
+// displayAppForecasts shows the monthly unit forecasts of the top apps
+func displayAppForecasts(forecast *sales.ForecastReport, limit int) {
+	if len(forecast.Apps) == 0 {
+		return
+	}
+	apps := forecast.Apps
+	if limit > 0 && len(apps) > limit {
+		apps = apps[:limit]
+	}
+
+	fmt.Printf("\n%s📱 Apps (units)%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+
+	backtest := forecast.Apps[0].Units.Backtest != nil
+	fmt.Printf("  %-24s", "App")
+	for _, period := range forecast.Periods {
+		fmt.Printf(" %10s", period.Format("Jan 2006"))
+	}
+	if backtest {
+		fmt.Printf(" %8s", "MAPE")
+	}
+	fmt.Println()
+
+	for _, app := range apps {
+		name := app.AppName
+		if len([]rune(name)) > 24 {
+			name = string([]rune(name)[:21]) + "..."
+		}
+		fmt.Printf("  %s", padRight(name, 24))
+		for _, point := range app.Units.Points {
+			fmt.Printf(" %10s", formatNumber(int(math.Round(point.Value))))
+		}
+		if backtest {
+			fmt.Printf(" %8s", formatMAPE(app.Units.Backtest))
+		}
+		fmt.Println()
+	}
+
+	if len(apps) < len(forecast.Apps) {
+		fmt.Printf("  %s... and %d more (--apps 0 shows all)%s\n", colorGray, len(forecast.Apps)-len(apps), colorReset)
+	}
+}
+
+// formatMAPE formats a backtest's MAPE, which is undefined when nothing sold
+func formatMAPE(backtest *sales.Backtest) string {
+	for _, actual := range backtest.Actual {
+		if actual != 0 {
+			return fmt.Sprintf("%.1f%%", backtest.MAPE)
+		}
+	}
+	return "n/a"
+}
+
+// formatConfidence formats a confidence level, e.g. 0.95 as "95%"
+func formatConfidence(confidence float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", confidence*100), "0"), ".") + "%"
+}
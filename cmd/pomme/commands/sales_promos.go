@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/internal/output"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/spf13/cobra"
+)
+
+func runPromos(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format := output.Format(mustGetString(cmd, "output"))
+	if mustGetBool(cmd, "json") {
+		format = output.FormatJSON
+	}
+	switch format {
+	case output.FormatTable, output.FormatJSON, output.FormatCSV:
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	months, err := selectMonths(cmd)
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatTable {
+		fmt.Printf("🎟️  Fetching promo code sales for %d months...\n", len(months))
+	}
+
+	reports, err := fetchMonths(ctx, cmd, months)
+	if err != nil {
+		return err
+	}
+
+	labels := make([]string, len(months))
+	for i, month := range months {
+		labels[i] = month.Format("2006-01")
+	}
+	report := sales.AnalyzePromos(months, labels, reports)
+
+	switch format {
+	case output.FormatTable:
+		displayPromos(report, mustGetInt(cmd, "limit"))
+		return nil
+	case output.FormatJSON:
+		return output.JSON(report)
+	default:
+		return output.NewFormatter(format, os.Stdout).Format(report.Rows)
+	}
+}
+
+// displayPromos shows code sales by code, app and country, and redemptions
+// per month
+func displayPromos(report *sales.PromoReport, limit int) {
+	fmt.Printf("\n%s🎟️  Promo & Offer Codes%s\n", colorBold, colorReset)
+	fmt.Printf("Period: %s to %s (%d months)\n",
+		report.Periods[0].Format("Jan 2006"),
+		report.Periods[len(report.Periods)-1].Format("Jan 2006"),
+		len(report.Periods))
+	fmt.Println(strings.Repeat("═", 60))
+
+	if len(report.Codes) == 0 {
+		fmt.Printf("\n  %sNo sales with promo or offer codes%s\n", colorGray, colorReset)
+		return
+	}
+
+	fmt.Printf("\n  Codes:    %d\n", len(report.Codes))
+	fmt.Printf("  Units:    %s\n", formatNumber(report.Totals.Units))
+	fmt.Printf("  Proceeds: %s\n", formatRevenue(report.Totals.Proceeds))
+
+	// Codes
+	codes := report.Codes
+	if limit > 0 && len(codes) > limit {
+		codes = codes[:limit]
+	}
+	fmt.Printf("\n%s🏷️  Codes%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Printf("  %-20s %8s  %-24s %s\n", "Code", "Units", "Proceeds", "Apps · Countries")
+	for _, code := range codes {
+		fmt.Printf("  %s %8s  %s %s\n",
+			padRight(truncateName(code.Code, 20), 20),
+			formatNumber(code.Units),
+			padRight(formatRevenue(code.Proceeds), 24),
+			fmt.Sprintf("%d · %d", len(code.Apps), len(code.Countries)))
+	}
+	displayMoreRows(len(report.Codes), len(codes), "codes")
+
+	// Apps against their sales without a code
+	fmt.Printf("\n%s📱 Apps vs. Baseline%s %s(sales without a code)%s\n", colorBold, colorReset, colorGray, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Printf("  %-24s %8s %9s %7s  %s\n", "App", "Codes", "Baseline", "Share", "Proceeds/unit: codes vs. baseline")
+	for _, app := range report.Apps {
+		currency := app.Baseline.PrimaryCurrency()
+		if currency == "" {
+			currency = app.Promo.PrimaryCurrency()
+		}
+		fmt.Printf("  %s %8s %9s %6.1f%%  %s %.2f vs. %.2f\n",
+			padRight(truncateName(app.AppName, 24), 24),
+			formatNumber(app.Promo.Units),
+			formatNumber(app.Baseline.Units),
+			app.PromoShare(),
+			currency,
+			app.Promo.ProceedsPerUnit(currency),
+			app.Baseline.ProceedsPerUnit(currency))
+	}
+
+	// Countries
+	promoCountries := report.Countries
+	if limit > 0 && len(promoCountries) > limit {
+		promoCountries = promoCountries[:limit]
+	}
+	fmt.Printf("\n%s🌍 Countries%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	for _, country := range promoCountries {
+		flag := "  "
+		if c, ok := countries.Lookup(country.Country); ok {
+			flag = c.Flag()
+		}
+		fmt.Printf("  %s %s %8s units  %s\n",
+			flag,
+			padRight(truncateName(country.CountryName, 22), 22),
+			formatNumber(country.Units),
+			strings.Join(country.Codes, ", "))
+	}
+	displayMoreRows(len(report.Countries), len(promoCountries), "countries")
+
+	if len(report.Periods) > 1 {
+		displayRedemptions(report, codes)
+	}
+}
+
+// displayRedemptions shows the units sold with each code per month
+func displayRedemptions(report *sales.PromoReport, codes []*sales.PromoCode) {
+	fmt.Printf("\n%s📈 Redemptions by Month%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+
+	fmt.Printf("  %-20s", "Code")
+	for _, label := range report.Labels {
+		fmt.Printf(" %8s", label)
+	}
+	fmt.Println()
+
+	for _, code := range codes {
+		fmt.Printf("  %s", padRight(truncateName(code.Code, 20), 20))
+		for _, units := range code.Redemptions {
+			fmt.Printf(" %8s", formatNumber(units))
+		}
+		fmt.Println()
+	}
+}
+
+// displayMoreRows notes how many rows a limit left out
+func displayMoreRows(total, shown int, what string) {
+	if shown < total {
+		fmt.Printf("  %s... and %d more %s (--limit 0 shows all)%s\n", colorGray, total-shown, what, colorReset)
+	}
+}
+
+// truncateName shortens names longer than width runes with an ellipsis
+func truncateName(name string, width int) string {
+	if runes := []rune(name); len(runes) > width {
+		return string(runes[:width-3]) + "..."
+	}
+	return name
+}
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/internal/output"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+func runRefunds(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	months := mustGetInt(cmd, "months")
+	if months < 1 {
+		return fmt.Errorf("--months must be positive")
+	}
+
+	jsonOutput := mustGetBool(cmd, "json")
+	if !jsonOutput {
+		fmt.Printf("↩️  Fetching refunds for %d months...\n", months)
+	}
+
+	periods := lastMonths(months)
+	reports, err := fetchMonths(ctx, cmd, periods)
+	if err != nil {
+		return err
+	}
+
+	labels := make([]string, len(periods))
+	for i, month := range periods {
+		labels[i] = month.Format("2006-01")
+	}
+	report := sales.AnalyzeRefunds(periods, labels, reports, sales.RefundOptions{
+		SpikeFactor: mustGetFloat64(cmd, "spike-factor"),
+		MinReturns:  mustGetInt(cmd, "min-returns"),
+	})
+
+	if jsonOutput {
+		return output.JSON(report)
+	}
+
+	displayRefunds(report, mustGetInt(cmd, "limit"))
+	return nil
+}
+
+// displayRefunds shows gross and net totals, refund rates by app and country
+// and refund spikes
+func displayRefunds(report *sales.RefundReport, limit int) {
+	fmt.Printf("\n%s↩️  Refunds & Returns%s\n", colorBold, colorReset)
+	fmt.Printf("Period: %s to %s (%d months)\n",
+		report.Periods[0].Format("Jan 2006"),
+		report.Periods[len(report.Periods)-1].Format("Jan 2006"),
+		len(report.Periods))
+	fmt.Println(strings.Repeat("═", 60))
+
+	totals := report.Totals
+	net := make(map[string]float64)
+	for currency, amount := range totals.GrossProceeds {
+		net[currency] += amount
+	}
+	for currency, amount := range totals.RefundedProceeds {
+		net[currency] -= amount
+	}
+
+	fmt.Printf("\n  Gross units:     %s\n", formatNumber(totals.GrossUnits))
+	fmt.Printf("  Returned units:  %s (%.1f%%)\n", formatNumber(totals.ReturnedUnits), totals.Rate())
+	fmt.Printf("  Net units:       %s\n", formatNumber(totals.NetUnits()))
+	fmt.Printf("\n  Gross proceeds:  %s\n", formatRevenue(totals.GrossProceeds))
+	fmt.Printf("  Refunded:        %s%s%s\n", colorRed, formatRevenue(totals.RefundedProceeds), colorReset)
+	fmt.Printf("  Net proceeds:    %s\n", formatRevenue(net))
+
+	if totals.ReturnedUnits == 0 {
+		fmt.Printf("\n  %s✅ No returns%s\n", colorGreen, colorReset)
+		return
+	}
+
+	// Apps, with the latest month against the months before
+	apps := report.Apps
+	if limit > 0 && len(apps) > limit {
+		apps = apps[:limit]
+	}
+	latest := report.Labels[len(report.Labels)-1]
+	fmt.Printf("\n%s📱 Apps%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Printf("  %-24s %9s %9s %7s %9s %9s\n", "App", "Gross", "Returned", "Rate", latest, "Before")
+	for _, app := range apps {
+		marker := ""
+		if app.Spike {
+			marker = colorRed + " ⚠️" + colorReset
+		}
+		fmt.Printf("  %s %9s %9s %6.1f%% %8.1f%% %8.1f%%%s\n",
+			padRight(truncateName(app.AppName, 24), 24),
+			formatNumber(app.GrossUnits),
+			formatNumber(app.ReturnedUnits),
+			app.Rate(),
+			app.LatestRate,
+			app.BaselineRate,
+			marker)
+	}
+	displayMoreRows(len(report.Apps), len(apps), "apps")
+
+	// Countries
+	refundCountries := report.Countries
+	if limit > 0 && len(refundCountries) > limit {
+		refundCountries = refundCountries[:limit]
+	}
+	fmt.Printf("\n%s🌍 Countries%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 40))
+	for _, country := range refundCountries {
+		displayCountryRefunds(country)
+	}
+	displayMoreRows(len(report.Countries), len(refundCountries), "countries")
+
+	displayInsights(report.Insights)
+}
+
+// displayCountryRefunds shows one country's returns and refund rate
+func displayCountryRefunds(country *sales.CountryRefunds) {
+	flag := "  "
+	if c, ok := countries.Lookup(country.Country); ok {
+		flag = c.Flag()
+	}
+	fmt.Printf("  %s %s %7s of %9s returned  %6.1f%%  %s\n",
+		flag,
+		padRight(truncateName(country.CountryName, 22), 22),
+		formatNumber(country.ReturnedUnits),
+		formatNumber(country.GrossUnits),
+		country.Rate(),
+		formatRefunded(country.Refunds))
+}
+
+// formatRefunded formats refunded proceeds, or nothing when none were
+func formatRefunded(refunds models.Refunds) string {
+	if len(refunds.RefundedProceeds) == 0 {
+		return ""
+	}
+	return colorGray + "refunded " + formatRevenue(refunds.RefundedProceeds) + colorReset
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/marcusziade/pomme/internal/config"
+	"github.com/marcusziade/pomme/internal/server"
+	"github.com/marcusziade/pomme/internal/services/cache"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/pomme"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve sales, reviews and apps over a local HTTP API and dashboard",
+	Long: `Runs a read-only HTTP/JSON API and HTML dashboard over your sales reports,
+customer reviews and apps.
+
+Clients must authenticate with a bearer token (--token or POMME_SERVE_TOKEN),
+basic auth (--basic-auth or POMME_SERVE_BASIC_AUTH), or either when both are set.
+Responses are JSON; add ?format=csv or send Accept: text/csv for a CSV download.
+Reports and reviews are cached in memory for the life of the server.
+
+Endpoints:
+  GET /?month=YYYY-MM&months=12             Dashboard (use --basic-auth for browsers)
+  GET /healthz                              Liveness check (no auth)
+  GET /api/v1/apps                          Apps
+  GET /api/v1/sales/monthly?month=YYYY-MM   Monthly report (latest by default)
+  GET /api/v1/sales/compare?current=&previous=  Compare two months (or ?months=N)
+  GET /api/v1/sales/trends?months=6&group=  Trends over the last N months
+  GET /api/v1/apps/{id}/reviews             Reviews (limit, rating, min_rating, max_rating,
+                                            territory, has_response, text, nickname,
+                                            since, until, sort)
+  GET /api/v1/apps/{id}/reviews/summary     Review summary
+
+The server stops gracefully on SIGINT or SIGTERM.`,
+	Example: `  # Serve on port 8080 with a bearer token
+  POMME_SERVE_TOKEN=s3cret pomme serve --addr :8080
+  curl -H "Authorization: Bearer s3cret" localhost:8080/api/v1/sales/monthly
+
+  # Download reviews as CSV with basic auth
+  pomme serve --basic-auth admin:s3cret
+  curl -u admin:s3cret "localhost:8080/api/v1/apps/123456789/reviews?limit=100&format=csv" -o reviews.csv`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	RootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().String("token", "", "Bearer token clients must send (default $POMME_SERVE_TOKEN)")
+	serveCmd.Flags().String("basic-auth", "", "Basic auth credentials as user:password (default $POMME_SERVE_BASIC_AUTH)")
+	serveCmd.Flags().String("vendor", "", "Vendor number (overrides config)")
+	serveCmd.Flags().Bool("quiet", false, "Don't log requests")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Auth.KeyID == "" || (cfg.Auth.IssuerID == "" && !cfg.Auth.IsIndividual()) {
+		return fmt.Errorf("authentication not configured. Run 'pomme config init' first")
+	}
+
+	token := mustGetString(cmd, "token")
+	if token == "" {
+		token = os.Getenv("POMME_SERVE_TOKEN")
+	}
+	basicAuth := mustGetString(cmd, "basic-auth")
+	if basicAuth == "" {
+		basicAuth = os.Getenv("POMME_SERVE_BASIC_AUTH")
+	}
+	var basicUser, basicPassword string
+	if basicAuth != "" {
+		var ok bool
+		basicUser, basicPassword, ok = strings.Cut(basicAuth, ":")
+		if !ok || basicUser == "" {
+			return fmt.Errorf("invalid --basic-auth, use user:password")
+		}
+	}
+	if token == "" && basicUser == "" {
+		return fmt.Errorf("refusing to serve without access control: set --token or --basic-auth")
+	}
+
+	// Sales and reviews share one cache, so repeated requests don't refetch
+	store := cache.NewMemoryCache()
+	client, err := newPommeClient(cfg, pomme.WithCache(store))
+	if err != nil {
+		return err
+	}
+
+	serverConfig := server.Config{
+		Sales:         sales.NewService(client, store),
+		Reviews:       client.Reviews(),
+		Apps:          client.Apps(),
+		VendorNumber:  getVendorNumber(cmd, cfg),
+		Token:         token,
+		BasicUser:     basicUser,
+		BasicPassword: basicPassword,
+	}
+	if !mustGetBool(cmd, "quiet") {
+		serverConfig.Logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	srv, err := server.New(serverConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	addr := mustGetString(cmd, "addr")
+	fmt.Fprintf(os.Stderr, "🚀 Serving on http://%s (Ctrl+C to stop)\n", addr)
+	if err := srv.ListenAndServe(ctx, addr); err != nil {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, "👋 Server stopped")
+	return nil
+}
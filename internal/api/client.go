@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/marcusziade/pomme/internal/auth"
@@ -13,47 +15,60 @@ import (
 
 // Client represents an App Store Connect API client
 type Client struct {
-	BaseURL     string
-	HTTPClient  *http.Client
-	AuthConfig  auth.JWTConfig
-	jwtToken    string
-	tokenExpiry time.Time
+	BaseURL    string
+	HTTPClient *http.Client
+	AuthConfig auth.JWTConfig
+	Tokens     auth.TokenProvider
 }
 
 // NewClient creates a new App Store Connect API client
 func NewClient(baseURL string, authConfig auth.JWTConfig) *Client {
+	tokens := auth.NewJWTProvider(authConfig)
 	return &Client{
-		BaseURL:    baseURL,
-		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		BaseURL: baseURL,
+		HTTPClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &ReauthTransport{Tokens: tokens},
+		},
 		AuthConfig: authConfig,
+		Tokens:     tokens,
 	}
 }
 
-// GetAuthToken returns a valid JWT token, generating a new one if needed
+// GetAuthToken returns a valid JWT token, generating a new one if needed.
+// It is safe to call from multiple goroutines.
 func (c *Client) GetAuthToken() (string, error) {
-	// Check if we already have a valid token
-	if c.jwtToken != "" && time.Now().Before(c.tokenExpiry) {
-		return c.jwtToken, nil
-	}
-
-	// Generate a new token
-	token, err := auth.GenerateToken(c.AuthConfig)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate auth token: %w", err)
-	}
+	return c.Tokens.Token(context.Background())
+}
 
-	// Update the client's token and expiry time
-	c.jwtToken = token
-	c.tokenExpiry = time.Now().Add(15 * time.Minute) // Token expires in 20 min, but we refresh after 15
+// StatusError is returned for responses with an error status. Response holds
+// the status line and headers; its body has already been read into Body.
+type StatusError struct {
+	Response *http.Response
+	Body     []byte
+	message  string
+}
 
-	return token, nil
+func (e *StatusError) Error() string {
+	return e.message
 }
 
 // Request makes an HTTP request to the App Store Connect API
 func (c *Client) Request(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.RequestWithHeaders(ctx, method, path, body, nil)
+}
+
+// RequestWithHeaders makes an HTTP request with extra headers, which override
+// the defaults. Absolute URLs, such as pagination links, are used as-is.
+func (c *Client) RequestWithHeaders(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
 	// Construct the full URL
 	url := fmt.Sprintf("%s%s", c.BaseURL, path)
-	fmt.Printf("Debug: Making %s request to %s\n", method, url)
+	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		url = path
+	}
+	if os.Getenv("POMME_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "Debug: Making %s request to %s\n", method, url)
+	}
 
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
@@ -67,9 +82,12 @@ func (c *Client) Request(ctx context.Context, method, path string, body io.Reade
 	
 	// Additional headers for App Store Connect API
 	req.Header.Set("User-Agent", "pomme-cli/1.0")
+	for name, values := range header {
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
 
 	// Add authorization token
-	token, err := c.GetAuthToken()
+	token, err := c.Tokens.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +107,15 @@ func (c *Client) Request(ctx context.Context, method, path string, body io.Reade
 		// Read the full response body for debugging
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("API request failed with status: %s (failed to read response body: %v)", resp.Status, err)
+			return nil, &StatusError{
+				Response: resp,
+				message:  fmt.Sprintf("API request failed with status: %s (failed to read response body: %v)", resp.Status, err),
+			}
+		}
+		statusErr := &StatusError{
+			Response: resp,
+			Body:     bodyBytes,
+			message:  fmt.Sprintf("API request failed with status: %s", resp.Status),
 		}
 		
 		// Try to parse as JSON error
@@ -103,10 +129,10 @@ func (c *Client) Request(ctx context.Context, method, path string, body io.Reade
 		}
 		
 		if err := json.Unmarshal(bodyBytes, &errResp); err == nil && len(errResp.Errors) > 0 {
-			return nil, fmt.Errorf("API error: %s - %s", errResp.Errors[0].Code, errResp.Errors[0].Detail)
+			statusErr.message = fmt.Sprintf("API error: %s - %s", errResp.Errors[0].Code, errResp.Errors[0].Detail)
 		}
 		
-		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
+		return nil, statusErr
 	}
 
 	return resp, nil
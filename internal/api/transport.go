@@ -0,0 +1,219 @@
+package api
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/auth"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; 1 disables retries
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each one after
+	MaxDelay    time.Duration // Upper bound for a single delay, including Retry-After
+}
+
+// DefaultRetryPolicy retries twice, waiting half a second and then a second
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// RetryTransport retries GET and HEAD requests that fail with a network
+// error, 429 Too Many Requests or a 5xx status. Retry-After is honored.
+type RetryTransport struct {
+	Base   http.RoundTripper
+	Policy RetryPolicy
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return base.RoundTrip(req)
+	}
+
+	delay := t.Policy.BaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if attempt >= t.Policy.MaxAttempts || !retryable(resp, err) {
+			return resp, err
+		}
+
+		wait := delay
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				wait = after
+			}
+			// Drain so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if t.Policy.MaxDelay > 0 && wait > t.Policy.MaxDelay {
+			wait = t.Policy.MaxDelay
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// retryable reports whether a request is worth trying again
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter reads a Retry-After header given in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+// ReauthTransport retries a request once with a freshly signed token when the
+// API rejects it with 401 Unauthorized, e.g. because the cached token was
+// revoked. Only token providers that implement auth.TokenInvalidator are
+// asked for a new token, and requests whose body can't be replayed aren't
+// retried.
+type ReauthTransport struct {
+	Base   http.RoundTripper
+	Tokens auth.TokenProvider
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ReauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || req.Header.Get("Authorization") == "" {
+		return resp, err
+	}
+	tokens, ok := t.Tokens.(auth.TokenInvalidator)
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if !ok || !replayable {
+		return resp, nil
+	}
+
+	// Keep the 401 if no new token or body can be had
+	tokens.Invalidate()
+	token, err := t.Tokens.Token(req.Context())
+	if err != nil {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	// Drain so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	return base.RoundTrip(retry)
+}
+
+// LoggingTransport logs each request with its status and duration
+type LoggingTransport struct {
+	Base   http.RoundTripper
+	Logger *slog.Logger
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Logger.Warn("api request failed",
+			"method", req.Method, "path", req.URL.Path, "duration", elapsed, "error", err)
+		return nil, err
+	}
+	t.Logger.Debug("api request",
+		"method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "duration", elapsed)
+	return resp, nil
+}
+
+// RequestObserver records the outcome of each API request. Implementations
+// must be safe for concurrent use.
+type RequestObserver interface {
+	// ObserveRequest is called once per attempt. status is 0 when err is set.
+	ObserveRequest(method, endpoint string, status int, duration time.Duration, err error)
+}
+
+// ObservingTransport reports each request to an observer, with the path
+// reduced to an endpoint by Endpoint
+type ObservingTransport struct {
+	Base     http.RoundTripper
+	Observer RequestObserver
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ObservingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	t.Observer.ObserveRequest(req.Method, Endpoint(req.URL.Path), status, time.Since(start), err)
+	return resp, err
+}
+
+// Endpoint replaces the resource IDs in an API path with {id}, e.g.
+// /v1/apps/123/customerReviews becomes /v1/apps/{id}/customerReviews, so
+// requests can be grouped without one series per resource
+func Endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		// Versions like v1 are the only segments with digits that aren't IDs
+		if i == 1 && len(segment) > 1 && segment[0] == 'v' {
+			continue
+		}
+		if strings.ContainsAny(segment, "0123456789") {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
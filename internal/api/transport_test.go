@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcusziade/pomme/internal/auth"
+)
+
+// rotatingTokens hands out "token-N", moving to the next one on Invalidate
+type rotatingTokens struct {
+	n int
+}
+
+func (p *rotatingTokens) Token(ctx context.Context) (string, error) {
+	return fmt.Sprintf("token-%d", p.n), nil
+}
+
+func (p *rotatingTokens) Invalidate() {
+	p.n++
+}
+
+// staticTokens can't drop its token
+type staticTokens struct{}
+
+func (staticTokens) Token(ctx context.Context) (string, error) {
+	return "token-0", nil
+}
+
+func TestReauthTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokens   auth.TokenProvider
+		accept   string
+		status   int
+		requests int
+	}{
+		{"valid token", &rotatingTokens{}, "token-0", http.StatusOK, 1},
+		{"retried with a new token", &rotatingTokens{}, "token-1", http.StatusOK, 2},
+		{"retried once", &rotatingTokens{}, "token-2", http.StatusUnauthorized, 2},
+		{"provider can't invalidate", staticTokens{}, "token-1", http.StatusUnauthorized, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "payload" {
+					t.Errorf("request %d body = %q, want it replayed", requests, body)
+				}
+				if r.Header.Get("Authorization") != "Bearer "+tt.accept {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			token, _ := tt.tokens.Token(context.Background())
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			client := &http.Client{Transport: &ReauthTransport{Tokens: tt.tokens}}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if requests != tt.requests {
+				t.Errorf("sent %d requests, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -16,19 +17,87 @@ func ContainsPEMHeaders(s string) bool {
 	return strings.Contains(s, "-----BEGIN") && strings.Contains(s, "-----END")
 }
 
+const (
+	// KeyTypeTeam keys belong to the team and sign tokens with the issuer ID
+	KeyTypeTeam = "team"
+
+	// KeyTypeIndividual keys belong to a user; their tokens have no issuer
+	// and carry sub: user instead
+	KeyTypeIndividual = "individual"
+
+	// MaxTokenLifetime is the longest token lifetime Apple accepts
+	MaxTokenLifetime = 20 * time.Minute
+
+	// audience is the aud claim App Store Connect expects
+	audience = "appstoreconnect-v1"
+)
+
 // JWTConfig contains the configuration needed to generate JWT tokens
 type JWTConfig struct {
-	KeyID          string
-	IssuerID       string
-	PrivateKeyPEM  string
-	Expiration     time.Duration
+	KeyID         string
+	IssuerID      string // Required for team keys
+	KeyType       string // KeyTypeTeam (default) or KeyTypeIndividual
+	PrivateKeyPEM string
+	KeySource     KeySource     // Used when PrivateKeyPEM is empty
+	Expiration    time.Duration // Defaults to MaxTokenLifetime
+	Scopes        []string      // Optional, e.g. "GET /v1/apps?filter[platform]=IOS"
 }
 
-// GenerateToken creates a new JWT token for App Store Connect API authentication
-func GenerateToken(config JWTConfig) (string, error) {
-	
+// Lifetime returns how long tokens generated with this config are valid
+func (c JWTConfig) Lifetime() time.Duration {
+	if c.Expiration <= 0 {
+		return MaxTokenLifetime
+	}
+	return c.Expiration
+}
+
+// Validate checks the parts of the config that don't need the private key
+func (c JWTConfig) Validate() error {
+	switch strings.ToLower(c.KeyType) {
+	case "", KeyTypeTeam:
+		if c.IssuerID == "" {
+			return fmt.Errorf("issuer ID is required for team keys (set key_type: individual for individual keys)")
+		}
+	case KeyTypeIndividual:
+	default:
+		return fmt.Errorf("invalid key type %q (valid: %s, %s)", c.KeyType, KeyTypeTeam, KeyTypeIndividual)
+	}
+
+	if c.Expiration < 0 {
+		return fmt.Errorf("token lifetime must be positive, got %s", c.Expiration)
+	}
+	if c.Expiration > MaxTokenLifetime {
+		return fmt.Errorf("token lifetime %s exceeds Apple's maximum of %s", c.Expiration, MaxTokenLifetime)
+	}
+
+	for _, scope := range c.Scopes {
+		if err := ValidateScope(scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateScope checks that a scope has the "METHOD /v1/path[?query]" form
+// Apple uses for the scope claim
+func ValidateScope(scope string) error {
+	method, path, ok := strings.Cut(scope, " ")
+	if !ok || !strings.HasPrefix(path, "/v1/") || strings.ContainsAny(path, " \t") {
+		return fmt.Errorf("invalid scope %q: expected e.g. \"GET /v1/salesReports?filter[vendorNumber]=123\"", scope)
+	}
+	switch method {
+	case "GET", "POST", "PATCH", "DELETE":
+		return nil
+	default:
+		return fmt.Errorf("invalid scope %q: unknown method %s", scope, method)
+	}
+}
+
+// ParsePrivateKey parses an App Store Connect private key, which is a PKCS#8
+// ECDSA key. Bare base64 without PEM headers is accepted too.
+func ParsePrivateKey(privateKeyPEM string) (*ecdsa.PrivateKey, error) {
 	// The private key from Apple might already be in PEM format
-	privateKeyData := config.PrivateKeyPEM
+	privateKeyData := strings.TrimSpace(privateKeyPEM)
 	
 	// Check if the key already has PEM headers
 	if !ContainsPEMHeaders(privateKeyData) {
@@ -38,37 +107,64 @@ func GenerateToken(config JWTConfig) (string, error) {
 	// Parse the private key
 	block, _ := pem.Decode([]byte(privateKeyData))
 	if block == nil {
-		return "", fmt.Errorf("failed to parse PEM block containing the private key")
+		return nil, fmt.Errorf("failed to parse PEM block containing the private key")
 	}
-
 	
 	privKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse private key: %w", err)
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
-
 	
 	ecdsaKey, ok := privKey.(*ecdsa.PrivateKey)
 	if !ok {
-		return "", fmt.Errorf("private key is not an ECDSA key")
+		return nil, fmt.Errorf("private key is not an ECDSA key")
 	}
 	
+	return ecdsaKey, nil
+}
 
-	// Set the expiration time
-	exp := time.Now().Add(config.Expiration)
-	if config.Expiration == 0 {
-		// Default to 20 minutes if not specified
-		exp = time.Now().Add(20 * time.Minute)
+// GenerateToken creates a new JWT token for App Store Connect API authentication
+func GenerateToken(config JWTConfig) (string, error) {
+	if err := config.Validate(); err != nil {
+		return "", err
+	}
+	
+	privateKeyData := config.PrivateKeyPEM
+	if privateKeyData == "" && config.KeySource != nil {
+		var err error
+		privateKeyData, err = config.KeySource.PrivateKey(context.Background())
+		if err != nil {
+			return "", err
+		}
+	}
+	
+	ecdsaKey, err := ParsePrivateKey(privateKeyData)
+	if err != nil {
+		return "", err
 	}
+	
+
+	// Set the expiration time
+	now := time.Now()
+	exp := now.Add(config.Lifetime())
 
 	// Create the JWT claims
 	// According to Apple documentation, the JWT must include specific claims
-	now := time.Now()
 	claims := jwt.MapClaims{
-		"iss": config.IssuerID,                       // Issuer
 		"iat": now.Unix(),                            // Issued at
 		"exp": exp.Unix(),                            // Expiration
-		"aud": "appstoreconnect-v1",                  // Audience
+		"aud": audience,                              // Audience
+	}
+	
+	// Team keys identify the team; individual keys identify the user instead
+	if strings.EqualFold(config.KeyType, KeyTypeIndividual) {
+		claims["sub"] = "user"
+	} else {
+		claims["iss"] = config.IssuerID
+	}
+	
+	if len(config.Scopes) > 0 {
+		claims["scope"] = config.Scopes
 	}
 
 	// Create the token with ES256 (ECDSA using P-256 and SHA-256)
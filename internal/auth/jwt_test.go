@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJWTConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config JWTConfig
+		err    string
+	}{
+		{"team key", JWTConfig{IssuerID: "issuer"}, ""},
+		{"individual key", JWTConfig{KeyType: KeyTypeIndividual}, ""},
+		{"missing issuer", JWTConfig{}, "issuer ID is required"},
+		{"invalid key type", JWTConfig{KeyType: "personal"}, "invalid key type"},
+		{"maximum lifetime", JWTConfig{IssuerID: "issuer", Expiration: MaxTokenLifetime}, ""},
+		{"lifetime too long", JWTConfig{IssuerID: "issuer", Expiration: MaxTokenLifetime + time.Second}, "exceeds Apple's maximum"},
+		{"negative lifetime", JWTConfig{IssuerID: "issuer", Expiration: -time.Minute}, "must be positive"},
+		{"invalid scope", JWTConfig{IssuerID: "issuer", Scopes: []string{"apps"}}, "invalid scope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.err == "" && err != nil {
+				t.Errorf("Validate() = %v, want no error", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Validate() = %v, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/marcusziade/pomme/internal/config"
+)
+
+// PrivateKeyEnv holds the PEM contents of the private key for the env source
+const PrivateKeyEnv = "POMME_AUTH_PRIVATE_KEY"
+
+// Key source names as they appear in the config file
+const (
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceKeyring = "keyring"
+	SourcePass    = "pass"
+	SourceCommand = "command"
+)
+
+// KeySources lists the supported key source names
+var KeySources = []string{SourceFile, SourceEnv, SourceKeyring, SourcePass, SourceCommand}
+
+// KeySource supplies the PEM-encoded App Store Connect private key
+type KeySource interface {
+	// PrivateKey returns the PEM contents of the key
+	PrivateKey(ctx context.Context) (string, error)
+
+	// String describes where the key comes from, without revealing it
+	String() string
+}
+
+// KeyStore is a key source that can also store a key
+type KeyStore interface {
+	KeySource
+
+	// StoreKey saves the PEM contents of the key
+	StoreKey(ctx context.Context, pem string) error
+}
+
+// NewKeySource creates the named key source. The meaning of ref depends on
+// the source: a file path, an environment variable, a keyring or pass entry
+// name, or a shell command that prints the key.
+func NewKeySource(kind, ref string) (KeySource, error) {
+	switch strings.ToLower(kind) {
+	case SourceFile, "":
+		if ref == "" {
+			return nil, fmt.Errorf("private key path not set in config")
+		}
+		return &FileKeySource{Path: ref}, nil
+	case SourceEnv:
+		if ref == "" {
+			ref = PrivateKeyEnv
+		}
+		return &EnvKeySource{Var: ref}, nil
+	case SourceKeyring:
+		if ref == "" {
+			return nil, fmt.Errorf("keyring entry (key_ref) not set in config")
+		}
+		return &KeyringKeySource{Entry: ref}, nil
+	case SourcePass:
+		if ref == "" {
+			return nil, fmt.Errorf("pass entry (key_ref) not set in config")
+		}
+		return &PassKeySource{Entry: ref}, nil
+	case SourceCommand:
+		if ref == "" {
+			return nil, fmt.Errorf("key command (key_ref) not set in config")
+		}
+		return &CommandKeySource{Command: ref}, nil
+	default:
+		return nil, fmt.Errorf("unknown key source %q (valid: %s)", kind, strings.Join(KeySources, ", "))
+	}
+}
+
+// KeySourceFromConfig picks the key source for the configured credentials.
+// Without an explicit source, a key in POMME_AUTH_PRIVATE_KEY takes precedence
+// over the key file.
+func KeySourceFromConfig(cfg config.AuthConfig) (KeySource, error) {
+	kind := cfg.KeySource
+	ref := cfg.KeyRef
+
+	if kind == "" {
+		if os.Getenv(PrivateKeyEnv) != "" {
+			return &EnvKeySource{Var: PrivateKeyEnv}, nil
+		}
+		kind = SourceFile
+	}
+
+	if strings.EqualFold(kind, SourceFile) && ref == "" {
+		ref = cfg.PrivateKeyPath
+	}
+	if ref == "" && (strings.EqualFold(kind, SourceKeyring) || strings.EqualFold(kind, SourcePass)) && cfg.KeyID != "" {
+		ref = DefaultKeyRef(kind, cfg.KeyID)
+	}
+
+	return NewKeySource(kind, ref)
+}
+
+// DefaultKeyRef is the entry name used for a key imported into a store
+func DefaultKeyRef(kind, keyID string) string {
+	if strings.EqualFold(kind, SourcePass) {
+		return "pomme/" + keyID
+	}
+	return keyID
+}
+
+// FileKeySource reads the key from a .p8 file, restricting files that other
+// users can read to the owner
+type FileKeySource struct {
+	Path string
+}
+
+// PrivateKey implements KeySource
+func (s *FileKeySource) PrivateKey(ctx context.Context) (string, error) {
+	path := expandHome(s.Path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read private key: %w", err)
+	}
+	restrictKeyPermissions(path, info)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read private key: %w", err)
+	}
+	return string(data), nil
+}
+
+// StoreKey implements KeyStore. The file is written with owner-only permissions.
+func (s *FileKeySource) StoreKey(ctx context.Context, pem string) error {
+	path := expandHome(s.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("could not create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(pem), 0o600); err != nil {
+		return fmt.Errorf("could not write private key: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0o600)
+}
+
+func (s *FileKeySource) String() string {
+	return "file " + s.Path
+}
+
+// SameFile reports whether path names the key file, comparing cleaned
+// absolute paths and, when both exist, the files themselves so links count
+func (s *FileKeySource) SameFile(path string) bool {
+	keyPath, err := filepath.Abs(expandHome(s.Path))
+	if err != nil {
+		return false
+	}
+	other, err := filepath.Abs(expandHome(path))
+	if err != nil {
+		return false
+	}
+	if keyPath == other {
+		return true
+	}
+
+	keyInfo, err := os.Stat(keyPath)
+	if err != nil {
+		return false
+	}
+	otherInfo, err := os.Stat(other)
+	return err == nil && os.SameFile(keyInfo, otherInfo)
+}
+
+// restrictKeyPermissions tightens key files readable by the group or others to
+// 0600, as config init does, and warns when it can't. Earlier versions wrote
+// keys 0644, so an open key is never refused. Windows has no comparable
+// permission bits, so nothing is checked there.
+func restrictKeyPermissions(path string, info os.FileInfo) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	mode := info.Mode().Perm()
+	if mode&0o077 == 0 {
+		return
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: private key %s is accessible by other users (mode %04o); run: chmod 600 %s\n", path, mode, path)
+	}
+}
+
+// EnvKeySource reads the PEM contents of the key from an environment variable
+type EnvKeySource struct {
+	Var string
+}
+
+// PrivateKey implements KeySource
+func (s *EnvKeySource) PrivateKey(ctx context.Context) (string, error) {
+	value := os.Getenv(s.Var)
+	if value == "" {
+		return "", fmt.Errorf("environment variable %s is not set", s.Var)
+	}
+	// CI systems often store multi-line secrets with literal \n
+	if !strings.Contains(value, "\n") {
+		value = strings.ReplaceAll(value, `\n`, "\n")
+	}
+	return value, nil
+}
+
+func (s *EnvKeySource) String() string {
+	return "environment variable " + s.Var
+}
+
+// KeyringKeySource keeps the key in the Secret Service keyring (GNOME Keyring,
+// KWallet) through the secret-tool command
+type KeyringKeySource struct {
+	Entry string
+}
+
+// PrivateKey implements KeySource
+func (s *KeyringKeySource) PrivateKey(ctx context.Context) (string, error) {
+	out, err := runTool(ctx, nil, "secret-tool", "lookup", "service", "pomme", "key", s.Entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to read key from keyring: %w", err)
+	}
+	if strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("no key named %q in keyring", s.Entry)
+	}
+	return out, nil
+}
+
+// StoreKey implements KeyStore
+func (s *KeyringKeySource) StoreKey(ctx context.Context, pem string) error {
+	_, err := runTool(ctx, strings.NewReader(pem), "secret-tool", "store",
+		"--label=pomme App Store Connect key "+s.Entry, "service", "pomme", "key", s.Entry)
+	if err != nil {
+		return fmt.Errorf("failed to store key in keyring: %w", err)
+	}
+	return nil
+}
+
+func (s *KeyringKeySource) String() string {
+	return "keyring entry " + s.Entry
+}
+
+// PassKeySource keeps the key in the pass password store
+type PassKeySource struct {
+	Entry string
+}
+
+// PrivateKey implements KeySource
+func (s *PassKeySource) PrivateKey(ctx context.Context) (string, error) {
+	out, err := runTool(ctx, nil, "pass", "show", s.Entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to read key from pass: %w", err)
+	}
+	return out, nil
+}
+
+// StoreKey implements KeyStore
+func (s *PassKeySource) StoreKey(ctx context.Context, pem string) error {
+	if _, err := runTool(ctx, strings.NewReader(pem), "pass", "insert", "--multiline", "--force", s.Entry); err != nil {
+		return fmt.Errorf("failed to store key in pass: %w", err)
+	}
+	return nil
+}
+
+func (s *PassKeySource) String() string {
+	return "pass entry " + s.Entry
+}
+
+// CommandKeySource runs a shell command that prints the key, such as
+// `op read op://vault/asc/key.p8`
+type CommandKeySource struct {
+	Command string
+}
+
+// PrivateKey implements KeySource
+func (s *CommandKeySource) PrivateKey(ctx context.Context) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	out, err := runTool(ctx, nil, shell, flag, s.Command)
+	if err != nil {
+		return "", fmt.Errorf("key command failed: %w", err)
+	}
+	return out, nil
+}
+
+func (s *CommandKeySource) String() string {
+	return "command " + s.Command
+}
+
+// runTool runs an external helper and returns its standard output. Standard
+// error is included in the returned error to explain failures.
+func runTool(ctx context.Context, stdin *strings.Reader, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return stdout.String(), nil
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
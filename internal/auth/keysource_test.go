@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFileKeySourceRestrictsOpenKey(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no permission bits on Windows")
+	}
+
+	// Earlier versions of config init wrote keys 0644
+	path := filepath.Join(t.TempDir(), "AuthKey_TEST.p8")
+	if err := os.WriteFile(path, []byte("key"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := (&FileKeySource{Path: path}).PrivateKey(context.Background())
+	if err != nil || key != "key" {
+		t.Fatalf("PrivateKey = %q, %v, want the key", key, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("key mode = %04o, want 0600", mode)
+	}
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenProvider supplies bearer tokens for API requests. Implementations must
+// be safe for concurrent use.
+type TokenProvider interface {
+	// Token returns a token that stays valid for at least a short while
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is implemented by token providers that can drop a cached
+// token, so a request the API rejected with 401 can be retried with a new one
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// JWTProvider signs tokens from a JWTConfig and reuses each one until it
+// nears expiry. Concurrent callers share a single refresh.
+type JWTProvider struct {
+	config JWTConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expiry  time.Time
+	refresh *tokenCall // In-flight refresh, if any
+}
+
+// tokenCall is a refresh that other callers can wait on
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// NewJWTProvider creates a token provider for the given config
+func NewJWTProvider(config JWTConfig) *JWTProvider {
+	return &JWTProvider{config: config, now: time.Now}
+}
+
+// Token implements TokenProvider
+func (p *JWTProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	if p.token != "" && p.now().Before(p.refreshAt()) {
+		token := p.token
+		p.mu.Unlock()
+		return token, nil
+	}
+
+	call := p.refresh
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		p.refresh = call
+		go p.generate(call)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Invalidate drops the cached token so the next call signs a new one, e.g.
+// after the API rejected it
+func (p *JWTProvider) Invalidate() {
+	p.mu.Lock()
+	p.token = ""
+	p.expiry = time.Time{}
+	p.mu.Unlock()
+}
+
+// generate signs a token and publishes the result to everyone waiting on call.
+// It runs detached from any caller so one cancelled request doesn't fail the
+// others.
+func (p *JWTProvider) generate(call *tokenCall) {
+	token, err := GenerateToken(p.config)
+	var expiry time.Time
+	if err == nil {
+		expiry, err = TokenExpiry(token)
+	}
+	if err != nil {
+		call.err = fmt.Errorf("failed to generate auth token: %w", err)
+	} else {
+		call.token = token
+	}
+
+	p.mu.Lock()
+	if err == nil {
+		p.token = token
+		p.expiry = expiry
+	}
+	p.refresh = nil
+	p.mu.Unlock()
+
+	close(call.done)
+}
+
+// refreshAt is when the cached token should be replaced: with a quarter of
+// its lifetime to spare, e.g. after 15 of 20 minutes
+func (p *JWTProvider) refreshAt() time.Time {
+	return p.expiry.Add(-p.config.Lifetime() / 4)
+}
+
+// DecodeToken returns the header and claims of a JWT without verifying its
+// signature
+func DecodeToken(token string) (header, claims map[string]interface{}, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, fmt.Errorf("malformed token: expected 3 parts, got %d", len(parts))
+	}
+
+	if header, err = decodeSegment(parts[0]); err != nil {
+		return nil, nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	if claims, err = decodeSegment(parts[1]); err != nil {
+		return nil, nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	return header, claims, nil
+}
+
+// TokenExpiry reads the exp claim of a JWT
+func TokenExpiry(token string) (time.Time, error) {
+	_, claims, err := DecodeToken(token)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("token has no exp claim")
+	}
+	return time.Unix(int64(exp), 0), nil
+}
+
+func decodeSegment(segment string) (map[string]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
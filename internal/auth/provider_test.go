@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingKeySource hands out a test key and counts how often it's read,
+// which is once per signed token. Reads wait for release when it's set.
+type countingKeySource struct {
+	pem     string
+	reads   atomic.Int32
+	release chan struct{}
+}
+
+func (s *countingKeySource) PrivateKey(ctx context.Context) (string, error) {
+	s.reads.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+	return s.pem, nil
+}
+
+func (s *countingKeySource) String() string {
+	return "test key"
+}
+
+func newTestProvider(t *testing.T) (*JWTProvider, *countingKeySource) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := &countingKeySource{pem: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))}
+	return NewJWTProvider(JWTConfig{
+		KeyID:     "ABC123DEFG",
+		IssuerID:  "a5ebdab5-0ceb-463c-8151-195b902f117b",
+		KeySource: source,
+	}), source
+}
+
+func TestJWTProviderSharesConcurrentRefresh(t *testing.T) {
+	provider, source := newTestProvider(t)
+	source.release = make(chan struct{})
+
+	const callers = 20
+	tokens := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = provider.Token(context.Background())
+		}(i)
+	}
+
+	// Let every caller queue up behind the first refresh before it finishes
+	time.Sleep(50 * time.Millisecond)
+	close(source.release)
+	wg.Wait()
+
+	for i := range tokens {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: %v", i, errs[i])
+		}
+		if tokens[i] != tokens[0] {
+			t.Fatalf("caller %d got a different token", i)
+		}
+	}
+	if reads := source.reads.Load(); reads != 1 {
+		t.Errorf("signed %d tokens for %d concurrent callers, want 1", reads, callers)
+	}
+}
+
+func TestJWTProviderCachesUntilRefresh(t *testing.T) {
+	provider, source := newTestProvider(t)
+	ctx := context.Background()
+
+	first, err := provider.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := provider.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first || source.reads.Load() != 1 {
+		t.Errorf("second call signed a new token, want the cached one")
+	}
+
+	// A quarter of the lifetime before expiry, the token is replaced
+	expiry, err := TokenExpiry(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.now = func() time.Time { return expiry.Add(-MaxTokenLifetime / 4) }
+	if _, err := provider.Token(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if reads := source.reads.Load(); reads != 2 {
+		t.Errorf("signed %d tokens, want a refresh near expiry", reads)
+	}
+}
+
+func TestJWTProviderInvalidate(t *testing.T) {
+	provider, source := newTestProvider(t)
+	ctx := context.Background()
+
+	first, err := provider.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.Invalidate()
+	second, err := provider.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first || source.reads.Load() != 2 {
+		t.Errorf("Invalidate didn't force a new token")
+	}
+}
+
+func TestJWTProviderCancelledCaller(t *testing.T) {
+	provider, source := newTestProvider(t)
+	source.release = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := provider.Token(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Token with a cancelled context = %v, want context.Canceled", err)
+	}
+
+	// The refresh carries on for everyone else
+	close(source.release)
+	if _, err := provider.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if reads := source.reads.Load(); reads != 1 {
+		t.Errorf("signed %d tokens, want the cancelled caller's refresh reused", reads)
+	}
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// keyIDPattern matches App Store Connect key IDs, e.g. 73TT63DP5R
+	keyIDPattern = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+
+	// issuerIDPattern matches issuer IDs, which are UUIDs
+	issuerIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// ValidateKeyID checks that a key ID has Apple's 10 character format
+func ValidateKeyID(keyID string) error {
+	if !keyIDPattern.MatchString(keyID) {
+		return fmt.Errorf("invalid key ID %q: expected 10 uppercase letters and digits, e.g. 73TT63DP5R", keyID)
+	}
+	return nil
+}
+
+// ValidateIssuerID checks that an issuer ID is a UUID
+func ValidateIssuerID(issuerID string) error {
+	if !issuerIDPattern.MatchString(issuerID) {
+		return fmt.Errorf("invalid issuer ID %q: expected a UUID, e.g. a5ebdab5-0ceb-463c-8151-195b902f117b", issuerID)
+	}
+	return nil
+}
+
+// ValidatePrivateKey checks that a key is a PKCS#8 ECDSA key on the P-256
+// curve, which is what App Store Connect issues and ES256 requires
+func ValidatePrivateKey(privateKeyPEM string) error {
+	if strings.Contains(privateKeyPEM, "BEGIN EC PRIVATE KEY") {
+		return fmt.Errorf("key is in SEC 1 format; App Store Connect keys are PKCS#8 (BEGIN PRIVATE KEY)")
+	}
+
+	key, err := ParsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return err
+	}
+	if key.Curve != elliptic.P256() {
+		return fmt.Errorf("key uses curve %s, expected P-256", key.Curve.Params().Name)
+	}
+	return nil
+}
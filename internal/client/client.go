@@ -6,8 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
-	"time"
+	"strings"
 
 	"github.com/marcusziade/pomme/internal/api"
 	"github.com/marcusziade/pomme/internal/auth"
@@ -21,26 +20,40 @@ type Client struct {
 
 // New creates a new client from config
 func New(cfg *config.Config) (*Client, error) {
-	// Read private key from file
-	privateKeyData, err := os.ReadFile(cfg.Auth.PrivateKeyPath)
+	// The key is read from its source whenever a token is generated
+	keySource, err := auth.KeySourceFromConfig(cfg.Auth)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read private key: %w", err)
+		return nil, err
 	}
 	
 	authConfig := auth.JWTConfig{
 		KeyID:          cfg.Auth.KeyID,
 		IssuerID:       cfg.Auth.IssuerID,
-		PrivateKeyPEM:  string(privateKeyData),
-		Expiration:     20 * time.Minute,
+		KeyType:        cfg.Auth.KeyType,
+		KeySource:      keySource,
+		Expiration:     cfg.Auth.TokenTTL,
+		Scopes:         cfg.Auth.Scopes,
 	}
 	
-	apiClient := api.NewClient(cfg.API.BaseURL, authConfig)
+	// Service paths carry their own /v1 prefix, so use the bare host
+	baseURL := strings.TrimSuffix(strings.TrimRight(cfg.API.BaseURL, "/"), "/v1")
+	apiClient := api.NewClient(baseURL, authConfig)
 	
 	return &Client{
 		apiClient: apiClient,
 	}, nil
 }
 
+// NewFromAPI wraps an existing API client
+func NewFromAPI(apiClient *api.Client) *Client {
+	return &Client{apiClient: apiClient}
+}
+
+// API returns the underlying App Store Connect API client
+func (c *Client) API() *api.Client {
+	return c.apiClient
+}
+
 // NewRequest creates a new HTTP request with JSON body
 func (c *Client) NewRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
 	var bodyReader *bytes.Reader
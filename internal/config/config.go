@@ -1,10 +1,12 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Config contains all the configuration settings for the application
@@ -15,9 +17,20 @@ type Config struct {
 }
 
 type AuthConfig struct {
-	KeyID          string `json:"key_id"`
-	IssuerID       string `json:"issuer_id"`
-	PrivateKeyPath string `json:"private_key_path"`
+	KeyID          string        `json:"key_id"`
+	IssuerID       string        `json:"issuer_id"`
+	PrivateKeyPath string        `json:"private_key_path"`
+	KeySource      string        `json:"key_source"` // file, env, keyring, pass or command
+	KeyRef         string        `json:"key_ref"`    // Source-specific entry name or command
+	KeyType        string        `json:"key_type"`   // team (default) or individual
+	Scopes         []string      `json:"scopes"`     // Optional JWT scope claim
+	TokenTTL       time.Duration `json:"token_ttl"`  // Token lifetime, at most 20 minutes
+}
+
+// IsIndividual reports whether the credentials are for an individual API key,
+// which has no issuer ID
+func (a AuthConfig) IsIndividual() bool {
+	return strings.EqualFold(a.KeyType, "individual")
 }
 
 type APIConfig struct {
@@ -26,8 +39,9 @@ type APIConfig struct {
 }
 
 type DefaultsConfig struct {
-	OutputFormat string `json:"output_format"`
-	VendorNumber string `json:"vendor_number"`
+	OutputFormat    string `json:"output_format"`
+	VendorNumber    string `json:"vendor_number"`
+	FiscalYearStart string `json:"fiscal_year_start"` // Month name or number, e.g. april or 4
 }
 
 // Load reads the config file and returns a Config struct
@@ -127,8 +141,10 @@ func parseYAML(data []byte, config *Config) error {
 
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
-		// Remove quotes if present
-		value = strings.Trim(value, "\"'")
+		// Remove one pair of surrounding quotes, keeping any inside the value
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
 
 		// Assign values based on section and key
 		switch section {
@@ -140,6 +156,24 @@ func parseYAML(data []byte, config *Config) error {
 				config.Auth.IssuerID = value
 			case "private_key_path":
 				config.Auth.PrivateKeyPath = value
+			case "key_source":
+				config.Auth.KeySource = value
+			case "key_ref":
+				config.Auth.KeyRef = value
+			case "key_type":
+				config.Auth.KeyType = value
+			case "scopes":
+				scopes, err := parseList(value)
+				if err != nil {
+					return fmt.Errorf("invalid auth.scopes: %w", err)
+				}
+				config.Auth.Scopes = scopes
+			case "token_ttl":
+				ttl, err := time.ParseDuration(value)
+				if err != nil {
+					return fmt.Errorf("invalid auth.token_ttl: %w", err)
+				}
+				config.Auth.TokenTTL = ttl
 			}
 		case "api":
 			switch key {
@@ -154,6 +188,8 @@ func parseYAML(data []byte, config *Config) error {
 				config.Defaults.OutputFormat = value
 			case "vendor_number":
 				config.Defaults.VendorNumber = value
+			case "fiscal_year_start":
+				config.Defaults.FiscalYearStart = value
 			}
 		}
 	}
@@ -161,6 +197,19 @@ func parseYAML(data []byte, config *Config) error {
 	return nil
 }
 
+// parseList parses a flow-style list such as ["a", "b"], or a single bare
+// value as a one-element list. Items may contain commas, so they must be quoted.
+func parseList(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") {
+		return []string{value}, nil
+	}
+	var items []string
+	if err := json.Unmarshal([]byte(value), &items); err != nil {
+		return nil, fmt.Errorf("expected a list of quoted strings: %w", err)
+	}
+	return items, nil
+}
+
 // loadFromEnv loads configuration from environment variables
 func loadFromEnv(config *Config) {
 	// Auth settings
@@ -173,6 +222,29 @@ func loadFromEnv(config *Config) {
 	if v := os.Getenv("POMME_AUTH_PRIVATE_KEY_PATH"); v != "" {
 		config.Auth.PrivateKeyPath = v
 	}
+	if v := os.Getenv("POMME_AUTH_KEY_SOURCE"); v != "" {
+		// A reference from the file belongs to the source it configures
+		if !strings.EqualFold(v, config.Auth.KeySource) {
+			config.Auth.KeyRef = ""
+		}
+		config.Auth.KeySource = v
+	}
+	if v := os.Getenv("POMME_AUTH_KEY_REF"); v != "" {
+		config.Auth.KeyRef = v
+	}
+	if v := os.Getenv("POMME_AUTH_KEY_TYPE"); v != "" {
+		config.Auth.KeyType = v
+	}
+	if v := os.Getenv("POMME_AUTH_SCOPES"); v != "" {
+		if scopes, err := parseList(v); err == nil {
+			config.Auth.Scopes = scopes
+		}
+	}
+	if v := os.Getenv("POMME_AUTH_TOKEN_TTL"); v != "" {
+		if ttl, err := time.ParseDuration(v); err == nil {
+			config.Auth.TokenTTL = ttl
+		}
+	}
 
 	// API settings
 	if v := os.Getenv("POMME_API_BASE_URL"); v != "" {
@@ -189,6 +261,9 @@ func loadFromEnv(config *Config) {
 	if v := os.Getenv("POMME_DEFAULTS_VENDOR_NUMBER"); v != "" {
 		config.Defaults.VendorNumber = v
 	}
+	if v := os.Getenv("POMME_DEFAULTS_FISCAL_YEAR_START"); v != "" {
+		config.Defaults.FiscalYearStart = v
+	}
 }
 
 // InitConfig creates a new config file with default values
@@ -202,7 +277,7 @@ func InitConfig(configPath string) error {
 	}
 
 	// Ensure the directory exists
-	if err := os.MkdirAll(configPath, 0o755); err != nil {
+	if err := os.MkdirAll(configPath, 0o700); err != nil {
 		return fmt.Errorf("could not create config directory: %w", err)
 	}
 
@@ -221,7 +296,7 @@ auth:
   private_key_path: ""
 `
 
-	if err := os.WriteFile(configFile, []byte(defaultConfig), 0o644); err != nil {
+	if err := writePrivate(configFile, []byte(defaultConfig)); err != nil {
 		return fmt.Errorf("could not write config file: %w", err)
 	}
 
@@ -229,20 +304,33 @@ auth:
 	return nil
 }
 
-// Save writes the config to the default location
-func Save(cfg *Config) (string, error) {
+// DefaultDir returns the directory Save writes the config file to
+func DefaultDir() (string, error) {
 	configHome, err := os.UserConfigDir()
 	if err != nil {
 		return "", fmt.Errorf("could not find user config directory: %w", err)
 	}
+	return filepath.Join(configHome, "pomme"), nil
+}
+
+// Save writes the config to the default location
+func Save(cfg *Config) (string, error) {
+	configPath, err := DefaultDir()
+	if err != nil {
+		return "", err
+	}
 	
-	configPath := filepath.Join(configHome, "pomme")
-	if err := os.MkdirAll(configPath, 0o755); err != nil {
+	if err := os.MkdirAll(configPath, 0o700); err != nil {
 		return "", fmt.Errorf("could not create config directory: %w", err)
 	}
 	
 	configFile := filepath.Join(configPath, "pomme.yaml")
 	
+	var fiscalYearStart string
+	if cfg.Defaults.FiscalYearStart != "" {
+		fiscalYearStart = fmt.Sprintf("  fiscal_year_start: %s\n", cfg.Defaults.FiscalYearStart)
+	}
+
 	// Format the config as YAML
 	content := fmt.Sprintf(`api:
   base_url: %s
@@ -250,7 +338,7 @@ func Save(cfg *Config) (string, error) {
 defaults:
   output_format: %s
   vendor_number: "%s"
-auth:
+%sauth:
   key_id: %s
   issuer_id: %s
   private_key_path: %s
@@ -259,18 +347,44 @@ auth:
 		cfg.API.Timeout,
 		cfg.Defaults.OutputFormat,
 		cfg.Defaults.VendorNumber,
+		fiscalYearStart,
 		cfg.Auth.KeyID,
 		cfg.Auth.IssuerID,
 		cfg.Auth.PrivateKeyPath,
 	)
+	if cfg.Auth.KeySource != "" {
+		content += fmt.Sprintf("  key_source: %s\n", cfg.Auth.KeySource)
+	}
+	if cfg.Auth.KeyRef != "" {
+		content += fmt.Sprintf("  key_ref: \"%s\"\n", cfg.Auth.KeyRef)
+	}
+	if cfg.Auth.KeyType != "" {
+		content += fmt.Sprintf("  key_type: %s\n", cfg.Auth.KeyType)
+	}
+	if len(cfg.Auth.Scopes) > 0 {
+		scopes, _ := json.Marshal(cfg.Auth.Scopes)
+		content += fmt.Sprintf("  scopes: %s\n", scopes)
+	}
+	if cfg.Auth.TokenTTL > 0 {
+		content += fmt.Sprintf("  token_ttl: %s\n", cfg.Auth.TokenTTL)
+	}
 	
-	if err := os.WriteFile(configFile, []byte(content), 0o644); err != nil {
+	if err := writePrivate(configFile, []byte(content)); err != nil {
 		return "", fmt.Errorf("could not write config file: %w", err)
 	}
 	
 	return configFile, nil
 }
 
+// writePrivate writes a file readable only by its owner, tightening the
+// permissions of a file that already exists
+func writePrivate(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
 // GetConfigPath returns the path to the config file if it exists
 func GetConfigPath() string {
 	path := findConfigFile()
@@ -0,0 +1,201 @@
+// Package countries is an ISO 3166-1 country table with the codes, regions
+// and currencies App Store Connect reports use
+package countries
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Region groups every country belongs to one of, besides EU and EUROZONE
+const (
+	RegionNorthAmerica = "NA"
+	RegionLatinAmerica = "LATAM"
+	RegionEMEA         = "EMEA"
+	RegionAPAC         = "APAC"
+	RegionEU           = "EU"
+	RegionEurozone     = "EUROZONE"
+)
+
+// financePrefix marks an Apple financial report region in region names
+const financePrefix = "apple:"
+
+//go:embed countries.tsv
+var table string
+
+// Country is one ISO 3166-1 country
+type Country struct {
+	Alpha2   string   // As used by sales reports, e.g. US
+	Alpha3   string   // As used by customer review territories, e.g. USA
+	Name     string   // Short English name
+	Currency string   // ISO 4217 code of the local currency, "" for none
+	Regions  []string // NA, LATAM, EMEA or APAC, plus EU and EUROZONE for members
+
+	// FinanceRegion is the Apple financial report the country's proceeds are
+	// paid in: its own code where Apple pays in the local currency, EU for the
+	// euro zone, LL for the rest of Latin America and the Caribbean and WW for
+	// the rest of the world
+	FinanceRegion string
+}
+
+// Flag returns the country's flag emoji
+func (c Country) Flag() string {
+	if len(c.Alpha2) != 2 {
+		return ""
+	}
+	var flag strings.Builder
+	for _, letter := range c.Alpha2 {
+		flag.WriteRune(0x1F1E6 + letter - 'A')
+	}
+	return flag.String()
+}
+
+// InRegion reports whether the country belongs to a region group, such as
+// EU, or to an Apple financial report region, such as apple:LL
+func (c Country) InRegion(region string) bool {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if finance, ok := cutPrefixFold(region, financePrefix); ok {
+		return c.FinanceRegion == finance
+	}
+	for _, name := range c.Regions {
+		if name == region {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	all     []Country
+	byCode  = make(map[string]*Country)
+	regions = make(map[string]bool)
+)
+
+func init() {
+	for i, line := range strings.Split(strings.TrimSpace(table), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 6 {
+			panic(fmt.Sprintf("countries.tsv line %d: want 6 fields, got %d", i+1, len(fields)))
+		}
+		country := Country{
+			Alpha2:        fields[0],
+			Alpha3:        fields[1],
+			Name:          fields[2],
+			Currency:      dash(fields[3]),
+			FinanceRegion: dash(fields[5]),
+		}
+		if groups := dash(fields[4]); groups != "" {
+			country.Regions = strings.Split(groups, ",")
+		}
+		for _, region := range country.Regions {
+			regions[region] = true
+		}
+		if country.FinanceRegion != "" {
+			regions[financePrefix+country.FinanceRegion] = true
+		}
+		all = append(all, country)
+	}
+
+	for i := range all {
+		byCode[all[i].Alpha2] = &all[i]
+		byCode[all[i].Alpha3] = &all[i]
+	}
+}
+
+// Lookup finds a country by its alpha-2 or alpha-3 code
+func Lookup(code string) (Country, bool) {
+	country, ok := byCode[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Country{}, false
+	}
+	return *country, true
+}
+
+// Name returns a country's name, or the code itself when it's unknown
+func Name(code string) string {
+	if country, ok := Lookup(code); ok {
+		return country.Name
+	}
+	return code
+}
+
+// Alpha3 returns a country's alpha-3 code, or "" when it's unknown
+func Alpha3(code string) string {
+	country, _ := Lookup(code)
+	return country.Alpha3
+}
+
+// Currency returns a country's local currency, or "" when it's unknown
+func Currency(code string) string {
+	country, _ := Lookup(code)
+	return country.Currency
+}
+
+// All returns every country, ordered by alpha-2 code
+func All() []Country {
+	return append([]Country(nil), all...)
+}
+
+// Regions returns the names of every region group and Apple financial
+// report region, sorted
+func Regions() []string {
+	names := make([]string, 0, len(regions))
+	for name := range regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InRegion reports whether the country with the given code is in region.
+// Unknown codes aren't in any region.
+func InRegion(code, region string) bool {
+	country, ok := Lookup(code)
+	return ok && country.InRegion(region)
+}
+
+// Members returns the countries in a region, or an error for regions that
+// don't exist
+func Members(region string) ([]Country, error) {
+	if err := ValidateRegion(region); err != nil {
+		return nil, err
+	}
+	var members []Country
+	for _, country := range all {
+		if country.InRegion(region) {
+			members = append(members, country)
+		}
+	}
+	return members, nil
+}
+
+// ValidateRegion returns an error for regions that don't exist
+func ValidateRegion(region string) error {
+	name := strings.ToUpper(strings.TrimSpace(region))
+	if finance, ok := cutPrefixFold(name, financePrefix); ok {
+		name = financePrefix + finance
+	}
+	if !regions[name] {
+		return fmt.Errorf("unknown region %q: use NA, LATAM, EMEA, APAC, EU, EUROZONE or an Apple finance region such as apple:LL", region)
+	}
+	return nil
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return strings.ToUpper(s[len(prefix):]), true
+}
+
+func dash(field string) string {
+	if field == "-" {
+		return ""
+	}
+	return field
+}
@@ -0,0 +1,290 @@
+// Package exporter keeps sales and ratings gauges up to date for scraping
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/metrics"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+const (
+	// dailyLookback is how many days back to look for the latest published
+	// DAILY report; Apple publishes each day's report the following day
+	dailyLookback = 4
+
+	reviewConcurrency = 4
+)
+
+// SalesService fetches sales reports
+type SalesService interface {
+	GetReport(ctx context.Context, options sales.ReportOptions) (*models.SalesReport, error)
+}
+
+// ReviewsService summarizes customer reviews
+type ReviewsService interface {
+	Summary(ctx context.Context, appID string) (*models.ReviewSummary, error)
+}
+
+// AppsService lists apps
+type AppsService interface {
+	List(ctx context.Context) ([]models.App, error)
+}
+
+// Config configures an Exporter
+type Config struct {
+	Sales        SalesService
+	Reviews      ReviewsService
+	Apps         AppsService
+	VendorNumber string
+	Registry     *metrics.Registry
+	Logger       *slog.Logger // Optional refresh log
+}
+
+// Exporter refreshes sales and review gauges in a registry
+type Exporter struct {
+	config Config
+	now    func() time.Time
+
+	units       *metrics.GaugeVec
+	proceeds    *metrics.GaugeVec
+	reportDate  *metrics.GaugeVec
+	reviews     *metrics.GaugeVec
+	rating      *metrics.GaugeVec
+	appReviews  *metrics.GaugeVec
+	appRating   *metrics.GaugeVec
+	lastSuccess *metrics.GaugeVec
+	duration    *metrics.GaugeVec
+	failures    *metrics.CounterVec
+}
+
+// New registers the exporter's metrics
+func New(config Config) *Exporter {
+	r := config.Registry
+	return &Exporter{
+		config: config,
+		now:    time.Now,
+
+		units: r.NewGaugeVec("pomme_sales_units",
+			"Units in the latest DAILY sales report.",
+			"app_id", "app", "country"),
+		proceeds: r.NewGaugeVec("pomme_sales_proceeds",
+			"Developer proceeds in the latest DAILY sales report, in the currency of proceeds.",
+			"app_id", "app", "country", "currency"),
+		reportDate: r.NewGaugeVec("pomme_sales_report_date_seconds",
+			"Start of the day covered by the latest DAILY sales report, as a Unix timestamp."),
+		reviews: r.NewGaugeVec("pomme_reviews",
+			"Recent customer reviews per territory.",
+			"app_id", "app", "territory"),
+		rating: r.NewGaugeVec("pomme_review_rating_average",
+			"Average rating of recent customer reviews per territory.",
+			"app_id", "app", "territory"),
+		appReviews: r.NewGaugeVec("pomme_app_reviews",
+			"Recent customer reviews across all territories.",
+			"app_id", "app"),
+		appRating: r.NewGaugeVec("pomme_app_rating_average",
+			"Average rating of recent customer reviews across all territories.",
+			"app_id", "app"),
+		lastSuccess: r.NewGaugeVec("pomme_exporter_last_success_seconds",
+			"Time of the last successful refresh, as a Unix timestamp.",
+			"source"),
+		duration: r.NewGaugeVec("pomme_exporter_refresh_duration_seconds",
+			"Duration of the last refresh.",
+			"source"),
+		failures: r.NewCounterVec("pomme_exporter_refresh_failures_total",
+			"Refreshes that failed. The previous values are kept.",
+			"source"),
+	}
+}
+
+// Run refreshes now and then every interval until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Refresh(ctx); err != nil && e.config.Logger != nil && ctx.Err() == nil {
+			e.config.Logger.Warn("refresh failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh updates the sales and review gauges. A source that fails keeps its
+// previous values.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	var wg sync.WaitGroup
+	var salesErr, reviewsErr error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		salesErr = e.track(ctx, "sales", e.refreshSales)
+	}()
+	go func() {
+		defer wg.Done()
+		reviewsErr = e.track(ctx, "reviews", e.refreshReviews)
+	}()
+	wg.Wait()
+
+	return errors.Join(salesErr, reviewsErr)
+}
+
+// track runs one source's refresh and records how it went
+func (e *Exporter) track(ctx context.Context, source string, refresh func(context.Context) error) error {
+	start := time.Now()
+	err := refresh(ctx)
+	e.duration.Set(time.Since(start).Seconds(), source)
+
+	if err != nil {
+		e.failures.Inc(source)
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	e.lastSuccess.Set(float64(e.now().Unix()), source)
+	if e.config.Logger != nil {
+		e.config.Logger.Info("refreshed", "source", source, "duration", time.Since(start))
+	}
+	return nil
+}
+
+// refreshSales exports the most recent DAILY report Apple has published
+func (e *Exporter) refreshSales(ctx context.Context) error {
+	report, day, err := e.latestDailyReport(ctx)
+	if err != nil {
+		return err
+	}
+
+	type appCountry struct {
+		appID, country string
+	}
+	type appCountryCurrency struct {
+		appCountry
+		currency string
+	}
+	units := make(map[appCountry]int)
+	proceeds := make(map[appCountryCurrency]float64)
+	names := make(map[string]string)
+
+	for _, app := range report.Apps {
+		names[app.AppID] = app.AppName
+		for _, sale := range app.Sales {
+			key := appCountry{app.AppID, sale.Country}
+			units[key] += sale.Units
+			if amount := sale.Proceeds(); amount != 0 && sale.DeveloperProceeds.Currency != "" {
+				proceeds[appCountryCurrency{key, sale.DeveloperProceeds.Currency}] += amount
+			}
+		}
+	}
+
+	unitSamples := make([]metrics.Sample, 0, len(units))
+	for key, n := range units {
+		unitSamples = append(unitSamples, metrics.Sample{
+			Labels: []string{key.appID, names[key.appID], key.country},
+			Value:  float64(n),
+		})
+	}
+	proceedsSamples := make([]metrics.Sample, 0, len(proceeds))
+	for key, amount := range proceeds {
+		proceedsSamples = append(proceedsSamples, metrics.Sample{
+			Labels: []string{key.appID, names[key.appID], key.country, key.currency},
+			Value:  amount,
+		})
+	}
+
+	e.units.Replace(unitSamples)
+	e.proceeds.Replace(proceedsSamples)
+	e.reportDate.Set(float64(day.Unix()))
+	return nil
+}
+
+// latestDailyReport walks back from yesterday to the newest published report
+// and returns it with the day it covers
+func (e *Exporter) latestDailyReport(ctx context.Context) (*models.SalesReport, time.Time, error) {
+	now := e.now()
+	for days := 1; days <= dailyLookback; days++ {
+		date := now.AddDate(0, 0, -days)
+		report, err := e.config.Sales.GetReport(ctx, sales.ReportOptions{
+			Period:       models.ReportFrequencyDaily,
+			Date:         date,
+			ReportType:   models.ReportTypeSales,
+			VendorNumber: e.config.VendorNumber,
+		})
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("daily report for %s: %w", date.Format("2006-01-02"), err)
+		}
+		if report != nil {
+			return report, time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return nil, time.Time{}, fmt.Errorf("no DAILY report published in the last %d days", dailyLookback)
+}
+
+// refreshReviews exports rating statistics for every app
+func (e *Exporter) refreshReviews(ctx context.Context) error {
+	apps, err := e.config.Apps.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list apps: %w", err)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		firstErr   error
+		reviews    []metrics.Sample
+		rating     []metrics.Sample
+		appReviews []metrics.Sample
+		appRating  []metrics.Sample
+		sem        = make(chan struct{}, reviewConcurrency)
+	)
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app models.App) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			summary, err := e.config.Reviews.Summary(ctx, app.ID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("reviews for %s: %w", app.ID, err)
+				}
+				return
+			}
+
+			name := app.Attributes.Name
+			appReviews = append(appReviews, metrics.Sample{Labels: []string{app.ID, name}, Value: float64(summary.TotalReviews)})
+			if summary.TotalReviews > 0 {
+				appRating = append(appRating, metrics.Sample{Labels: []string{app.ID, name}, Value: summary.AverageRating})
+			}
+			for _, stats := range summary.TerritoryStats {
+				labels := []string{app.ID, name, stats.Territory}
+				reviews = append(reviews, metrics.Sample{Labels: labels, Value: float64(stats.ReviewCount)})
+				rating = append(rating, metrics.Sample{Labels: labels, Value: stats.AverageRating})
+			}
+		}(app)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	e.reviews.Replace(reviews)
+	e.rating.Replace(rating)
+	e.appReviews.Replace(appReviews)
+	e.appRating.Replace(appRating)
+	return nil
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// APIMetrics counts App Store Connect API requests, errors and latency. It
+// implements api.RequestObserver.
+type APIMetrics struct {
+	requests *CounterVec
+	errors   *CounterVec
+	latency  *HistogramVec
+}
+
+// NewAPIMetrics registers the API request metrics
+func NewAPIMetrics(r *Registry) *APIMetrics {
+	return &APIMetrics{
+		requests: r.NewCounterVec("pomme_api_requests_total",
+			"App Store Connect API requests by response status code (0 for network errors).",
+			"method", "endpoint", "code"),
+		errors: r.NewCounterVec("pomme_api_errors_total",
+			"App Store Connect API requests that failed with a network error, 4xx or 5xx status.",
+			"method", "endpoint", "reason"),
+		latency: r.NewHistogramVec("pomme_api_request_duration_seconds",
+			"App Store Connect API request latency.",
+			DefaultBuckets, "method", "endpoint"),
+	}
+}
+
+// ObserveRequest implements api.RequestObserver
+func (m *APIMetrics) ObserveRequest(method, endpoint string, status int, duration time.Duration, err error) {
+	m.requests.Inc(method, endpoint, strconv.Itoa(status))
+	m.latency.Observe(duration.Seconds(), method, endpoint)
+
+	switch {
+	case err != nil:
+		m.errors.Inc(method, endpoint, "network")
+	case status >= 500:
+		m.errors.Inc(method, endpoint, "5xx")
+	case status >= 400:
+		m.errors.Inc(method, endpoint, "4xx")
+	}
+}
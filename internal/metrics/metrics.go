@@ -0,0 +1,261 @@
+// Package metrics implements counters, gauges and histograms with label
+// values, written in the Prometheus text exposition format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry holds metric families in registration order
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// family is a metric name with its series, one per combination of label values
+type family struct {
+	name    string
+	help    string
+	kind    string // counter, gauge or histogram
+	labels  []string
+	buckets []float64 // Histogram upper bounds, ascending
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is the state for one set of label values
+type series struct {
+	labels []string
+	value  float64  // Counter or gauge value, histogram sum
+	count  uint64   // Histogram observations
+	counts []uint64 // Histogram observations per bucket, not cumulative
+}
+
+func (r *Registry) register(f *family) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.families {
+		if existing.name == f.name {
+			panic(fmt.Sprintf("metrics: %s registered twice", f.name))
+		}
+	}
+	f.series = make(map[string]*series)
+	r.families = append(r.families, f)
+	return f
+}
+
+// get returns the series for label values, creating it if needed. The caller
+// holds f.mu.
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s has %d labels, got %d values", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: append([]string(nil), values...)}
+		if f.kind == "histogram" {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct {
+	f *family
+}
+
+// NewCounterVec registers a counter
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{r.register(&family{name: name, help: help, kind: "counter", labels: labels})}
+}
+
+// Inc adds one to the series for the label values
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v, which must not be negative, to the series for the label values
+func (c *CounterVec) Add(v float64, values ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metrics: counter %s decreased", c.f.name))
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.get(values).value += v
+}
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct {
+	f *family
+}
+
+// NewGaugeVec registers a gauge
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{r.register(&family{name: name, help: help, kind: "gauge", labels: labels})}
+}
+
+// Set sets the series for the label values
+func (g *GaugeVec) Set(v float64, values ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(values).value = v
+}
+
+// Sample is one gauge value with its label values
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// Replace swaps every series for samples at once, so scrapes never see a
+// half-refreshed gauge and series that disappeared are dropped
+func (g *GaugeVec) Replace(samples []Sample) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.series = make(map[string]*series, len(samples))
+	for _, sample := range samples {
+		g.f.get(sample.Labels).value = sample.Value
+	}
+}
+
+// HistogramVec counts observations into buckets, partitioned by labels
+type HistogramVec struct {
+	f *family
+}
+
+// DefaultBuckets suit API request latencies in seconds
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// NewHistogramVec registers a histogram with the given bucket upper bounds
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{r.register(&family{name: name, help: help, kind: "histogram", labels: labels, buckets: buckets})}
+}
+
+// Observe records v in the series for the label values
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+	s := h.f.get(values)
+	s.value += v
+	s.count++
+	if i := sort.SearchFloat64s(h.f.buckets, v); i < len(h.f.buckets) {
+		s.counts[i]++
+	}
+}
+
+// Write writes every family in the text exposition format, series sorted by
+// label values
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry for scraping
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		r.Write(w)
+	})
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != "histogram" {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(f.labels, s.labels), formatValue(s.value))
+			continue
+		}
+
+		names := append(append([]string(nil), f.labels...), "le")
+		values := append(append([]string(nil), s.labels...), "")
+		var cumulative uint64
+		for i, bound := range f.buckets {
+			cumulative += s.counts[i]
+			values[len(values)-1] = formatValue(bound)
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(names, values), cumulative)
+		}
+		values[len(values)-1] = "+Inf"
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(names, values), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, formatLabels(f.labels, s.labels), formatValue(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, formatLabels(f.labels, s.labels), s.count)
+	}
+}
+
+// formatLabels renders {name="value",...}, or nothing without labels
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(values[i]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,331 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Filter is a compiled jq-style expression. It supports the subset that
+// covers most API exploration:
+//
+//	.                     the whole document
+//	.data[0].id           fields and array indexes (negative counts from the end)
+//	.data[].attributes    iterate arrays or object values
+//	."bundle-id"          quoted field names, also as .["bundle-id"]
+//	.data | length        pipes, with the length and keys builtins
+type Filter struct {
+	stages [][]filterStep
+}
+
+// filterStep is one field access, index or iteration
+type filterStep struct {
+	field string
+	index int
+	kind  int
+}
+
+const (
+	stepField = iota
+	stepIndex
+	stepIterate
+	stepLength
+	stepKeys
+)
+
+// ParseFilter compiles a filter expression
+func ParseFilter(expr string) (*Filter, error) {
+	filter := &Filter{}
+	for _, stage := range splitPipes(expr) {
+		steps, err := parseStage(strings.TrimSpace(stage))
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+		}
+		filter.stages = append(filter.stages, steps)
+	}
+	return filter, nil
+}
+
+// Apply runs the filter over a decoded JSON document and returns every result
+func (f *Filter) Apply(data interface{}) ([]interface{}, error) {
+	values := []interface{}{data}
+	for _, steps := range f.stages {
+		for _, step := range steps {
+			var next []interface{}
+			for _, value := range values {
+				results, err := step.apply(value)
+				if err != nil {
+					return nil, err
+				}
+				next = append(next, results...)
+			}
+			values = next
+		}
+	}
+	return values, nil
+}
+
+// WriteFiltered applies the filter and writes each result on its own line.
+// Strings are written raw so they can be used in shell pipelines; everything
+// else is written as indented JSON.
+func (f *Filter) WriteFiltered(w io.Writer, data interface{}) error {
+	results, err := f.Apply(data)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if s, ok := result.(string); ok {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		encoded, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(encoded))
+	}
+	return nil
+}
+
+func (s filterStep) apply(value interface{}) ([]interface{}, error) {
+	switch s.kind {
+	case stepField:
+		if value == nil {
+			return []interface{}{nil}, nil
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot read field %q of %s", s.field, typeName(value))
+		}
+		return []interface{}{object[s.field]}, nil
+
+	case stepIndex:
+		if value == nil {
+			return []interface{}{nil}, nil
+		}
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot index %s with a number", typeName(value))
+		}
+		i := s.index
+		if i < 0 {
+			i += len(array)
+		}
+		if i < 0 || i >= len(array) {
+			return []interface{}{nil}, nil
+		}
+		return []interface{}{array[i]}, nil
+
+	case stepIterate:
+		switch v := value.(type) {
+		case []interface{}:
+			return v, nil
+		case map[string]interface{}:
+			results := make([]interface{}, 0, len(v))
+			for _, key := range sortedKeys(v) {
+				results = append(results, v[key])
+			}
+			return results, nil
+		default:
+			return nil, fmt.Errorf("cannot iterate over %s", typeName(value))
+		}
+
+	case stepLength:
+		switch v := value.(type) {
+		case nil:
+			return []interface{}{0.0}, nil
+		case []interface{}:
+			return []interface{}{float64(len(v))}, nil
+		case map[string]interface{}:
+			return []interface{}{float64(len(v))}, nil
+		case string:
+			return []interface{}{float64(len([]rune(v)))}, nil
+		default:
+			return nil, fmt.Errorf("%s has no length", typeName(value))
+		}
+
+	case stepKeys:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s has no keys", typeName(value))
+		}
+		keys := make([]interface{}, 0, len(object))
+		for _, key := range sortedKeys(object) {
+			keys = append(keys, key)
+		}
+		return []interface{}{keys}, nil
+	}
+	return nil, fmt.Errorf("unknown filter step")
+}
+
+// parseStage parses a single pipe stage into steps
+func parseStage(stage string) ([]filterStep, error) {
+	switch stage {
+	case "":
+		return nil, fmt.Errorf("empty expression")
+	case ".":
+		return nil, nil
+	case "length":
+		return []filterStep{{kind: stepLength}}, nil
+	case "keys":
+		return []filterStep{{kind: stepKeys}}, nil
+	}
+	if stage[0] != '.' {
+		return nil, fmt.Errorf("expected a path starting with '.', got %q", stage)
+	}
+
+	var steps []filterStep
+	rest := stage
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '['")
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case inner == "":
+				steps = append(steps, filterStep{kind: stepIterate})
+			case strings.HasPrefix(inner, `"`):
+				field, err := strconv.Unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("bad field name %s", inner)
+				}
+				steps = append(steps, filterStep{kind: stepField, field: field})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("bad index %q", inner)
+				}
+				steps = append(steps, filterStep{kind: stepIndex, index: index})
+			}
+
+		case strings.HasPrefix(rest, `."`):
+			field, tail, err := cutQuoted(rest[1:])
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, filterStep{kind: stepField, field: field})
+			rest = tail
+
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			n := 0
+			for n < len(rest) && isIdentChar(rest[n]) {
+				n++
+			}
+			if n == 0 {
+				// A bare dot before brackets, as in .[0] or .data.[]
+				if strings.HasPrefix(rest, "[") {
+					continue
+				}
+				return nil, fmt.Errorf("expected a field name after '.'")
+			}
+			steps = append(steps, filterStep{kind: stepField, field: rest[:n]})
+			rest = rest[n:]
+
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+	}
+	return steps, nil
+}
+
+// splitPipes splits on | outside of quoted strings
+func splitPipes(expr string) []string {
+	var parts []string
+	inQuote := false
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if inQuote {
+				i++
+			}
+		case '"':
+			inQuote = !inQuote
+		case '|':
+			if !inQuote {
+				parts = append(parts, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+// closingBracket finds the ] matching the [ at the start of s
+func closingBracket(s string) int {
+	inQuote := false
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if inQuote {
+				i++
+			}
+		case '"':
+			inQuote = !inQuote
+		case ']':
+			if !inQuote {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// cutQuoted reads the quoted string at the start of s
+func cutQuoted(s string) (string, string, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("bad field name %s", s[:i+1])
+			}
+			return value, s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unclosed '\"'")
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case float64, json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authenticate rejects requests without a valid bearer token or basic auth
+// credentials
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.authorized(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var challenges []string
+		if s.config.Token != "" {
+			challenges = append(challenges, `Bearer realm="pomme"`)
+		}
+		if s.config.BasicUser != "" {
+			challenges = append(challenges, `Basic realm="pomme", charset="UTF-8"`)
+		}
+		for _, challenge := range challenges {
+			w.Header().Add("WWW-Authenticate", challenge)
+		}
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+	})
+}
+
+// authorized checks the request's credentials in constant time
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+
+	if s.config.Token != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return secureEqual(token, s.config.Token)
+		}
+	}
+
+	if s.config.BasicUser != "" {
+		if user, password, ok := r.BasicAuth(); ok {
+			// Evaluate both so timing doesn't reveal which one was wrong
+			userOK := secureEqual(user, s.config.BasicUser)
+			passwordOK := secureEqual(password, s.config.BasicPassword)
+			return userOK && passwordOK
+		}
+	}
+
+	return false
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"math"
+	"strconv"
+)
+
+// Chart dimensions in SVG user units
+const (
+	chartWidth   = 640
+	chartHeight  = 220
+	chartLeft    = 56 // Room for axis labels
+	chartBottom  = 24 // Room for period labels
+	chartTop     = 12
+	chartBarFill = 0.7 // Share of each slot the bar covers
+)
+
+// barChart is a bar chart laid out for the SVG in the dashboard template
+type barChart struct {
+	Title     string
+	Width     float64
+	Height    float64
+	Left      float64
+	Baseline  float64
+	Bars      []chartBar
+	Gridlines []gridline
+}
+
+// chartBar is one bar with its label underneath and value in a tooltip
+type chartBar struct {
+	X, Y, Width, Height float64
+	LabelX              float64
+	Label               string
+	Value               string
+}
+
+// gridline is a horizontal line with its value on the axis
+type gridline struct {
+	Y     float64
+	Label string
+}
+
+// newBarChart lays out one bar per value. Negative values are drawn as zero.
+func newBarChart(title string, labels []string, values []float64, format func(float64) string) barChart {
+	chart := barChart{
+		Title:    title,
+		Width:    chartWidth,
+		Height:   chartHeight,
+		Left:     chartLeft,
+		Baseline: chartHeight - chartBottom,
+	}
+
+	max := 0.0
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	max = niceCeiling(max)
+	plotHeight := chart.Baseline - chartTop
+
+	for _, fraction := range []float64{0, 0.5, 1} {
+		chart.Gridlines = append(chart.Gridlines, gridline{
+			Y:     chart.Baseline - fraction*plotHeight,
+			Label: format(fraction * max),
+		})
+	}
+
+	if len(values) == 0 {
+		return chart
+	}
+	slot := (chartWidth - chartLeft) / float64(len(values))
+	for i, v := range values {
+		height := math.Max(v, 0) / max * plotHeight
+		x := chartLeft + float64(i)*slot
+		chart.Bars = append(chart.Bars, chartBar{
+			X:      round1(x + slot*(1-chartBarFill)/2),
+			Y:      round1(chart.Baseline - height),
+			Width:  round1(slot * chartBarFill),
+			Height: round1(height),
+			LabelX: round1(x + slot/2),
+			Label:  labels[i],
+			Value:  format(v),
+		})
+	}
+	return chart
+}
+
+// niceCeiling rounds max up to 1, 2 or 5 times a power of ten, so gridline
+// labels are round numbers
+func niceCeiling(max float64) float64 {
+	if max <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(max)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if max <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// formatCount formats a number with thousands separators
+func formatCount(v float64) string {
+	s := strconv.FormatInt(int64(math.Round(v)), 10)
+	negative := s[0] == '-'
+	if negative {
+		s = s[1:]
+	}
+
+	var out []byte
+	for i := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, s[i])
+	}
+	if negative {
+		return "-" + string(out)
+	}
+	return string(out)
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// writeCSV sends rows, header first, as a CSV download
+func writeCSV(w http.ResponseWriter, filename string, rows [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", attachment(filename))
+
+	writer := csv.NewWriter(w)
+	writer.WriteAll(rows)
+}
+
+func attachment(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
+
+func appsCSV(apps []models.App) [][]string {
+	rows := [][]string{{"id", "name", "bundle_id", "sku", "primary_locale"}}
+	for _, app := range apps {
+		rows = append(rows, []string{app.ID, app.Attributes.Name, app.Attributes.BundleID,
+			app.Attributes.SKU, app.Attributes.PrimaryLocale})
+	}
+	return rows
+}
+
+// salesReportCSV has one row per app and proceeds currency
+func salesReportCSV(report *models.SalesReport) [][]string {
+	rows := [][]string{{"app_id", "app_name", "sku", "units", "countries", "currency", "proceeds"}}
+	for _, app := range report.Apps {
+		prefix := []string{app.AppID, app.AppName, app.SKU,
+			strconv.Itoa(app.Summary.TotalUnits), strconv.Itoa(app.Summary.Countries)}
+
+		currencies := sortedKeys(app.Summary.TotalProceeds)
+		if len(currencies) == 0 {
+			rows = append(rows, append(prefix, "", ""))
+			continue
+		}
+		for _, currency := range currencies {
+			rows = append(rows, append(append([]string{}, prefix...), currency,
+				formatAmount(app.Summary.TotalProceeds[currency])))
+		}
+	}
+	return rows
+}
+
+// comparisonCSV has one row per app in either period, by current units
+func comparisonCSV(comparison *sales.Comparison) [][]string {
+	type appUnits struct {
+		id, name          string
+		current, previous int
+	}
+
+	byID := make(map[string]*appUnits)
+	var apps []*appUnits
+	add := func(report *models.SalesReport, current bool) {
+		if report == nil {
+			return
+		}
+		for _, app := range report.Apps {
+			entry, ok := byID[app.AppID]
+			if !ok {
+				entry = &appUnits{id: app.AppID, name: app.AppName}
+				byID[app.AppID] = entry
+				apps = append(apps, entry)
+			}
+			if current {
+				entry.current = app.Summary.TotalUnits
+			} else {
+				entry.previous = app.Summary.TotalUnits
+			}
+		}
+	}
+	add(comparison.Current, true)
+	add(comparison.Previous, false)
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].current > apps[j].current
+	})
+
+	rows := [][]string{{"app_id", "app_name", "current_units", "previous_units", "units_change", "units_percent"}}
+	for _, app := range apps {
+		percent := ""
+		if app.previous > 0 {
+			percent = formatAmount(float64(app.current-app.previous) / float64(app.previous) * 100)
+		}
+		rows = append(rows, []string{app.id, app.name, strconv.Itoa(app.current),
+			strconv.Itoa(app.previous), strconv.Itoa(app.current - app.previous), percent})
+	}
+	return rows
+}
+
+// trendsCSV has one row per period with a proceeds column per currency
+func trendsCSV(trends *sales.TrendReport) [][]string {
+	currencies := make([]string, 0, len(trends.TotalProceeds))
+	for currency := range trends.TotalProceeds {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	rows := [][]string{append([]string{"period", "units"}, currencies...)}
+	for i, period := range trends.Periods {
+		row := []string{period.Format("2006-01"), ""}
+		if i < len(trends.TotalUnits) {
+			row[1] = strconv.Itoa(trends.TotalUnits[i])
+		}
+		for _, currency := range currencies {
+			value := ""
+			if values := trends.TotalProceeds[currency]; i < len(values) {
+				value = formatAmount(values[i])
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// reviewSummaryCSV has one row per territory
+func reviewSummaryCSV(summary *models.ReviewSummary) [][]string {
+	rows := [][]string{{"territory", "reviews", "average_rating"}}
+	for _, stats := range summary.TerritoryStats {
+		rows = append(rows, []string{stats.Territory, strconv.Itoa(stats.ReviewCount),
+			formatAmount(stats.AverageRating)})
+	}
+	return rows
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatAmount(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
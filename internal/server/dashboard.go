@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+const (
+	defaultDashboardMonths = 12
+	dashboardCountries     = 15
+	dashboardReviews       = 10
+	dashboardReviewApps    = 10 // Apps whose latest reviews are fetched
+	dashboardConcurrency   = 4
+)
+
+//go:embed web
+var webFS embed.FS
+
+var dashboardTemplate = template.Must(template.New("dashboard.html").Funcs(template.FuncMap{
+	"formatUnits": func(n int) string { return formatCount(float64(n)) },
+	"subtract":    func(a, b float64) float64 { return a - b },
+}).ParseFS(webFS, "web/dashboard.html", "web/style.css"))
+
+// dashboardView is the data behind the dashboard template
+type dashboardView struct {
+	Month          time.Time
+	Months         int
+	GeneratedAt    time.Time
+	Summary        *models.ReportSummary // Nil when there's no data for the month
+	Proceeds       string
+	Countries      []countryRow
+	MoreCountries  int
+	UnitsChart     *barChart
+	ProceedsCharts []barChart
+	Reviews        []reviewRow
+	Errors         []string
+}
+
+// countryRow is one row of the country breakdown
+type countryRow struct {
+	Name     string
+	Units    string
+	Proceeds string
+	Share    float64 // Percent of the month's units
+}
+
+// reviewRow is one of the latest reviews
+type reviewRow struct {
+	App       string
+	Stars     string
+	Rating    int
+	Title     string
+	Body      string
+	Nickname  string
+	Territory string
+	Date      string
+}
+
+// handleDashboard renders the HTML dashboard for ?month=YYYY-MM with trends
+// over the ?months=N before it
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	view := dashboardView{
+		Month:       sales.LatestAvailableMonth(s.now()),
+		GeneratedAt: s.now(),
+	}
+	var err error
+	if value := query.Get("month"); value != "" {
+		if view.Month, err = parseMonth("month", value); err != nil {
+			writeFailure(w, err)
+			return
+		}
+	}
+	if view.Months, err = intParam(query, "months", defaultDashboardMonths, 1, maxTrendMonths); err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	s.loadDashboard(r.Context(), &view)
+
+	var buf strings.Builder
+	if err := dashboardTemplate.ExecuteTemplate(&buf, "dashboard.html", view); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to render dashboard: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(buf.String()))
+}
+
+// loadDashboard fetches the month's report, the trends and the latest reviews
+// in parallel. Failures are shown on the page rather than failing it.
+func (s *Server) loadDashboard(ctx context.Context, view *dashboardView) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		report *models.SalesReport
+		trends *sales.TrendReport
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		view.Errors = append(view.Errors, err.Error())
+	}
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		var err error
+		if report, err = s.config.Sales.GetReport(ctx, sales.ReportOptions{
+			Period:       models.ReportFrequencyMonthly,
+			Date:         view.Month,
+			ReportType:   models.ReportTypeSales,
+			VendorNumber: s.config.VendorNumber,
+		}); err != nil {
+			fail(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if trends, err = s.config.Sales.GetTrends(ctx, sales.TrendOptions{
+			Frequency:    models.ReportFrequencyMonthly,
+			EndDate:      view.Month,
+			Periods:      view.Months,
+			ReportType:   models.ReportTypeSales,
+			VendorNumber: s.config.VendorNumber,
+		}); err != nil {
+			fail(fmt.Errorf("failed to analyze trends: %w", err))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		reviews, err := s.latestReviews(ctx)
+		if err != nil {
+			fail(err)
+		}
+		view.Reviews = reviews
+	}()
+	wg.Wait()
+
+	if report != nil {
+		view.Summary = &report.Summary
+		view.Proceeds = formatProceeds(report.Summary.TotalProceeds)
+		view.Countries, view.MoreCountries = countryRows(report)
+	}
+	if trends != nil {
+		view.UnitsChart, view.ProceedsCharts = trendCharts(trends)
+	}
+}
+
+// latestReviews returns the newest reviews across the first apps
+func (s *Server) latestReviews(ctx context.Context) ([]reviewRow, error) {
+	apps, err := s.config.Apps.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list apps: %w", err)
+	}
+	if len(apps) > dashboardReviewApps {
+		apps = apps[:dashboardReviewApps]
+	}
+
+	type appReview struct {
+		app    string
+		review models.CustomerReview
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		all     []appReview
+		lastErr error
+		sem     = make(chan struct{}, dashboardConcurrency)
+	)
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app models.App) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			list, err := s.config.Reviews.List(ctx, models.ReviewFilter{
+				AppID: app.ID,
+				Limit: dashboardReviews,
+				Sort:  "recent",
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = fmt.Errorf("failed to fetch reviews for %s: %w", app.Attributes.Name, err)
+				return
+			}
+			for _, review := range list {
+				all = append(all, appReview{app: app.Attributes.Name, review: review})
+			}
+		}(app)
+	}
+	wg.Wait()
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].review.Attributes.CreatedDate.After(all[j].review.Attributes.CreatedDate)
+	})
+	if len(all) > dashboardReviews {
+		all = all[:dashboardReviews]
+	}
+
+	rows := make([]reviewRow, len(all))
+	for i, entry := range all {
+		attrs := entry.review.Attributes
+		rows[i] = reviewRow{
+			App:       entry.app,
+			Stars:     strings.Repeat("★", attrs.Rating) + strings.Repeat("☆", 5-attrs.Rating),
+			Rating:    attrs.Rating,
+			Title:     attrs.Title,
+			Body:      attrs.Body,
+			Nickname:  attrs.ReviewerNickname,
+			Territory: attrs.Territory,
+			Date:      attrs.CreatedDate.Format("Jan 2, 2006"),
+		}
+	}
+	return rows, lastErr
+}
+
+// countryRows formats the top countries of the breakdown and counts the rest
+func countryRows(report *models.SalesReport) ([]countryRow, int) {
+	countries := sales.CountryBreakdown(report)
+
+	var rows []countryRow
+	for i, country := range countries {
+		if i >= dashboardCountries {
+			break
+		}
+		name := country.CountryName
+		if name == "" {
+			name = country.Country
+		}
+		share := 0.0
+		if report.Summary.TotalUnits > 0 {
+			share = float64(country.Units) / float64(report.Summary.TotalUnits) * 100
+		}
+		rows = append(rows, countryRow{
+			Name:     name,
+			Units:    formatCount(float64(country.Units)),
+			Proceeds: formatProceeds(country.Proceeds),
+			Share:    round1(share),
+		})
+	}
+	return rows, len(countries) - len(rows)
+}
+
+// trendCharts draws units and, per currency, proceeds for each period
+func trendCharts(trends *sales.TrendReport) (*barChart, []barChart) {
+	labels := make([]string, len(trends.Periods))
+	for i, period := range trends.Periods {
+		if !period.IsZero() {
+			labels[i] = period.Format("Jan 06")
+		}
+	}
+
+	units := make([]float64, len(trends.TotalUnits))
+	for i, n := range trends.TotalUnits {
+		units[i] = float64(n)
+	}
+	unitsChart := newBarChart("Units", labels, units, formatCount)
+
+	currencies := make([]string, 0, len(trends.TotalProceeds))
+	for currency := range trends.TotalProceeds {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	var proceeds []barChart
+	for _, currency := range currencies {
+		proceeds = append(proceeds, newBarChart("Proceeds ("+currency+")", labels,
+			trends.TotalProceeds[currency], formatAmount))
+	}
+	return &unitsChart, proceeds
+}
+
+// formatProceeds lists amounts by currency, e.g. "EUR 12.00 · USD 3.50"
+func formatProceeds(amounts map[string]float64) string {
+	if len(amounts) == 0 {
+		return "—"
+	}
+	parts := make([]string, 0, len(amounts))
+	for _, currency := range sortedKeys(amounts) {
+		parts = append(parts, currency+" "+formatAmount(amounts[currency]))
+	}
+	return strings.Join(parts, " · ")
+}
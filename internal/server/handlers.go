@@ -0,0 +1,335 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/internal/services/reviews"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+const (
+	defaultReviewLimit = 20
+	defaultTrendMonths = 6
+	maxTrendMonths     = 36
+)
+
+func (s *Server) handleApps(w http.ResponseWriter, r *http.Request) {
+	csv, err := wantsCSV(r)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	apps, err := s.config.Apps.List(r.Context())
+	if err != nil {
+		writeFailure(w, fmt.Errorf("failed to list apps: %w", err))
+		return
+	}
+
+	if csv {
+		writeCSV(w, "apps.csv", appsCSV(apps))
+		return
+	}
+	writeJSON(w, http.StatusOK, apps)
+}
+
+// handleMonthly serves the monthly report for ?month=YYYY-MM, by default the
+// latest published month
+func (s *Server) handleMonthly(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	csv, err := wantsCSV(r)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	month := sales.LatestAvailableMonth(s.now())
+	if value := query.Get("month"); value != "" {
+		if month, err = parseMonth("month", value); err != nil {
+			writeFailure(w, err)
+			return
+		}
+	}
+	noCache, err := boolParam(query, "no_cache")
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	report, err := s.config.Sales.GetReport(r.Context(), sales.ReportOptions{
+		Period:          models.ReportFrequencyMonthly,
+		Date:            month,
+		ReportType:      models.ReportTypeSales,
+		VendorNumber:    s.config.VendorNumber,
+		NoCache:         noCache,
+		IncludeAnalysis: true,
+	})
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+	if report == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no sales data available for %s", month.Format("January 2006")))
+		return
+	}
+
+	if csv {
+		writeCSV(w, "sales-"+month.Format("2006-01")+".csv", salesReportCSV(report))
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleCompare compares ?current=YYYY-MM with ?previous=YYYY-MM, or the
+// latest month with the one ?months=N before it
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	csv, err := wantsCSV(r)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	var current, previous time.Time
+	if query.Get("current") != "" || query.Get("previous") != "" {
+		if current, err = parseMonth("current", query.Get("current")); err != nil {
+			writeFailure(w, err)
+			return
+		}
+		if previous, err = parseMonth("previous", query.Get("previous")); err != nil {
+			writeFailure(w, err)
+			return
+		}
+	} else {
+		months, err := intParam(query, "months", 1, 1, maxTrendMonths)
+		if err != nil {
+			writeFailure(w, err)
+			return
+		}
+		current = sales.LatestAvailableMonth(s.now())
+		previous = current.AddDate(0, -months, 0)
+	}
+
+	comparison, err := s.config.Sales.GetComparison(r.Context(), s.monthlyOptions(current), s.monthlyOptions(previous))
+	if err != nil {
+		writeFailure(w, fmt.Errorf("failed to get comparison: %w", err))
+		return
+	}
+
+	if csv {
+		filename := fmt.Sprintf("sales-compare-%s-vs-%s.csv", current.Format("2006-01"), previous.Format("2006-01"))
+		writeCSV(w, filename, comparisonCSV(comparison))
+		return
+	}
+	writeJSON(w, http.StatusOK, comparison)
+}
+
+// handleTrends analyzes the last ?months=N published months
+func (s *Server) handleTrends(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	csv, err := wantsCSV(r)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	months, err := intParam(query, "months", defaultTrendMonths, 1, maxTrendMonths)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	end := sales.LatestAvailableMonth(s.now())
+	trends, err := s.config.Sales.GetTrends(r.Context(), sales.TrendOptions{
+		Frequency:    models.ReportFrequencyMonthly,
+		EndDate:      end,
+		Periods:      months,
+		ReportType:   models.ReportTypeSales,
+		VendorNumber: s.config.VendorNumber,
+		GroupBy:      query.Get("group"),
+	})
+	if err != nil {
+		writeFailure(w, fmt.Errorf("failed to analyze trends: %w", err))
+		return
+	}
+
+	if csv {
+		writeCSV(w, "sales-trends-"+end.Format("2006-01")+".csv", trendsCSV(trends))
+		return
+	}
+	writeJSON(w, http.StatusOK, trends)
+}
+
+func (s *Server) handleReviews(w http.ResponseWriter, r *http.Request, appID string) {
+	csv, err := wantsCSV(r)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	filter, err := s.reviewFilter(appID, r.URL.Query())
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	list, err := s.config.Reviews.List(r.Context(), filter)
+	if err != nil {
+		writeFailure(w, fmt.Errorf("failed to fetch reviews: %w", err))
+		return
+	}
+
+	if csv {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", attachment("reviews-"+appID+".csv"))
+		reviews.Export(w, reviews.ExportCSV, reviews.ExportReport{AppID: appID, Reviews: list})
+		return
+	}
+	if list == nil {
+		list = []models.CustomerReview{}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleReviewSummary(w http.ResponseWriter, r *http.Request, appID string) {
+	csv, err := wantsCSV(r)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+
+	summary, err := s.config.Reviews.Summary(r.Context(), appID)
+	if err != nil {
+		writeFailure(w, fmt.Errorf("failed to get review summary: %w", err))
+		return
+	}
+
+	if csv {
+		writeCSV(w, "reviews-summary-"+appID+".csv", reviewSummaryCSV(summary))
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// monthlyOptions selects the monthly sales report for month
+func (s *Server) monthlyOptions(month time.Time) sales.ReportOptions {
+	return sales.ReportOptions{
+		Period:       models.ReportFrequencyMonthly,
+		Date:         month,
+		ReportType:   models.ReportTypeSales,
+		VendorNumber: s.config.VendorNumber,
+	}
+}
+
+// reviewFilter builds a review filter from query parameters named like the
+// reviews list flags
+func (s *Server) reviewFilter(appID string, query url.Values) (models.ReviewFilter, error) {
+	filter := models.ReviewFilter{
+		AppID:    appID,
+		Text:     query.Get("text"),
+		Nickname: query.Get("nickname"),
+		Sort:     query.Get("sort"),
+	}
+	if filter.Sort == "" {
+		filter.Sort = "recent"
+	}
+	for _, value := range query["territory"] {
+		for _, territory := range strings.Split(value, ",") {
+			code := countries.Alpha3(territory)
+			if code == "" {
+				return filter, badRequest{fmt.Errorf("unknown territory %q", territory)}
+			}
+			filter.Territories = append(filter.Territories, code)
+		}
+	}
+
+	var err error
+	if filter.Limit, err = intParam(query, "limit", defaultReviewLimit, 1, reviews.MaxExportReviews); err != nil {
+		return filter, err
+	}
+	if filter.Rating, err = intParam(query, "rating", 0, 1, 5); err != nil {
+		return filter, err
+	}
+	if filter.MinRating, err = intParam(query, "min_rating", 0, 1, 5); err != nil {
+		return filter, err
+	}
+	if filter.MaxRating, err = intParam(query, "max_rating", 0, 1, 5); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("has_response"); value != "" {
+		answered, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, badRequest{fmt.Errorf("invalid has_response %q", value)}
+		}
+		filter.HasResponse = &answered
+	}
+
+	now := s.now()
+	if value := query.Get("since"); value != "" {
+		if filter.StartDate, err = reviews.ParseTime(value, now, false); err != nil {
+			return filter, badRequest{fmt.Errorf("invalid since: %w", err)}
+		}
+	}
+	if value := query.Get("until"); value != "" {
+		if filter.EndDate, err = reviews.ParseTime(value, now, true); err != nil {
+			return filter, badRequest{fmt.Errorf("invalid until: %w", err)}
+		}
+	}
+	return filter, nil
+}
+
+// wantsCSV reports whether the client asked for CSV with ?format=csv or an
+// Accept: text/csv header
+func wantsCSV(r *http.Request) (bool, error) {
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case "csv":
+		return true, nil
+	case "json":
+		return false, nil
+	case "":
+		return strings.Contains(r.Header.Get("Accept"), "text/csv"), nil
+	default:
+		return false, badRequest{fmt.Errorf("invalid format %q (valid: json, csv)", format)}
+	}
+}
+
+func parseMonth(name, value string) (time.Time, error) {
+	month, err := time.Parse("2006-01", value)
+	if err != nil {
+		return time.Time{}, badRequest{fmt.Errorf("invalid %s %q, use YYYY-MM", name, value)}
+	}
+	return month, nil
+}
+
+// intParam parses an optional integer parameter within [min, max]
+func intParam(query url.Values, name string, def, min, max int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, badRequest{fmt.Errorf("invalid %s %q (must be %d-%d)", name, value, min, max)}
+	}
+	return n, nil
+}
+
+func boolParam(query url.Values, name string) (bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, badRequest{fmt.Errorf("invalid %s %q", name, value)}
+	}
+	return b, nil
+}
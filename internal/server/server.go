@@ -0,0 +1,219 @@
+// Package server exposes sales, reviews and apps as a read-only HTTP/JSON API
+// and an HTML dashboard
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// ShutdownTimeout is how long in-flight requests get to finish on shutdown
+const ShutdownTimeout = 10 * time.Second
+
+// SalesService is the part of sales.Service the server uses
+type SalesService interface {
+	GetReport(ctx context.Context, options sales.ReportOptions) (*models.SalesReport, error)
+	GetComparison(ctx context.Context, current, previous sales.ReportOptions) (*sales.Comparison, error)
+	GetTrends(ctx context.Context, options sales.TrendOptions) (*sales.TrendReport, error)
+}
+
+// ReviewsService reads customer reviews
+type ReviewsService interface {
+	List(ctx context.Context, filter models.ReviewFilter) ([]models.CustomerReview, error)
+	Summary(ctx context.Context, appID string) (*models.ReviewSummary, error)
+}
+
+// AppsService lists apps
+type AppsService interface {
+	List(ctx context.Context) ([]models.App, error)
+}
+
+// Config configures a Server
+type Config struct {
+	Sales        SalesService
+	Reviews      ReviewsService
+	Apps         AppsService
+	VendorNumber string
+
+	// Clients authenticate with a bearer token, basic auth, or either when
+	// both are set. At least one is required.
+	Token         string
+	BasicUser     string
+	BasicPassword string
+
+	Logger *slog.Logger // Optional request log
+}
+
+// Server serves the HTTP API
+type Server struct {
+	config Config
+	now    func() time.Time
+}
+
+// New creates a server
+func New(config Config) (*Server, error) {
+	if config.Token == "" && config.BasicUser == "" {
+		return nil, fmt.Errorf("access control required: set a bearer token or basic auth credentials")
+	}
+	if config.BasicUser != "" && config.BasicPassword == "" {
+		return nil, fmt.Errorf("basic auth password required")
+	}
+	if config.Sales == nil || config.Reviews == nil || config.Apps == nil {
+		return nil, fmt.Errorf("sales, reviews and apps services are required")
+	}
+	return &Server{config: config, now: time.Now}, nil
+}
+
+// Handler returns the API and dashboard handler. Everything except /healthz
+// requires authentication.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.Handle("/api/v1/", s.authenticate(http.HandlerFunc(s.route)))
+	mux.Handle("/", s.authenticate(http.HandlerFunc(s.handleDashboard)))
+	return s.logRequests(mux)
+}
+
+// ListenAndServe serves on addr until ctx is cancelled, then gives in-flight
+// requests ShutdownTimeout to finish
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	return Serve(ctx, addr, s.Handler())
+}
+
+// Serve runs handler on addr until ctx is cancelled, then gives in-flight
+// requests ShutdownTimeout to finish
+func Serve(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// route dispatches /api/v1/ requests
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "apps":
+		s.handleApps(w, r)
+	case path == "sales/monthly":
+		s.handleMonthly(w, r)
+	case path == "sales/compare":
+		s.handleCompare(w, r)
+	case path == "sales/trends":
+		s.handleTrends(w, r)
+	case len(parts) == 3 && parts[0] == "apps" && parts[2] == "reviews":
+		s.handleReviews(w, r, parts[1])
+	case len(parts) == 4 && parts[0] == "apps" && parts[2] == "reviews" && parts[3] == "summary":
+		s.handleReviewSummary(w, r, parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// logRequests logs each request when a logger is configured
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	if s.config.Logger == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		s.config.Logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration", time.Since(start))
+	})
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// errorBody is the JSON body of an error response
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorBody{Error: message})
+}
+
+// badRequest is an error caused by the request's parameters
+type badRequest struct {
+	err error
+}
+
+func (e badRequest) Error() string {
+	return e.err.Error()
+}
+
+// writeFailure answers 400 for invalid parameters and 502 for upstream
+// failures
+func writeFailure(w http.ResponseWriter, err error) {
+	var bad badRequest
+	if errors.As(err, &bad) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadGateway, err.Error())
+}
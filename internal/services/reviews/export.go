@@ -0,0 +1,381 @@
+package reviews
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// MaxExportReviews is the most reviews a single export will contain
+const MaxExportReviews = maxScannedReviews
+
+// ExportFormat is a file format reviews can be exported to
+type ExportFormat string
+
+const (
+	ExportCSV      ExportFormat = "csv"
+	ExportJSON     ExportFormat = "json"
+	ExportNDJSON   ExportFormat = "ndjson"
+	ExportMarkdown ExportFormat = "md"
+	ExportHTML     ExportFormat = "html"
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []ExportFormat{ExportCSV, ExportJSON, ExportNDJSON, ExportMarkdown, ExportHTML}
+
+// ParseExportFormat validates an export format name
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch strings.ToLower(name) {
+	case "markdown":
+		return ExportMarkdown, nil
+	case "jsonl":
+		return ExportNDJSON, nil
+	}
+	for _, format := range ExportFormats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+
+	names := make([]string, len(ExportFormats))
+	for i, format := range ExportFormats {
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("invalid export format %q (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// ExportReport is the data behind an export
+type ExportReport struct {
+	AppID       string
+	Since       time.Time // Zero for no lower bound
+	Until       time.Time // Exclusive; zero for no upper bound
+	GeneratedAt time.Time
+	Reviews     []models.CustomerReview
+}
+
+// reportTerritory is one row of an export's territory table
+type reportTerritory struct {
+	Territory     string
+	Reviews       int
+	AverageRating float64
+	Unanswered    int
+}
+
+// reportRating is one bar of an export's rating histogram
+type reportRating struct {
+	Stars   int
+	Count   int
+	Percent float64
+}
+
+// reportSummary holds the aggregates shown at the top of Markdown and HTML exports
+type reportSummary struct {
+	Total         int
+	AverageRating float64
+	Unanswered    int
+	Histogram     []reportRating
+	Territories   []reportTerritory
+}
+
+// Export writes the report in the given format
+func Export(w io.Writer, format ExportFormat, report ExportReport) error {
+	switch format {
+	case ExportCSV:
+		return exportCSV(w, report.Reviews)
+	case ExportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(exportRows(report.Reviews))
+	case ExportNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, row := range exportRows(report.Reviews) {
+			if err := encoder.Encode(row); err != nil {
+				return fmt.Errorf("encoding review: %w", err)
+			}
+		}
+		return nil
+	case ExportMarkdown:
+		return exportMarkdown(w, report)
+	case ExportHTML:
+		return htmlReport.Execute(w, newReportView(report))
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// exportRows flattens reviews for the tabular formats
+func exportRows(reviews []models.CustomerReview) []models.ReviewExportRow {
+	rows := make([]models.ReviewExportRow, len(reviews))
+	for i, review := range reviews {
+		rows[i] = models.NewReviewExportRow(review)
+	}
+	return rows
+}
+
+// exportCSV writes one row per review with full bodies and responses
+func exportCSV(w io.Writer, reviews []models.CustomerReview) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"id", "date", "rating", "territory", "nickname", "title", "body",
+		"response", "response_state", "response_modified"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+
+	for _, row := range exportRows(reviews) {
+		record := []string{row.ID, row.Date, strconv.Itoa(row.Rating), row.Territory, row.Nickname,
+			row.Title, row.Body, row.Response, row.ResponseState, row.ResponseModified}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// summarizeReport computes the rating histogram and territory table. Territories
+// are ordered by review count.
+func summarizeReport(reviews []models.CustomerReview) reportSummary {
+	summary := reportSummary{Total: len(reviews)}
+
+	counts := make(map[int]int)
+	territories := make(map[string]*reportTerritory)
+	var totalRating float64
+
+	for _, review := range reviews {
+		rating := review.Attributes.Rating
+		counts[rating]++
+		totalRating += float64(rating)
+
+		territory := territories[review.Attributes.Territory]
+		if territory == nil {
+			territory = &reportTerritory{Territory: review.Attributes.Territory}
+			territories[review.Attributes.Territory] = territory
+		}
+		territory.Reviews++
+		territory.AverageRating += float64(rating)
+
+		if !review.HasResponse() {
+			summary.Unanswered++
+			territory.Unanswered++
+		}
+	}
+
+	if summary.Total > 0 {
+		summary.AverageRating = totalRating / float64(summary.Total)
+	}
+
+	for stars := 5; stars >= 1; stars-- {
+		bar := reportRating{Stars: stars, Count: counts[stars]}
+		if summary.Total > 0 {
+			bar.Percent = float64(bar.Count) / float64(summary.Total) * 100
+		}
+		summary.Histogram = append(summary.Histogram, bar)
+	}
+
+	for _, territory := range territories {
+		territory.AverageRating /= float64(territory.Reviews)
+		summary.Territories = append(summary.Territories, *territory)
+	}
+	sort.Slice(summary.Territories, func(i, j int) bool {
+		a, b := summary.Territories[i], summary.Territories[j]
+		if a.Reviews != b.Reviews {
+			return a.Reviews > b.Reviews
+		}
+		return a.Territory < b.Territory
+	})
+
+	return summary
+}
+
+// reportPeriod describes the date range a report covers
+func reportPeriod(report ExportReport) string {
+	const layout = "Jan 2, 2006"
+
+	switch {
+	case report.Since.IsZero() && report.Until.IsZero():
+		return "All time"
+	case report.Until.IsZero():
+		return report.Since.Format(layout) + " – " + report.GeneratedAt.Format(layout)
+	case report.Since.IsZero():
+		return "Until " + report.Until.Add(-time.Nanosecond).Format(layout)
+	default:
+		return report.Since.Format(layout) + " – " + report.Until.Add(-time.Nanosecond).Format(layout)
+	}
+}
+
+// exportMarkdown writes a summary followed by every review
+func exportMarkdown(w io.Writer, report ExportReport) error {
+	summary := summarizeReport(report.Reviews)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Review Report: App %s\n\n", report.AppID)
+	fmt.Fprintf(&b, "**Period:** %s  \n", reportPeriod(report))
+	fmt.Fprintf(&b, "**Reviews:** %d  \n", summary.Total)
+	fmt.Fprintf(&b, "**Average rating:** %.2f  \n", summary.AverageRating)
+	fmt.Fprintf(&b, "**Unanswered:** %d\n\n", summary.Unanswered)
+
+	b.WriteString("## Ratings\n\n| Rating | Reviews | Share |\n|--------|--------:|------:|\n")
+	for _, bar := range summary.Histogram {
+		fmt.Fprintf(&b, "| %s | %d | %.1f%% |\n", strings.Repeat("★", bar.Stars), bar.Count, bar.Percent)
+	}
+
+	if len(summary.Territories) > 0 {
+		b.WriteString("\n## Territories\n\n| Territory | Reviews | Avg Rating | Unanswered |\n|-----------|--------:|-----------:|-----------:|\n")
+		for _, t := range summary.Territories {
+			fmt.Fprintf(&b, "| %s | %d | %.2f | %d |\n", t.Territory, t.Reviews, t.AverageRating, t.Unanswered)
+		}
+	}
+
+	b.WriteString("\n## Reviews\n")
+	for _, review := range report.Reviews {
+		a := review.Attributes
+		fmt.Fprintf(&b, "\n### %s %s\n\n", strings.Repeat("★", clampRating(a.Rating)), markdownEscape(a.Title))
+		fmt.Fprintf(&b, "*%s · %s · %s*\n\n", markdownEscape(a.ReviewerNickname), a.Territory, a.CreatedDate.Format("2006-01-02"))
+		fmt.Fprintf(&b, "%s\n", markdownEscape(a.Body))
+
+		if review.Response != nil {
+			fmt.Fprintf(&b, "\n> **Response** (%s)  \n> %s\n",
+				review.Response.Attributes.State,
+				strings.ReplaceAll(markdownEscape(review.Response.Attributes.ResponseBody), "\n", "\n> "))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n---\n*Generated %s by pomme*\n", report.GeneratedAt.Format("2006-01-02 15:04"))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscape stops user text from being read as Markdown or HTML
+func markdownEscape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "#", `\#`, "|", `\|`,
+	"<", "&lt;", ">", "&gt;", "[", `\[`, "]", `\]`,
+)
+
+// reportView is the data passed to the HTML template
+type reportView struct {
+	ExportReport
+	Summary reportSummary
+	Period  string
+	MaxBar  int
+}
+
+func newReportView(report ExportReport) reportView {
+	view := reportView{
+		ExportReport: report,
+		Summary:      summarizeReport(report.Reviews),
+		Period:       reportPeriod(report),
+	}
+	for _, bar := range view.Summary.Histogram {
+		if bar.Count > view.MaxBar {
+			view.MaxBar = bar.Count
+		}
+	}
+	return view
+}
+
+// htmlReport is a self-contained page with inline styles and no external
+// assets, so it survives being emailed as an attachment
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"stars": func(n int) string {
+		n = clampRating(n)
+		return strings.Repeat("★", n) + strings.Repeat("☆", 5-n)
+	},
+	"width": func(count, max int) int {
+		if max == 0 {
+			return 0
+		}
+		return count * 100 / max
+	},
+	"date": func(t time.Time) string { return t.Format("Jan 2, 2006") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Review Report: App {{.AppID}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #1d1d1f; max-width: 880px; margin: 32px auto; padding: 0 16px; }
+h1 { font-size: 24px; margin-bottom: 4px; }
+h2 { font-size: 18px; margin-top: 32px; border-bottom: 1px solid #d2d2d7; padding-bottom: 6px; }
+.muted { color: #6e6e73; }
+.stats { display: flex; gap: 16px; margin: 20px 0; }
+.stat { flex: 1; background: #f5f5f7; border-radius: 10px; padding: 12px 16px; }
+.stat .value { font-size: 26px; font-weight: 600; }
+.histogram td { padding: 3px 8px; }
+.bar { background: #0071e3; height: 14px; border-radius: 3px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 6px 8px; }
+th { border-bottom: 1px solid #d2d2d7; font-weight: 600; }
+td.num, th.num { text-align: right; }
+.review { border-bottom: 1px solid #e8e8ed; padding: 14px 0; }
+.review .stars { color: #ff9f0a; }
+.review .title { font-weight: 600; }
+.review .body { white-space: pre-wrap; margin: 6px 0; }
+.response { background: #f5f5f7; border-left: 3px solid #0071e3; padding: 8px 12px; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Review Report: App {{.AppID}}</h1>
+<div class="muted">{{.Period}}</div>
+
+<div class="stats">
+  <div class="stat"><div class="muted">Reviews</div><div class="value">{{.Summary.Total}}</div></div>
+  <div class="stat"><div class="muted">Average rating</div><div class="value">{{printf "%.2f" .Summary.AverageRating}}</div></div>
+  <div class="stat"><div class="muted">Unanswered</div><div class="value">{{.Summary.Unanswered}}</div></div>
+</div>
+
+<h2>Ratings</h2>
+<table class="histogram">
+{{- range .Summary.Histogram}}
+  <tr>
+    <td>{{.Stars}} ★</td>
+    <td style="width: 70%"><div class="bar" style="width: {{width .Count $.MaxBar}}%"></div></td>
+    <td class="num">{{.Count}}</td>
+    <td class="num muted">{{printf "%.1f" .Percent}}%</td>
+  </tr>
+{{- end}}
+</table>
+
+{{- if .Summary.Territories}}
+<h2>Territories</h2>
+<table>
+  <tr><th>Territory</th><th class="num">Reviews</th><th class="num">Avg Rating</th><th class="num">Unanswered</th></tr>
+{{- range .Summary.Territories}}
+  <tr><td>{{.Territory}}</td><td class="num">{{.Reviews}}</td><td class="num">{{printf "%.2f" .AverageRating}}</td><td class="num">{{.Unanswered}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Reviews</h2>
+{{- range .Reviews}}
+<div class="review">
+  <div><span class="stars">{{stars .Attributes.Rating}}</span> <span class="title">{{.Attributes.Title}}</span></div>
+  <div class="muted">{{.Attributes.ReviewerNickname}} · {{.Attributes.Territory}} · {{date .Attributes.CreatedDate}}</div>
+  <div class="body">{{.Attributes.Body}}</div>
+  {{- with .Response}}
+  <div class="response"><strong>Response</strong> <span class="muted">({{.Attributes.State}})</span>
+{{.Attributes.ResponseBody}}</div>
+  {{- end}}
+</div>
+{{- else}}
+<p class="muted">No reviews in this period.</p>
+{{- end}}
+
+<p class="muted">Generated {{.GeneratedAt.Format "2006-01-02 15:04"}} by pomme</p>
+</body>
+</html>
+`))
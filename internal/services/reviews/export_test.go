@@ -0,0 +1,30 @@
+package reviews
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+func TestExportOutOfRangeRatings(t *testing.T) {
+	report := ExportReport{Reviews: []models.CustomerReview{
+		{ID: "1", Attributes: models.CustomerReviewAttributes{Rating: -1, Title: "Below"}},
+		{ID: "2", Attributes: models.CustomerReviewAttributes{Rating: 0, Title: "Unrated"}},
+		{ID: "3", Attributes: models.CustomerReviewAttributes{Rating: 7, Title: "Above"}},
+	}}
+
+	for _, format := range []ExportFormat{ExportMarkdown, ExportHTML} {
+		t.Run(string(format), func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Export(&b, format, report); err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			// Ratings are drawn as one to five stars
+			if strings.Contains(b.String(), "★★★★★★") {
+				t.Errorf("%s export draws more than five stars", format)
+			}
+		})
+	}
+}
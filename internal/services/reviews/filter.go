@@ -0,0 +1,131 @@
+package reviews
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// reviewMatcher applies the parts of a ReviewFilter the customerReviews
+// endpoint can't evaluate server-side
+type reviewMatcher struct {
+	text     *regexp.Regexp
+	nickname string
+	start    time.Time
+	end      time.Time
+}
+
+// serverQuery adds the filters the API supports natively to the query
+func serverQuery(q url.Values, filter models.ReviewFilter) {
+	if territories := filter.AllTerritories(); len(territories) > 0 {
+		q.Set("filter[territory]", strings.Join(territories, ","))
+	}
+
+	if ratings := filter.Ratings(); len(ratings) > 0 {
+		values := make([]string, len(ratings))
+		for i, r := range ratings {
+			values[i] = strconv.Itoa(r)
+		}
+		q.Set("filter[rating]", strings.Join(values, ","))
+	}
+
+	if filter.HasResponse != nil {
+		q.Set("exists[publishedResponse]", strconv.FormatBool(*filter.HasResponse))
+	}
+}
+
+// newReviewMatcher compiles the client-side part of a filter. It returns nil
+// if the filter has nothing that needs to be applied on the client.
+func newReviewMatcher(filter models.ReviewFilter) (*reviewMatcher, error) {
+	if filter.Text == "" && filter.Nickname == "" && filter.StartDate.IsZero() && filter.EndDate.IsZero() {
+		return nil, nil
+	}
+
+	m := &reviewMatcher{
+		nickname: strings.ToLower(filter.Nickname),
+		start:    filter.StartDate,
+		end:      filter.EndDate,
+	}
+
+	if filter.Text != "" {
+		re, err := regexp.Compile("(?i)" + filter.Text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid text pattern: %w", err)
+		}
+		m.text = re
+	}
+
+	return m, nil
+}
+
+// Matches reports whether a review passes the client-side filters. The end
+// date is exclusive.
+func (m *reviewMatcher) Matches(review models.CustomerReview) bool {
+	if m == nil {
+		return true
+	}
+
+	created := review.Attributes.CreatedDate
+	if !m.start.IsZero() && created.Before(m.start) {
+		return false
+	}
+	if !m.end.IsZero() && !created.Before(m.end) {
+		return false
+	}
+
+	if m.nickname != "" && !strings.Contains(strings.ToLower(review.Attributes.ReviewerNickname), m.nickname) {
+		return false
+	}
+
+	if m.text != nil && !m.text.MatchString(review.Attributes.Title) && !m.text.MatchString(review.Attributes.Body) {
+		return false
+	}
+
+	return true
+}
+
+// ParseTime parses an absolute date (YYYY-MM-DD or RFC 3339) or a
+// relative offset into the past such as 36h, 7d, 2w, 3m or 1y. With endOfDay
+// set, a bare date covers that whole day.
+func ParseTime(value string, now time.Time, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	
+	if len(value) < 2 {
+		return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+	}
+	
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, fmt.Errorf("unrecognized date %q (use YYYY-MM-DD or e.g. 7d)", value)
+	}
+	
+	switch value[len(value)-1] {
+	case 'h':
+		return now.Add(-time.Duration(n) * time.Hour), nil
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unrecognized unit in %q (use h, d, w, m or y)", value)
+	}
+}
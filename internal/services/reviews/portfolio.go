@@ -0,0 +1,168 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+const (
+	// PortfolioReviewLimit is how many recent reviews are fetched per app
+	PortfolioReviewLimit = 500
+
+	// minTerritoryReviews is the minimum number of reviews a territory needs
+	// before it can be reported as an app's worst territory
+	minTerritoryReviews = 3
+)
+
+// PortfolioSortFields lists the fields a portfolio can be sorted by
+var PortfolioSortFields = []string{"name", "rating", "reviews", "week", "month", "unanswered"}
+
+// GetPortfolioSummary fetches recent reviews for every app concurrently, with
+// at most concurrency requests in flight, and summarizes each app. A failure
+// for one app is recorded on its row rather than failing the whole portfolio.
+func (s *Service) GetPortfolioSummary(ctx context.Context, apps []models.App, concurrency int) []models.AppReviewStats {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]models.AppReviewStats, len(apps))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for i, app := range apps {
+		wg.Add(1)
+		go func(idx int, app models.App) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			reviews, err := s.GetReviews(ctx, models.ReviewFilter{
+				AppID: app.ID,
+				Limit: PortfolioReviewLimit,
+				Sort:  "recent",
+			})
+
+			stats := SummarizeAppReviews(reviews, time.Now())
+			if err != nil {
+				stats.Error = err.Error()
+			}
+			stats.AppID = app.ID
+			stats.Name = app.Attributes.Name
+			stats.BundleID = app.Attributes.BundleID
+
+			results[idx] = stats
+		}(i, app)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// SummarizeAppReviews computes the dashboard metrics for one app's reviews
+func SummarizeAppReviews(reviews []models.CustomerReview, now time.Time) models.AppReviewStats {
+	stats := models.AppReviewStats{
+		Reviews: len(reviews),
+	}
+
+	weekAgo := now.AddDate(0, 0, -7)
+	monthAgo := now.AddDate(0, 0, -30)
+
+	type territoryTotals struct {
+		count int
+		sum   float64
+	}
+	territories := make(map[string]*territoryTotals)
+
+	var totalRating float64
+	for _, review := range reviews {
+		rating := float64(review.Attributes.Rating)
+		totalRating += rating
+
+		if review.Attributes.CreatedDate.After(weekAgo) {
+			stats.Last7Days++
+		}
+		if review.Attributes.CreatedDate.After(monthAgo) {
+			stats.Last30Days++
+		}
+		if !review.HasResponse() {
+			stats.Unanswered++
+		}
+
+		territory := review.Attributes.Territory
+		if _, exists := territories[territory]; !exists {
+			territories[territory] = &territoryTotals{}
+		}
+		territories[territory].count++
+		territories[territory].sum += rating
+	}
+
+	if stats.Reviews > 0 {
+		stats.AverageRating = totalRating / float64(stats.Reviews)
+	}
+
+	// Find the lowest rated territory with enough reviews to be meaningful
+	for territory, totals := range territories {
+		if totals.count < minTerritoryReviews {
+			continue
+		}
+		avg := totals.sum / float64(totals.count)
+		if stats.WorstTerritory == "" || avg < stats.WorstTerritoryRating ||
+			(avg == stats.WorstTerritoryRating && territory < stats.WorstTerritory) {
+			stats.WorstTerritory = territory
+			stats.WorstTerritoryRating = avg
+		}
+	}
+
+	return stats
+}
+
+// SortPortfolio sorts portfolio rows in place. Ratings sort worst first and
+// counts sort largest first, so the apps needing attention come first.
+func SortPortfolio(stats []models.AppReviewStats, field string) error {
+	var less func(a, b models.AppReviewStats) bool
+
+	switch strings.ToLower(field) {
+	case "name", "":
+		less = func(a, b models.AppReviewStats) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+	case "rating":
+		// Apps without a rating, from having no reviews or a failed fetch,
+		// go last rather than ahead of the worst rated ones
+		less = func(a, b models.AppReviewStats) bool {
+			if rated(a) != rated(b) {
+				return rated(a)
+			}
+			return a.AverageRating < b.AverageRating
+		}
+	case "reviews":
+		less = func(a, b models.AppReviewStats) bool { return a.Reviews > b.Reviews }
+	case "week":
+		less = func(a, b models.AppReviewStats) bool { return a.Last7Days > b.Last7Days }
+	case "month":
+		less = func(a, b models.AppReviewStats) bool { return a.Last30Days > b.Last30Days }
+	case "unanswered":
+		less = func(a, b models.AppReviewStats) bool { return a.Unanswered > b.Unanswered }
+	default:
+		return fmt.Errorf("invalid sort field %q (valid: %s)", field, strings.Join(PortfolioSortFields, ", "))
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return less(stats[i], stats[j])
+	})
+	return nil
+}
+
+// rated reports whether a portfolio row has an average rating
+func rated(stats models.AppReviewStats) bool {
+	return stats.Error == "" && stats.Reviews > 0
+}
@@ -0,0 +1,251 @@
+package reviews
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+const (
+	// DefaultHalfLife is how long it takes a review's recency factor to halve
+	DefaultHalfLife = 14 * 24 * time.Hour
+
+	// detailedReviewLength is the body length, in characters, at which a
+	// review gets the full length factor
+	detailedReviewLength = 500
+
+	// minRankPool and maxRankPool bound how many recent reviews are fetched
+	// when a sort order has to be applied on the client
+	minRankPool = 200
+	maxRankPool = 1000
+)
+
+// PriorityWeights controls how much each factor contributes to a triage score.
+// Weights are relative; they don't need to sum to one.
+type PriorityWeights struct {
+	Rating     float64
+	Recency    float64
+	Length     float64
+	Revenue    float64
+	Unanswered float64
+}
+
+// DefaultPriorityWeights favors low ratings, then recent reviews from
+// territories that make money
+var DefaultPriorityWeights = PriorityWeights{
+	Rating:     0.35,
+	Recency:    0.20,
+	Length:     0.10,
+	Revenue:    0.20,
+	Unanswered: 0.15,
+}
+
+// Ranker scores reviews by how urgently they deserve a response
+type Ranker struct {
+	Weights PriorityWeights
+
+	// TerritoryWeights maps review territories (ISO 3166 alpha-3) to a 0-1
+	// revenue weight. Territories not in the map get no revenue credit.
+	TerritoryWeights map[string]float64
+
+	HalfLife time.Duration
+	Now      time.Time
+}
+
+// NewRanker creates a ranker with the default weights and half-life
+func NewRanker(territoryWeights map[string]float64) *Ranker {
+	return &Ranker{
+		Weights:          DefaultPriorityWeights,
+		TerritoryWeights: territoryWeights,
+		HalfLife:         DefaultHalfLife,
+		Now:              time.Now(),
+	}
+}
+
+// Score computes a review's triage score
+func (r *Ranker) Score(review models.CustomerReview) models.PrioritizedReview {
+	factors := models.PriorityFactors{
+		Rating: float64(5-clampRating(review.Attributes.Rating)) / 4,
+		Length: math.Min(float64(len([]rune(review.Attributes.Body)))/detailedReviewLength, 1),
+	}
+
+	age := r.Now.Sub(review.Attributes.CreatedDate)
+	if age < 0 || r.HalfLife <= 0 {
+		factors.Recency = 1
+	} else {
+		factors.Recency = math.Exp2(-float64(age) / float64(r.HalfLife))
+	}
+
+	factors.Revenue = r.TerritoryWeights[review.Attributes.Territory]
+
+	if !review.HasResponse() {
+		factors.Unanswered = 1
+	}
+
+	w := r.Weights
+	total := w.Rating + w.Recency + w.Length + w.Revenue + w.Unanswered
+	score := 0.0
+	if total > 0 {
+		score = (w.Rating*factors.Rating +
+			w.Recency*factors.Recency +
+			w.Length*factors.Length +
+			w.Revenue*factors.Revenue +
+			w.Unanswered*factors.Unanswered) / total * 100
+	}
+
+	return models.PrioritizedReview{
+		Review:  review,
+		Score:   score,
+		Factors: factors,
+	}
+}
+
+// Rank scores reviews and returns them highest priority first
+func (r *Ranker) Rank(reviews []models.CustomerReview) []models.PrioritizedReview {
+	ranked := make([]models.PrioritizedReview, len(reviews))
+	for i, review := range reviews {
+		ranked[i] = r.Score(review)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Review.Attributes.CreatedDate.After(ranked[j].Review.Attributes.CreatedDate)
+	})
+
+	return ranked
+}
+
+// TerritoryWeightsFromSales derives revenue weights for an app's review
+// territories from a sales report. Proceeds are reported in each territory's
+// own currency, so territories are weighted by paid units, which needs no
+// exchange rates. In-app purchases and subscriptions count towards their app,
+// so freemium apps are weighted by what users pay for; apps with no paid units
+// fall back to all units. The top territory gets 1.
+func TerritoryWeightsFromSales(report *models.SalesReport, appID string) map[string]float64 {
+	if report == nil {
+		return nil
+	}
+
+	paid := make(map[string]float64)
+	all := make(map[string]float64)
+	for _, app := range sales.NestApps(report.Apps) {
+		if app.AppID != appID {
+			continue
+		}
+		rows := append([]models.Sale(nil), app.Sales...)
+		for _, child := range app.Children {
+			rows = append(rows, child.Sales...)
+		}
+		for _, sale := range rows {
+			territory := territoryCode(sale.Country)
+			if territory == "" || sale.Units <= 0 {
+				continue
+			}
+			all[territory] += float64(sale.Units)
+			if sale.DeveloperProceeds.Amount > 0 {
+				paid[territory] += float64(sale.Units)
+			}
+		}
+	}
+
+	units := paid
+	if len(units) == 0 {
+		units = all
+	}
+
+	var top float64
+	for _, n := range units {
+		top = math.Max(top, n)
+	}
+	if top == 0 {
+		return nil
+	}
+
+	weights := make(map[string]float64, len(units))
+	for territory, n := range units {
+		weights[territory] = n / top
+	}
+	return weights
+}
+
+// isClientSort reports whether a sort order can't be expressed as an API
+// sort and has to be applied after fetching
+func isClientSort(order string) bool {
+	switch strings.ToLower(order) {
+	case "mostcritical", "critical", "mosthelpful", "helpful":
+		return true
+	}
+	return false
+}
+
+// rankPoolSize is how many recent reviews to fetch so a client-side sort has
+// enough candidates to pick limit reviews from
+func rankPoolSize(limit int) int {
+	pool := limit * 5
+	if pool < minRankPool {
+		pool = minRankPool
+	}
+	if pool > maxRankPool {
+		pool = maxRankPool
+	}
+	if pool < limit {
+		pool = limit
+	}
+	return pool
+}
+
+// sortReviews applies a client-side sort order in place. Critical puts the
+// lowest ratings first, newest first within a rating. The API exposes no
+// helpfulness votes, so helpful puts the most detailed reviews first.
+func sortReviews(reviews []models.CustomerReview, order string) {
+	var less func(a, b models.CustomerReview) bool
+
+	switch strings.ToLower(order) {
+	case "mostcritical", "critical":
+		less = func(a, b models.CustomerReview) bool {
+			if a.Attributes.Rating != b.Attributes.Rating {
+				return a.Attributes.Rating < b.Attributes.Rating
+			}
+			return a.Attributes.CreatedDate.After(b.Attributes.CreatedDate)
+		}
+	case "mosthelpful", "helpful":
+		less = func(a, b models.CustomerReview) bool {
+			la := len([]rune(a.Attributes.Title + a.Attributes.Body))
+			lb := len([]rune(b.Attributes.Title + b.Attributes.Body))
+			if la != lb {
+				return la > lb
+			}
+			return a.Attributes.CreatedDate.After(b.Attributes.CreatedDate)
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return less(reviews[i], reviews[j])
+	})
+}
+
+// clampRating keeps a rating within 1-5
+func clampRating(rating int) int {
+	if rating < 1 {
+		return 1
+	}
+	if rating > 5 {
+		return 5
+	}
+	return rating
+}
+
+// territoryCode converts a sales report country code (ISO 3166 alpha-2) to
+// the alpha-3 territory code used by customer reviews
+func territoryCode(country string) string {
+	return countries.Alpha3(country)
+}
@@ -0,0 +1,47 @@
+package reviews
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+func TestTerritoryWeightsFromSales(t *testing.T) {
+	usd := models.Money{Amount: 0.75, Currency: "USD"}
+	report := &models.SalesReport{Apps: []models.AppSales{
+		// A free app whose revenue comes from its subscription
+		{AppID: "111", SKU: "FOO", Sales: []models.Sale{
+			{Country: "US", Units: 1000},
+			{Country: "GB", Units: 500},
+		}},
+		{AppID: "112", SKU: "FOO_PRO", Sales: []models.Sale{
+			{Country: "GB", Units: 40, DeveloperProceeds: usd, ParentID: "FOO"},
+			{Country: "US", Units: 10, DeveloperProceeds: usd, ParentID: "FOO"},
+			{Country: "US", Units: -2, DeveloperProceeds: usd, ParentID: "FOO"},
+		}},
+		// Another app's sales don't count
+		{AppID: "222", SKU: "BAR", Sales: []models.Sale{{Country: "JP", Units: 90, DeveloperProceeds: usd}}},
+	}}
+
+	tests := []struct {
+		appID string
+		want  map[string]float64
+	}{
+		{"111", map[string]float64{"GBR": 1, "USA": 0.25}},
+		{"222", map[string]float64{"JPN": 1}},
+		{"333", nil},
+	}
+
+	for _, tt := range tests {
+		if got := TerritoryWeightsFromSales(report, tt.appID); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TerritoryWeightsFromSales(%s) = %v, want %v", tt.appID, got, tt.want)
+		}
+	}
+
+	// Free apps without in-app purchases fall back to downloads
+	free := &models.SalesReport{Apps: report.Apps[:1]}
+	if got, want := TerritoryWeightsFromSales(free, "111"), map[string]float64{"USA": 1, "GBR": 0.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("free app weights = %v, want %v", got, want)
+	}
+}
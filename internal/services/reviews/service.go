@@ -2,17 +2,27 @@ package reviews
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/marcusziade/pomme/internal/client"
-	"github.com/marcusziade/pomme/internal/models"
 	"github.com/marcusziade/pomme/internal/services/cache"
+	"github.com/marcusziade/pomme/pkg/jsonapi"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+const (
+	// maxPageSize is the largest page the customerReviews endpoint accepts
+	maxPageSize = 200
+
+	// maxScannedReviews bounds how many reviews a client-side filter may page
+	// through looking for matches
+	maxScannedReviews = 10000
 )
 
 // Service provides customer review functionality
@@ -24,15 +34,24 @@ type Service struct {
 
 // NewService creates a new reviews service
 func NewService(client *client.Client) *Service {
+	return NewServiceWithCache(client, cache.NewMemoryCache())
+}
+
+// NewServiceWithCache creates a reviews service that caches results in c
+func NewServiceWithCache(client *client.Client, c cache.Cache) *Service {
 	return &Service{
 		client: client,
-		cache:  cache.NewMemoryCache(),
+		cache:  c,
 	}
 }
 
 // GetReviews fetches customer reviews based on filter
 func (s *Service) GetReviews(ctx context.Context, filter models.ReviewFilter) ([]models.CustomerReview, error) {
-	cacheKey := fmt.Sprintf("reviews_%s_%s_%d", filter.AppID, filter.Territory, filter.Rating)
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	cacheKey := filter.CacheKey()
 	
 	// Check cache
 	if cached, err := s.cache.Get(cacheKey); err == nil {
@@ -41,6 +60,23 @@ func (s *Service) GetReviews(ctx context.Context, filter models.ReviewFilter) ([
 		}
 	}
 
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	// Sort orders the API can't express rank a larger pool of recent reviews
+	want := limit
+	if isClientSort(filter.Sort) {
+		want = rankPoolSize(limit)
+	}
+
+	// Anything the API can't filter on is applied to each page as it arrives
+	matcher, err := newReviewMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build request
 	endpoint := fmt.Sprintf("/v1/apps/%s/customerReviews", filter.AppID)
 	req, err := s.client.NewRequest(ctx, http.MethodGet, endpoint, nil)
@@ -49,50 +85,99 @@ func (s *Service) GetReviews(ctx context.Context, filter models.ReviewFilter) ([
 	}
 
 	// Add query parameters
-	q := req.URL.Query()
-	if filter.Territory != "" {
-		q.Add("filter[territory]", filter.Territory)
-	}
-	if filter.Rating > 0 {
-		q.Add("filter[rating]", strconv.Itoa(filter.Rating))
-	}
-	if filter.Limit > 0 {
-		q.Add("limit", strconv.Itoa(filter.Limit))
-	} else {
-		q.Add("limit", "100")
-	}
+	sortField := "-createdDate"
 	if filter.Sort != "" {
-		q.Add("sort", s.mapSortField(filter.Sort))
+		sortField = s.mapSortField(filter.Sort)
+	}
+
+	q := jsonapi.NewQuery().Sort(sortField).Include("response")
+	serverQuery(q.Values(), filter)
+	if want > maxPageSize || matcher != nil {
+		q.Limit(maxPageSize)
 	} else {
-		q.Add("sort", "-createdDate")
+		q.Limit(want)
 	}
 	req.URL.RawQuery = q.Encode()
 
-	// Execute request
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+	// Fetch pages until we have enough reviews or run out of pages
+	reviews := make([]models.CustomerReview, 0, want)
+	scanned := 0
+	for len(reviews) < want && scanned < maxScannedReviews {
+		page, next, err := s.fetchReviewPage(req)
+		if err != nil {
+			return nil, err
+		}
+		scanned += len(page)
+
+		for _, review := range page {
+			if matcher.Matches(review) {
+				reviews = append(reviews, review)
+			}
+		}
+
+		if next == "" || len(page) == 0 {
+			break
+		}
+
+		// Newest-first pages can't contain anything newer once we're past the start date
+		if sortField == "-createdDate" && !filter.StartDate.IsZero() &&
+			page[len(page)-1].Attributes.CreatedDate.Before(filter.StartDate) {
+			break
+		}
+
+		nextURL, err := url.Parse(next)
+		if err != nil {
+			return nil, fmt.Errorf("parsing next page link: %w", err)
+		}
+		req, err = s.client.NewRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.URL = nextURL
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	sortReviews(reviews, filter.Sort)
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
 	}
 
-	// Parse response
-	var response struct {
-		Data  []models.CustomerReview `json:"data"`
-		Links map[string]interface{}  `json:"links"`
+	// Cache the result
+	s.cache.Set(cacheKey, reviews, 5*time.Minute)
+
+	return reviews, nil
+}
+
+// fetchReviewPage executes a single customerReviews request and returns the
+// reviews on the page along with the link to the next page, if any
+func (s *Service) fetchReviewPage(req *http.Request) ([]models.CustomerReview, string, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("executing request: %w", err)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	doc, err := jsonapi.DecodeResponse[[]jsonapi.Resource[models.CustomerReviewAttributes]](resp)
+	if err != nil {
+		return nil, "", err
 	}
 
-	// Cache the result
-	s.cache.Set(cacheKey, response.Data, 5*time.Minute)
+	// Attach included developer responses to their reviews
+	included := doc.Index()
+	reviews := make([]models.CustomerReview, 0, len(doc.Data))
+	for _, item := range doc.Data {
+		response, err := jsonapi.ResolveOne[models.CustomerReviewResponseAttributes](included, item.Relationship("response"))
+		if err != nil {
+			return nil, "", err
+		}
+		reviews = append(reviews, models.CustomerReview{
+			ID:         item.ID,
+			Type:       item.Type,
+			Attributes: item.Attributes,
+			Links:      item.Links,
+			Response:   response,
+		})
+	}
 
-	return response.Data, nil
+	return reviews, doc.Links.Next, nil
 }
 
 // GetReviewSummary fetches aggregated review statistics
@@ -182,23 +267,21 @@ func (s *Service) RespondToReview(ctx context.Context, reviewID, responseText st
 	return s.createReviewResponse(ctx, reviewID, responseText)
 }
 
+// responseBodyAttributes are the attributes sent when writing a response
+type responseBodyAttributes struct {
+	ResponseBody string `json:"responseBody"`
+}
+
 // createReviewResponse creates a new response to a review
 func (s *Service) createReviewResponse(ctx context.Context, reviewID, responseText string) error {
 	endpoint := "/v1/customerReviewResponses"
 	
-	payload := map[string]interface{}{
-		"data": map[string]interface{}{
-			"type": "customerReviewResponses",
-			"attributes": map[string]string{
-				"responseBody": responseText,
-			},
-			"relationships": map[string]interface{}{
-				"review": map[string]interface{}{
-					"data": map[string]string{
-						"type": "customerReviews",
-						"id":   reviewID,
-					},
-				},
+	payload := jsonapi.Payload[responseBodyAttributes]{
+		Data: jsonapi.Resource[responseBodyAttributes]{
+			Type:       "customerReviewResponses",
+			Attributes: responseBodyAttributes{ResponseBody: responseText},
+			Relationships: map[string]jsonapi.Relationship{
+				"review": jsonapi.ToOne("customerReviews", reviewID),
 			},
 		},
 	}
@@ -234,25 +317,20 @@ func (s *Service) updateReviewResponse(ctx context.Context, reviewID, responseTe
 	if err != nil {
 		return fmt.Errorf("getting response: %w", err)
 	}
-	defer resp.Body.Close()
 
-	var responseData struct {
-		Data models.CustomerReviewResponse `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&responseData); err != nil {
-		return fmt.Errorf("decoding response: %w", err)
+	existing, err := jsonapi.DecodeResponse[models.CustomerReviewResponse](resp)
+	if err != nil {
+		return fmt.Errorf("getting response: %w", err)
 	}
 
 	// Update the response
-	updateEndpoint := fmt.Sprintf("/v1/customerReviewResponses/%s", responseData.Data.ID)
+	updateEndpoint := fmt.Sprintf("/v1/customerReviewResponses/%s", existing.Data.ID)
 	
-	payload := map[string]interface{}{
-		"data": map[string]interface{}{
-			"type": "customerReviewResponses",
-			"id":   responseData.Data.ID,
-			"attributes": map[string]string{
-				"responseBody": responseText,
-			},
+	payload := jsonapi.Payload[responseBodyAttributes]{
+		Data: jsonapi.Resource[responseBodyAttributes]{
+			Type:       "customerReviewResponses",
+			ID:         existing.Data.ID,
+			Attributes: responseBodyAttributes{ResponseBody: responseText},
 		},
 	}
 
@@ -274,15 +352,16 @@ func (s *Service) updateReviewResponse(ctx context.Context, reviewID, responseTe
 	return nil
 }
 
-// mapSortField maps user-friendly sort names to API fields
+// mapSortField maps user-friendly sort names to API fields. Orders the API
+// doesn't support fetch newest first and are sorted by sortReviews.
 func (s *Service) mapSortField(sort string) string {
 	switch strings.ToLower(sort) {
-	case "mostrecent", "recent":
-		return "-createdDate"
-	case "mostcritical", "critical":
+	case "oldest":
+		return "createdDate"
+	case "highest":
+		return "-rating"
+	case "lowest":
 		return "rating"
-	case "mosthelpful", "helpful":
-		return "-helpfulCount"
 	default:
 		return "-createdDate"
 	}
@@ -310,27 +389,9 @@ func (s *Service) GetReviewsByTerritory(ctx context.Context, appID, territory st
 
 // SearchReviews searches reviews by keyword
 func (s *Service) SearchReviews(ctx context.Context, appID, keyword string) ([]models.CustomerReview, error) {
-	// Get all reviews
-	reviews, err := s.GetReviews(ctx, models.ReviewFilter{
+	return s.GetReviews(ctx, models.ReviewFilter{
 		AppID: appID,
+		Text:  regexp.QuoteMeta(keyword),
 		Limit: 500,
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	// Filter by keyword
-	keyword = strings.ToLower(keyword)
-	var filtered []models.CustomerReview
-	
-	for _, review := range reviews {
-		title := strings.ToLower(review.Attributes.Title)
-		body := strings.ToLower(review.Attributes.Body)
-		
-		if strings.Contains(title, keyword) || strings.Contains(body, keyword) {
-			filtered = append(filtered, review)
-		}
-	}
-
-	return filtered, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,86 @@
+package reviews
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// stopWords are common English words that never make useful topics
+var stopWords = map[string]bool{
+	"about": true, "after": true, "again": true, "all": true, "also": true,
+	"always": true, "and": true, "any": true, "app": true, "are": true,
+	"because": true, "been": true, "before": true, "being": true, "but": true,
+	"can": true, "cant": true, "could": true, "did": true, "does": true,
+	"doesnt": true, "dont": true, "even": true, "every": true, "for": true,
+	"from": true, "get": true, "gets": true, "good": true, "great": true,
+	"had": true, "has": true, "have": true, "how": true, "its": true,
+	"just": true, "like": true, "love": true, "make": true, "many": true,
+	"more": true, "most": true, "much": true, "nice": true, "not": true,
+	"now": true, "one": true, "only": true, "other": true, "please": true,
+	"really": true, "should": true, "some": true, "still": true, "than": true,
+	"that": true, "the": true, "their": true, "them": true, "then": true,
+	"there": true, "these": true, "they": true, "thing": true, "this": true,
+	"time": true, "use": true, "used": true, "using": true, "very": true,
+	"want": true, "was": true, "way": true, "well": true, "were": true,
+	"what": true, "when": true, "which": true, "while": true, "will": true,
+	"with": true, "without": true, "work": true, "would": true, "you": true,
+	"your": true, "ive": true, "im": true, "isnt": true, "wont": true,
+}
+
+// ExtractTopics returns the n terms mentioned by the most reviews. Each review
+// counts at most once per term so a single long rant can't dominate.
+func ExtractTopics(reviews []models.CustomerReview, n int) []models.ReviewTopic {
+	counts := make(map[string]int)
+
+	for _, review := range reviews {
+		seen := make(map[string]bool)
+		for _, term := range tokenize(review.Attributes.Title + " " + review.Attributes.Body) {
+			if !seen[term] {
+				seen[term] = true
+				counts[term]++
+			}
+		}
+	}
+
+	topics := make([]models.ReviewTopic, 0, len(counts))
+	for term, count := range counts {
+		// A term only one reviewer used isn't a topic
+		if count < 2 {
+			continue
+		}
+		topics = append(topics, models.ReviewTopic{Term: term, Count: count})
+	}
+
+	sort.Slice(topics, func(i, j int) bool {
+		if topics[i].Count != topics[j].Count {
+			return topics[i].Count > topics[j].Count
+		}
+		return topics[i].Term < topics[j].Term
+	})
+
+	if len(topics) > n {
+		topics = topics[:n]
+	}
+	return topics
+}
+
+// tokenize splits text into lowercase words, dropping stop words,
+// apostrophes and anything shorter than three letters
+func tokenize(text string) []string {
+	text = strings.ToLower(strings.NewReplacer("'", "", "’", "").Replace(text))
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 3 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
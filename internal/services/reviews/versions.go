@@ -0,0 +1,321 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/jsonapi"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+const (
+	// significanceLevel is the p-value below which a rating drop is flagged
+	significanceLevel = 0.05
+
+	// minVersionSample is the minimum number of reviews both versions need
+	// before a drop between them is tested for significance
+	minVersionSample = 5
+)
+
+// releasedStates are the App Store version states that mean the version shipped
+var releasedStates = map[string]bool{
+	"READY_FOR_SALE":              true,
+	"READY_FOR_DISTRIBUTION":      true,
+	"REPLACED_WITH_NEW_VERSION":   true,
+	"REMOVED_FROM_SALE":           true,
+	"DEVELOPER_REMOVED_FROM_SALE": true,
+}
+
+// appStoreVersionAttributes are the fields requested for each version
+type appStoreVersionAttributes struct {
+	VersionString       string `json:"versionString"`
+	Platform            string `json:"platform"`
+	AppStoreState       string `json:"appStoreState"`
+	CreatedDate         string `json:"createdDate"`
+	EarliestReleaseDate string `json:"earliestReleaseDate"`
+}
+
+// GetAppStoreVersions fetches the released App Store versions of an app,
+// ordered by release date
+func (s *Service) GetAppStoreVersions(ctx context.Context, appID, platform string) ([]models.AppStoreVersion, error) {
+	cacheKey := fmt.Sprintf("versions_%s_%s", appID, platform)
+	if cached, err := s.cache.Get(cacheKey); err == nil {
+		if versions, ok := cached.([]models.AppStoreVersion); ok {
+			return versions, nil
+		}
+	}
+
+	endpoint := fmt.Sprintf("/v1/apps/%s/appStoreVersions", appID)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	q := jsonapi.NewQuery().
+		Fields("appStoreVersions", "versionString", "platform", "appStoreState", "createdDate", "earliestReleaseDate").
+		Limit(maxPageSize)
+	if platform != "" {
+		q.Filter("platform", platform)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	var versions []models.AppStoreVersion
+	for {
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("executing request: %w", err)
+		}
+
+		doc, err := jsonapi.DecodeResponse[[]jsonapi.Resource[appStoreVersionAttributes]](resp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range doc.Data {
+			if !releasedStates[v.Attributes.AppStoreState] {
+				continue
+			}
+
+			// The API has no actual release timestamp, so prefer the scheduled
+			// release date and fall back to when the version was created
+			released := parseAPITime(v.Attributes.EarliestReleaseDate)
+			if released.IsZero() {
+				released = parseAPITime(v.Attributes.CreatedDate)
+			}
+			if released.IsZero() {
+				continue
+			}
+
+			versions = append(versions, models.AppStoreVersion{
+				ID:          v.ID,
+				Version:     v.Attributes.VersionString,
+				Platform:    v.Attributes.Platform,
+				State:       v.Attributes.AppStoreState,
+				ReleaseDate: released,
+			})
+		}
+
+		next := doc.Links.Next
+		if next == "" {
+			break
+		}
+
+		nextURL, err := url.Parse(next)
+		if err != nil {
+			return nil, fmt.Errorf("parsing next page link: %w", err)
+		}
+		req, err = s.client.NewRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.URL = nextURL
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ReleaseDate.Before(versions[j].ReleaseDate)
+	})
+
+	s.cache.Set(cacheKey, versions, 30*time.Minute)
+
+	return versions, nil
+}
+
+// GetReviewsByVersion correlates reviews with the App Store version that was
+// live when they were written
+func (s *Service) GetReviewsByVersion(ctx context.Context, appID, platform string, reviewLimit int) ([]models.VersionReviewStats, error) {
+	versions, err := s.GetAppStoreVersions(ctx, appID, platform)
+	if err != nil {
+		return nil, fmt.Errorf("fetching versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no released versions found for app %s", appID)
+	}
+
+	reviews, err := s.GetReviews(ctx, models.ReviewFilter{
+		AppID: appID,
+		Limit: reviewLimit,
+		Sort:  "recent",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching reviews: %w", err)
+	}
+
+	return BucketReviewsByVersion(versions, reviews), nil
+}
+
+// BucketReviewsByVersion assigns each review to the release window of the
+// version that was live when it was written and computes per-version stats.
+// Versions must be sorted by release date. Reviews written before the first
+// version are ignored.
+func BucketReviewsByVersion(versions []models.AppStoreVersion, reviews []models.CustomerReview) []models.VersionReviewStats {
+	buckets := make([][]models.CustomerReview, len(versions))
+
+	for _, review := range reviews {
+		created := review.Attributes.CreatedDate
+		idx := sort.Search(len(versions), func(i int) bool {
+			return versions[i].ReleaseDate.After(created)
+		}) - 1
+		if idx < 0 {
+			continue
+		}
+		buckets[idx] = append(buckets[idx], review)
+	}
+
+	stats := make([]models.VersionReviewStats, len(versions))
+	variances := make([]float64, len(versions))
+
+	for i, version := range versions {
+		stat := models.VersionReviewStats{
+			Version:      version.Version,
+			ReleaseDate:  version.ReleaseDate,
+			ReviewCount:  len(buckets[i]),
+			RatingCounts: make(map[int]int),
+			TopTopics:    ExtractTopics(buckets[i], 5),
+		}
+		if i+1 < len(versions) {
+			stat.WindowEnd = versions[i+1].ReleaseDate
+		}
+
+		var sum float64
+		for _, review := range buckets[i] {
+			stat.RatingCounts[review.Attributes.Rating]++
+			sum += float64(review.Attributes.Rating)
+		}
+		if stat.ReviewCount > 0 {
+			stat.AverageRating = sum / float64(stat.ReviewCount)
+		}
+
+		if stat.ReviewCount > 1 {
+			var squares float64
+			for _, review := range buckets[i] {
+				diff := float64(review.Attributes.Rating) - stat.AverageRating
+				squares += diff * diff
+			}
+			variances[i] = squares / float64(stat.ReviewCount-1)
+		}
+
+		stats[i] = stat
+	}
+
+	// Compare each version with the previous one that has reviews
+	prev := -1
+	for i := range stats {
+		if stats[i].ReviewCount == 0 {
+			continue
+		}
+		if prev >= 0 {
+			stats[i].RatingChange = stats[i].AverageRating - stats[prev].AverageRating
+
+			if stats[i].ReviewCount >= minVersionSample && stats[prev].ReviewCount >= minVersionSample {
+				stats[i].PValue = dropPValue(
+					stats[prev].AverageRating, variances[prev], stats[prev].ReviewCount,
+					stats[i].AverageRating, variances[i], stats[i].ReviewCount,
+				)
+				stats[i].SignificantDrop = stats[i].RatingChange < 0 && stats[i].PValue < significanceLevel
+			}
+		}
+		prev = i
+	}
+
+	return stats
+}
+
+// dropPValue returns the one-sided p-value of Welch's t test for the
+// hypothesis that the second sample's mean is lower than the first's, with
+// the Welch-Satterthwaite degrees of freedom. Versions can be compared with
+// as few as minVersionSample reviews, where a normal approximation would
+// flag drops far too readily.
+func dropPValue(mean1, var1 float64, n1 int, mean2, var2 float64, n2 int) float64 {
+	se1, se2 := var1/float64(n1), var2/float64(n2)
+	se := math.Sqrt(se1 + se2)
+	if se == 0 {
+		if mean2 < mean1 {
+			return 0
+		}
+		return 1
+	}
+
+	dof := (se1 + se2) * (se1 + se2) / (se1*se1/float64(n1-1) + se2*se2/float64(n2-1))
+	return studentCDF((mean2-mean1)/se, dof)
+}
+
+// studentCDF returns P(T <= t) for Student's t distribution with dof degrees
+// of freedom, which needn't be a whole number
+func studentCDF(t, dof float64) float64 {
+	tail := 0.5 * regularizedBeta(dof/(dof+t*t), dof/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// regularizedBeta returns the regularized incomplete beta function I_x(a, b),
+// evaluated with the continued fraction from Numerical Recipes
+func regularizedBeta(x, a, b float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+
+	// The continued fraction converges quickly below the mean; above it, use
+	// the symmetry I_x(a, b) = 1 - I_(1-x)(b, a)
+	if x > (a+1)/(a+b+2) {
+		return 1 - regularizedBeta(1-x, b, a)
+	}
+
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab-lga-lgb+a*math.Log(x)+b*math.Log(1-x)) / a
+
+	// Lentz's method
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	f := d
+	for m := 1; m <= 200; m++ {
+		mf := float64(m)
+		for _, numerator := range []float64{
+			mf * (b - mf) * x / ((a + 2*mf - 1) * (a + 2*mf)),
+			-(a + mf) * (a + b + mf) * x / ((a + 2*mf) * (a + 2*mf + 1)),
+		} {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			f *= c * d
+		}
+		if math.Abs(c*d-1) < 1e-14 {
+			break
+		}
+	}
+	return front * f
+}
+
+// parseAPITime parses an App Store Connect timestamp, returning the zero time
+// if it is empty or malformed
+func parseAPITime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,63 @@
+package reviews
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentCDF(t *testing.T) {
+	tests := []struct {
+		t, dof float64
+		want   float64
+	}{
+		// Closed forms for one and two degrees of freedom
+		{1, 1, 0.5 + math.Atan(1)/math.Pi},
+		{-3, 1, 0.5 + math.Atan(-3)/math.Pi},
+		{2, 2, 0.5 + 2/(2*math.Sqrt(2+4))},
+		{-0.5, 2, 0.5 - 0.5/(2*math.Sqrt(2+0.25))},
+		{0, 7, 0.5},
+
+		// One-sided 5% critical values
+		{6.3138, 1, 0.95},
+		{-2.0150, 5, 0.05},
+		{-1.8125, 10, 0.05},
+		{-1.6973, 30, 0.05},
+
+		// Approaches the normal distribution
+		{-1.6449, 1e6, 0.05},
+	}
+
+	for _, tt := range tests {
+		if got := studentCDF(tt.t, tt.dof); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("studentCDF(%v, %v) = %.6f, want %.6f", tt.t, tt.dof, got, tt.want)
+		}
+	}
+}
+
+func TestDropPValue(t *testing.T) {
+	tests := []struct {
+		name           string
+		mean1, var1    float64
+		n1             int
+		mean2, var2    float64
+		n2             int
+		want, maxDelta float64
+	}{
+		// t = -2.015 with equal variances and sizes has 8 degrees of freedom
+		{"small samples", 4, 1, 5, 4 - 2.015*math.Sqrt(0.4), 1, 5, 0.0393, 1e-3},
+		{"no drop", 4, 1, 5, 4.5, 1, 5, 0.7740, 1e-3},
+		{"identical ratings, drop", 5, 0, 10, 4, 0, 10, 0, 0},
+		{"identical ratings, no drop", 4, 0, 10, 4, 0, 10, 1, 0},
+		// t = -2 with the 10 degrees of freedom of the side with spread
+		{"one sided spread", 4, 0, 10, 3, 2.75, 11, 0.0367, 1e-3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dropPValue(tt.mean1, tt.var1, tt.n1, tt.mean2, tt.var2, tt.n2)
+			if math.Abs(got-tt.want) > tt.maxDelta {
+				t.Errorf("dropPValue = %.6f, want %.6f", got, tt.want)
+			}
+		})
+	}
+}
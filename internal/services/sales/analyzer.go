@@ -6,7 +6,8 @@ import (
 	"sort"
 	"time"
 
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/pkg/models"
 )
 
 // Analyzer provides analysis and insights for sales data
@@ -166,6 +167,7 @@ func (a *Analyzer) AnalyzeTrendSeries(reports []*models.SalesReport, options Tre
 		Periods:       make([]time.Time, len(reports)),
 		Frequency:     options.Frequency,
 		TotalUnits:    make([]int, len(reports)),
+		Breakdowns:    make([]models.UnitBreakdown, len(reports)),
 		TotalProceeds: make(map[string][]float64),
 		AppTrends:     make(map[string]*AppTrend),
 		CountryTrends: make(map[string]*CountryTrend),
@@ -186,6 +188,7 @@ func (a *Analyzer) AnalyzeTrendSeries(reports []*models.SalesReport, options Tre
 		
 		trend.Periods[i] = report.Date
 		trend.TotalUnits[i] = report.Summary.TotalUnits
+		trend.Breakdowns[i] = report.Summary.Breakdown
 		
 		// Track proceeds by currency
 		for currency, amount := range report.Summary.TotalProceeds {
@@ -254,8 +257,8 @@ func (a *Analyzer) getCountrySet(report *models.SalesReport) map[string]bool {
 // calculateAppPerformance calculates performance changes for each app
 func (a *Analyzer) calculateAppPerformance(current, previous *models.SalesReport) []models.AppRanking {
 	prevAppMap := make(map[string]*models.AppSales)
-	for _, app := range previous.Apps {
-		prevAppMap[app.AppID] = &app
+	for i := range previous.Apps {
+		prevAppMap[previous.Apps[i].AppID] = &previous.Apps[i]
 	}
 
 	var rankings []models.AppRanking
@@ -294,13 +297,13 @@ func (a *Analyzer) calculateAppPerformance(current, previous *models.SalesReport
 // calculateAppChanges calculates detailed changes for each app
 func (a *Analyzer) calculateAppChanges(current, previous *models.SalesReport) []AppChange {
 	currAppMap := make(map[string]*models.AppSales)
-	for _, app := range current.Apps {
-		currAppMap[app.AppID] = &app
+	for i := range current.Apps {
+		currAppMap[current.Apps[i].AppID] = &current.Apps[i]
 	}
 	
 	prevAppMap := make(map[string]*models.AppSales)
-	for _, app := range previous.Apps {
-		prevAppMap[app.AppID] = &app
+	for i := range previous.Apps {
+		prevAppMap[previous.Apps[i].AppID] = &previous.Apps[i]
 	}
 
 	var changes []AppChange
@@ -362,8 +365,8 @@ func (a *Analyzer) calculateAppChanges(current, previous *models.SalesReport) []
 
 // calculateCountryChanges calculates performance changes by country
 func (a *Analyzer) calculateCountryChanges(current, previous *models.SalesReport) map[string]CountryChange {
-	currCountryData := a.aggregateByCountry(current)
-	prevCountryData := a.aggregateByCountry(previous)
+	currCountryData := aggregateByCountry(current)
+	prevCountryData := aggregateByCountry(previous)
 	
 	changes := make(map[string]CountryChange)
 	
@@ -398,28 +401,101 @@ func (a *Analyzer) calculateCountryChanges(current, previous *models.SalesReport
 	return changes
 }
 
-// aggregateByCountry aggregates sales data by country
-func (a *Analyzer) aggregateByCountry(report *models.SalesReport) map[string]*models.CountrySales {
+// aggregateByCountry totals a report's units, refunds and proceeds by country
+func aggregateByCountry(report *models.SalesReport) map[string]*models.CountrySales {
 	countryData := make(map[string]*models.CountrySales)
-	
 	for _, app := range report.Apps {
 		for _, sale := range app.Sales {
-			if _, exists := countryData[sale.Country]; !exists {
-				countryData[sale.Country] = &models.CountrySales{
-					Country:  sale.Country,
-					Proceeds: make(map[string]float64),
+			addCountrySale(countryData, sale)
+		}
+	}
+	return countryData
+}
+
+// addCountrySale counts a sale towards its country's totals. Rows without a
+// proceeds currency add units but no proceeds.
+func addCountrySale(countryData map[string]*models.CountrySales, sale models.Sale) {
+	country, exists := countryData[sale.Country]
+	if !exists {
+		country = &models.CountrySales{
+			Country:     sale.Country,
+			CountryName: countries.Name(sale.Country),
+			Proceeds:    make(map[string]float64),
+		}
+		countryData[sale.Country] = country
+	}
+
+	country.Units += sale.Units
+	country.Refunds.Add(sale)
+	if proceeds := sale.Proceeds(); proceeds != 0 && sale.DeveloperProceeds.Currency != "" {
+		country.Proceeds[sale.DeveloperProceeds.Currency] += proceeds
+	}
+}
+
+// CountryBreakdown totals a report's units and proceeds by country, with the
+// best-selling countries first
+func CountryBreakdown(report *models.SalesReport) []models.CountrySales {
+	countryData := aggregateByCountry(report)
+
+	countries := make([]models.CountrySales, 0, len(countryData))
+	for _, country := range countryData {
+		countries = append(countries, *country)
+	}
+
+	sort.Slice(countries, func(i, j int) bool {
+		if countries[i].Units != countries[j].Units {
+			return countries[i].Units > countries[j].Units
+		}
+		return countries[i].Country < countries[j].Country
+	})
+
+	return countries
+}
+
+// ProductTypeBreakdown totals a report's units and proceeds by product type,
+// in category order with the best-selling types first
+func ProductTypeBreakdown(report *models.SalesReport) []ProductTypeSales {
+	typeData := make(map[string]*ProductTypeSales)
+
+	for _, app := range report.Apps {
+		for _, sale := range app.Sales {
+			productType := models.LookupProductType(sale.ProductType)
+			if _, exists := typeData[productType.ID]; !exists {
+				typeData[productType.ID] = &ProductTypeSales{
+					ProductType: productType,
+					Proceeds:    make(map[string]float64),
 				}
 			}
-			
-			countryData[sale.Country].Units += sale.Units
-			
-			if sale.DeveloperProceeds.Amount > 0 && sale.DeveloperProceeds.Currency != "" {
-				countryData[sale.Country].Proceeds[sale.DeveloperProceeds.Currency] += sale.DeveloperProceeds.Amount
+
+			typeData[productType.ID].Units += sale.Units
+
+			if proceeds := sale.Proceeds(); proceeds != 0 {
+				typeData[productType.ID].Proceeds[sale.DeveloperProceeds.Currency] += proceeds
 			}
 		}
 	}
-	
-	return countryData
+
+	order := make(map[models.ProductCategory]int)
+	for i, category := range models.ProductCategories {
+		order[category] = i
+	}
+
+	types := make([]ProductTypeSales, 0, len(typeData))
+	for _, productType := range typeData {
+		types = append(types, *productType)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Category != types[j].Category {
+			return order[types[i].Category] < order[types[j].Category]
+		}
+		if types[i].Units != types[j].Units {
+			return types[i].Units > types[j].Units
+		}
+		return types[i].ID < types[j].ID
+	})
+
+	return types
 }
 
 // getAllCurrencies returns all currencies present in the reports
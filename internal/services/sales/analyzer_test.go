@@ -0,0 +1,36 @@
+package sales
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+func TestCountryBreakdown(t *testing.T) {
+	usd := func(amount float64) models.Money { return models.Money{Amount: amount, Currency: "USD"} }
+	report := &models.SalesReport{Apps: []models.AppSales{
+		{AppID: "1", Sales: []models.Sale{
+			{Country: "US", Units: 3, DeveloperProceeds: usd(0.75)},
+			{Country: "FI", Units: 2, DeveloperProceeds: models.Money{Amount: 0.5, Currency: "EUR"}},
+			// Rows without a currency add units but no proceeds
+			{Country: "FI", Units: 5, CountryName: "Suomi", DeveloperProceeds: models.Money{Amount: 0.5}},
+		}},
+		{AppID: "2", Sales: []models.Sale{
+			{Country: "US", Units: 4, DeveloperProceeds: usd(1.5)},
+			{Country: "US", Units: -1, DeveloperProceeds: usd(1.5)},
+		}},
+	}}
+
+	got := CountryBreakdown(report)
+	for i := range got {
+		got[i].Refunds = models.Refunds{} // Covered by the refund tests
+	}
+	want := []models.CountrySales{
+		{Country: "FI", CountryName: "Finland", Units: 7, Proceeds: map[string]float64{"EUR": 1}},
+		{Country: "US", CountryName: "United States", Units: 6, Proceeds: map[string]float64{"USD": 3*0.75 + 4*1.5 - 1.5}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CountryBreakdown =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,444 @@
+package sales
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// Anomaly detection defaults
+const (
+	DefaultAnomalyWindow    = 28
+	DefaultAnomalyThreshold = 3.5
+	DefaultAnomalyMinUnits  = 5
+)
+
+// minAnomalyBaseline is the fewest trailing days with reports a day is
+// compared with
+const minAnomalyBaseline = 7
+
+// maxAnomalyDrivers is the most drivers listed per dimension
+const maxAnomalyDrivers = 3
+
+// Series and driver dimensions of anomalies
+const (
+	DimensionApp         = "app"
+	DimensionCountry     = "country"
+	DimensionProductType = "product_type"
+	DimensionPromoCode   = "promo_code"
+)
+
+// AnomalyOptions configures anomaly detection over daily reports
+type AnomalyOptions struct {
+	EndDate      time.Time // Last day to check
+	Days         int       // Days to check, ending at EndDate
+	Window       int       // Trailing days each day is compared with
+	Threshold    float64   // Robust z-score from which a day is anomalous
+	MinUnits     float64   // Smallest difference from the expected units that's reported
+	ReportType   models.ReportType
+	VendorNumber string
+	NoCache      bool
+	Filter       *FilterOptions
+}
+
+// AnomalyReport lists the days on which an app or country sold unusually
+// much or little
+type AnomalyReport struct {
+	Days     []time.Time // Days checked
+	Missing  []time.Time // Days Apple had no report for
+	Series   int         // Apps and countries checked
+	Insights []Insight   // Anomalies, newest first
+}
+
+// Anomaly is one app's or country's unusual day
+type Anomaly struct {
+	Date      time.Time
+	Dimension string // app or country
+	Key       string // App ID or country code
+	Name      string
+	Units     float64
+	Expected  float64 // Trailing median, adjusted for the day of the week
+	Score     float64 // Robust z-score; negative for drops
+	Drivers   []AnomalyDriver
+}
+
+// AnomalyDriver is a country, app, product type or promo code that made up
+// much of an anomaly
+type AnomalyDriver struct {
+	Dimension string
+	Key       string
+	Name      string
+	Units     float64
+	Expected  float64 // Its share of the series' expected units
+	Change    float64 // Units - Expected
+}
+
+// GetAnomalies fetches daily reports and detects anomalies in them
+func (s *Service) GetAnomalies(ctx context.Context, options AnomalyOptions) (*AnomalyReport, error) {
+	if options.Days < 1 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+
+	end := time.Date(options.EndDate.Year(), options.EndDate.Month(), options.EndDate.Day(), 0, 0, 0, 0, time.UTC)
+	requests := make([]ReportOptions, options.Days)
+	for i := range requests {
+		requests[i] = ReportOptions{
+			Period:       models.ReportFrequencyDaily,
+			Date:         end.AddDate(0, 0, i-options.Days+1),
+			ReportType:   options.ReportType,
+			VendorNumber: options.VendorNumber,
+			NoCache:      options.NoCache,
+			Filter:       options.Filter,
+		}
+	}
+
+	reports, err := s.GetMultipleReports(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]time.Time, len(requests))
+	for i, request := range requests {
+		days[i] = request.Date
+	}
+	return DetectAnomalies(days, reports, options), nil
+}
+
+// DetectAnomalies flags days on which an app or a country sold unusually
+// much or little. reports holds one daily report per day, nil where Apple
+// had none; missing days are skipped rather than counted as zero.
+//
+// Every day is scored with a robust z-score against the median and median
+// absolute deviation (MAD) of the trailing Window days. Both the day and its
+// baseline are first divided by day-of-week factors, the median of each
+// weekday over the median of all days in the baseline, so weekends aren't
+// flagged for being weekends. Only days before the scored one are used, so
+// a day is scored the same however many days follow it. The MAD is floored
+// at the spread of a Poisson count, so that apps selling a handful of units
+// don't flag every change of one or two.
+func DetectAnomalies(days []time.Time, reports []*models.SalesReport, options AnomalyOptions) *AnomalyReport {
+	if options.Window <= 0 {
+		options.Window = DefaultAnomalyWindow
+	}
+	if options.Threshold <= 0 {
+		options.Threshold = DefaultAnomalyThreshold
+	}
+
+	result := &AnomalyReport{Days: days}
+	for i, report := range reports {
+		if report == nil {
+			result.Missing = append(result.Missing, days[i])
+		}
+	}
+
+	series := buildAnomalySeries(reports)
+	result.Series = len(series)
+
+	var anomalies []Anomaly
+	for _, s := range series {
+		anomalies = append(anomalies, s.detect(days, reports, options)...)
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		if !anomalies[i].Date.Equal(anomalies[j].Date) {
+			return anomalies[i].Date.After(anomalies[j].Date)
+		}
+		return math.Abs(anomalies[i].Score) > math.Abs(anomalies[j].Score)
+	})
+
+	for _, anomaly := range anomalies {
+		result.Insights = append(result.Insights, anomaly.Insight())
+	}
+	return result
+}
+
+// Insight describes the anomaly as an insight, with the anomaly under the
+// "anomaly" key of its data
+func (a Anomaly) Insight() Insight {
+	kind, severity := "Spike", InsightSeveritySuccess
+	if a.Score < 0 {
+		kind, severity = "Drop", InsightSeverityWarning
+		if a.Units < a.Expected/2 {
+			// Lost more than half of the usual sales
+			severity = InsightSeverityCritical
+		}
+	}
+
+	description := fmt.Sprintf("%.0f units vs %.0f expected (%+.0f, z-score %.1f)",
+		a.Units, a.Expected, a.Units-a.Expected, a.Score)
+	if len(a.Drivers) > 0 {
+		drivers := make([]string, len(a.Drivers))
+		for i, driver := range a.Drivers {
+			drivers[i] = fmt.Sprintf("%s %+.0f", driver.Label(), driver.Change)
+		}
+		description += ", driven by " + strings.Join(drivers, ", ")
+	}
+
+	return Insight{
+		Type:        InsightAnomaly,
+		Severity:    severity,
+		Title:       fmt.Sprintf("%s: %s on %s", kind, a.Name, a.Date.Format("Mon Jan 2, 2006")),
+		Description: description,
+		Data: map[string]interface{}{
+			"anomaly": a,
+		},
+	}
+}
+
+// Label names the driver with its dimension where the name alone is unclear
+func (d AnomalyDriver) Label() string {
+	switch d.Dimension {
+	case DimensionPromoCode:
+		return "promo " + d.Name
+	case DimensionCountry:
+		if country, ok := countries.Lookup(d.Key); ok {
+			return country.Flag() + " " + d.Name
+		}
+	}
+	return d.Name
+}
+
+// anomalySeries is one app's or country's daily units, with the units of
+// each driver per day
+type anomalySeries struct {
+	dimension string
+	key       string
+	name      string
+	units     []float64
+	drivers   []map[driverKey]float64 // Day -> Driver -> Units
+	names     map[driverKey]string
+}
+
+type driverKey struct {
+	dimension string
+	key       string
+}
+
+// buildAnomalySeries splits the reports into a series per app and per
+// country
+func buildAnomalySeries(reports []*models.SalesReport) []*anomalySeries {
+	byKey := make(map[driverKey]*anomalySeries)
+	var series []*anomalySeries
+
+	get := func(dimension, key, name string) *anomalySeries {
+		k := driverKey{dimension, key}
+		s, ok := byKey[k]
+		if !ok {
+			s = &anomalySeries{
+				dimension: dimension,
+				key:       key,
+				name:      name,
+				units:     make([]float64, len(reports)),
+				drivers:   make([]map[driverKey]float64, len(reports)),
+				names:     make(map[driverKey]string),
+			}
+			byKey[k] = s
+			series = append(series, s)
+		}
+		return s
+	}
+
+	for day, report := range reports {
+		if report == nil {
+			continue
+		}
+		for _, app := range report.Apps {
+			for _, sale := range app.Sales {
+				productType := models.LookupProductType(sale.ProductType)
+
+				appSeries := get(DimensionApp, app.AppID, app.AppName)
+				countrySeries := get(DimensionCountry, sale.Country, countries.Name(sale.Country))
+
+				appSeries.add(day, sale.Units, DimensionCountry, sale.Country, countries.Name(sale.Country))
+				countrySeries.add(day, sale.Units, DimensionApp, app.AppID, app.AppName)
+				for _, s := range []*anomalySeries{appSeries, countrySeries} {
+					s.addDriver(day, sale.Units, DimensionProductType, productType.ID, productType.Name)
+					if sale.PromoCode != "" {
+						s.addDriver(day, sale.Units, DimensionPromoCode, sale.PromoCode, sale.PromoCode)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].dimension != series[j].dimension {
+			return series[i].dimension < series[j].dimension
+		}
+		return series[i].key < series[j].key
+	})
+	return series
+}
+
+// add counts units towards the series and one of its drivers
+func (s *anomalySeries) add(day, units int, dimension, key, name string) {
+	s.units[day] += float64(units)
+	s.addDriver(day, units, dimension, key, name)
+}
+
+func (s *anomalySeries) addDriver(day, units int, dimension, key, name string) {
+	k := driverKey{dimension, key}
+	if s.drivers[day] == nil {
+		s.drivers[day] = make(map[driverKey]float64)
+	}
+	s.drivers[day][k] += float64(units)
+	s.names[k] = name
+}
+
+// detect returns the series' anomalous days
+func (s *anomalySeries) detect(days []time.Time, reports []*models.SalesReport, options AnomalyOptions) []Anomaly {
+	var anomalies []Anomaly
+	for i := range s.units {
+		if reports[i] == nil {
+			continue
+		}
+
+		var baseline []int
+		for j := i - options.Window; j < i; j++ {
+			if j >= 0 && reports[j] != nil {
+				baseline = append(baseline, j)
+			}
+		}
+		if len(baseline) < minAnomalyBaseline {
+			continue
+		}
+
+		factors := weekdayFactors(days, s.units, baseline)
+		window := make([]float64, len(baseline))
+		for k, j := range baseline {
+			window[k] = s.units[j] / factors[days[j].Weekday()]
+		}
+		center := median(window)
+		deviations := make([]float64, len(window))
+		for k, value := range window {
+			deviations[k] = math.Abs(value - center)
+		}
+		// 1.4826 scales the MAD to a standard deviation for normal data
+		spread := math.Max(1.4826*median(deviations), math.Sqrt(math.Max(center, 1)))
+
+		score := (s.units[i]/factors[days[i].Weekday()] - center) / spread
+		expected := center * factors[days[i].Weekday()]
+		if math.Abs(score) < options.Threshold || math.Abs(s.units[i]-expected) < options.MinUnits {
+			continue
+		}
+
+		anomalies = append(anomalies, Anomaly{
+			Date:      days[i],
+			Dimension: s.dimension,
+			Key:       s.key,
+			Name:      s.name,
+			Units:     s.units[i],
+			Expected:  expected,
+			Score:     score,
+			Drivers:   s.explain(i, baseline, expected),
+		})
+	}
+	return anomalies
+}
+
+// explain splits the difference between a day's units and the expected units
+// over the drivers. Each driver is expected to sell its share of the
+// baseline days' units; those that moved furthest in the anomaly's direction
+// are returned, by dimension.
+func (s *anomalySeries) explain(day int, baseline []int, expected float64) []AnomalyDriver {
+	var baselineTotal float64
+	shares := make(map[driverKey]float64)
+	for _, j := range baseline {
+		baselineTotal += s.units[j]
+		for k, units := range s.drivers[j] {
+			shares[k] += units
+		}
+	}
+
+	keys := make(map[driverKey]bool)
+	for k := range shares {
+		keys[k] = true
+	}
+	for k := range s.drivers[day] {
+		keys[k] = true
+	}
+
+	direction := 1.0
+	if s.units[day] < expected {
+		direction = -1
+	}
+
+	byDimension := make(map[string][]AnomalyDriver)
+	for k := range keys {
+		driver := AnomalyDriver{
+			Dimension: k.dimension,
+			Key:       k.key,
+			Name:      s.names[k],
+			Units:     s.drivers[day][k],
+		}
+		if baselineTotal != 0 {
+			driver.Expected = shares[k] / baselineTotal * expected
+		}
+		driver.Change = driver.Units - driver.Expected
+
+		// Drivers that moved with the anomaly and made up a tenth of it
+		if driver.Change*direction >= math.Max(1, 0.1*math.Abs(s.units[day]-expected)) {
+			byDimension[k.dimension] = append(byDimension[k.dimension], driver)
+		}
+	}
+
+	var drivers []AnomalyDriver
+	for _, dimension := range []string{DimensionCountry, DimensionApp, DimensionProductType, DimensionPromoCode} {
+		candidates := byDimension[dimension]
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].Change != candidates[j].Change {
+				return candidates[i].Change*direction > candidates[j].Change*direction
+			}
+			return candidates[i].Key < candidates[j].Key
+		})
+		if len(candidates) > maxAnomalyDrivers {
+			candidates = candidates[:maxAnomalyDrivers]
+		}
+		drivers = append(drivers, candidates...)
+	}
+	return drivers
+}
+
+// weekdayFactors returns how much each day of the week sells relative to
+// the median of the baseline days. Weekdays without sales or data count as 1.
+func weekdayFactors(days []time.Time, units []float64, baseline []int) [7]float64 {
+	all := make([]float64, len(baseline))
+	var byWeekday [7][]float64
+	for k, i := range baseline {
+		all[k] = units[i]
+		byWeekday[days[i].Weekday()] = append(byWeekday[days[i].Weekday()], units[i])
+	}
+
+	var factors [7]float64
+	overall := median(all)
+	for weekday := range factors {
+		factors[weekday] = 1
+		if overall > 0 && len(byWeekday[weekday]) > 0 {
+			if m := median(byWeekday[weekday]); m > 0 {
+				factors[weekday] = m / overall
+			}
+		}
+	}
+	return factors
+}
+
+// median returns the median of values, or 0 for none. values is left
+// unchanged.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,221 @@
+package sales
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// anomalyDays starts on a Monday, so day 5 is the first Saturday
+var anomalyDays = time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+// dailyReports returns a day and a report for each entry of units, with one
+// app selling the units in one country. Negative units are days without a
+// report.
+func dailyReports(units []int) ([]time.Time, []*models.SalesReport) {
+	days := make([]time.Time, len(units))
+	reports := make([]*models.SalesReport, len(units))
+	for i, n := range units {
+		days[i] = anomalyDays.AddDate(0, 0, i)
+		if n >= 0 {
+			reports[i] = salesDay(map[string]int{"US": n})
+		}
+	}
+	return days, reports
+}
+
+// salesDay returns a report of one app selling units by country
+func salesDay(units map[string]int) *models.SalesReport {
+	app := models.AppSales{AppID: "111", AppName: "Foo App"}
+	for country, n := range units {
+		app.Sales = append(app.Sales, models.Sale{Country: country, Units: n, ProductType: "1"})
+	}
+	return &models.SalesReport{Apps: []models.AppSales{app}}
+}
+
+// flatUnits returns days of the same units, with changes on some days
+func flatUnits(days, units int, changes map[int]int) []int {
+	series := make([]int, days)
+	for i := range series {
+		series[i] = units
+		if n, ok := changes[i]; ok {
+			series[i] = n
+		}
+	}
+	return series
+}
+
+// appAnomalies returns the anomalies of the app series, newest first
+func appAnomalies(report *AnomalyReport) []Anomaly {
+	var anomalies []Anomaly
+	for _, insight := range report.Insights {
+		if anomaly := insight.Data["anomaly"].(Anomaly); anomaly.Dimension == DimensionApp {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	return anomalies
+}
+
+func TestDetectAnomaliesScores(t *testing.T) {
+	type flagged struct {
+		day      int
+		expected float64
+		score    float64
+	}
+
+	weekly := make([]int, 56)
+	for i := range weekly {
+		weekly[i] = 100
+		if i%7 >= 5 {
+			weekly[i] = 300 // Weekends sell three times as much
+		}
+	}
+	quietWeekend := append([]int(nil), weekly...)
+	quietWeekend[54] = 100 // A Saturday selling like a weekday
+
+	alternating := make([]int, 30)
+	for i := range alternating {
+		alternating[i] = 2 + i%2
+	}
+	alternating[29] = 7
+
+	tests := []struct {
+		name    string
+		units   []int
+		options AnomalyOptions
+		want    []flagged
+	}{
+		{
+			name:  "spike and drop",
+			units: flatUnits(40, 100, map[int]int{35: 200, 38: 20}),
+			// The MAD of a flat series is 0, so the Poisson floor of 10 applies
+			want: []flagged{{38, 100, -8}, {35, 100, 10}},
+		},
+		{
+			name:  "too little history",
+			units: flatUnits(7, 100, map[int]int{6: 500}),
+		},
+		{
+			name:  "weekly pattern",
+			units: weekly,
+		},
+		{
+			name:  "weekend drop",
+			units: quietWeekend,
+			// Scored as 100/3 adjusted units against 100, with the floor of 10
+			want: []flagged{{54, 300, -20.0 / 3}},
+		},
+		{
+			name:  "small counts",
+			units: alternating,
+		},
+		{
+			name:    "below min units",
+			units:   flatUnits(30, 100, map[int]int{29: 140}),
+			options: AnomalyOptions{MinUnits: 50},
+		},
+		{
+			name:    "threshold",
+			units:   flatUnits(30, 100, map[int]int{29: 140}),
+			options: AnomalyOptions{Threshold: 5},
+		},
+		{
+			name:  "missing days",
+			units: flatUnits(30, 100, map[int]int{10: -1, 11: -1, 12: -1, 29: 0}),
+			want:  []flagged{{29, 100, -10}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, reports := dailyReports(tt.units)
+			anomalies := appAnomalies(DetectAnomalies(days, reports, tt.options))
+			if len(anomalies) != len(tt.want) {
+				t.Fatalf("got %d anomalies %+v, want %d", len(anomalies), anomalies, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := anomalies[i]
+				if !got.Date.Equal(days[want.day]) {
+					t.Errorf("anomaly %d on %s, want %s", i, got.Date.Format("2006-01-02"), days[want.day].Format("2006-01-02"))
+				}
+				if math.Abs(got.Expected-want.expected) > 1e-6 || math.Abs(got.Score-want.score) > 1e-6 {
+					t.Errorf("anomaly %d expected %.2f with score %.2f, want %.2f with %.2f",
+						i, got.Expected, got.Score, want.expected, want.score)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectAnomaliesMissingDays(t *testing.T) {
+	days, reports := dailyReports(flatUnits(30, 100, map[int]int{10: -1, 20: -1}))
+	report := DetectAnomalies(days, reports, AnomalyOptions{})
+
+	if want := []time.Time{days[10], days[20]}; !reflect.DeepEqual(report.Missing, want) {
+		t.Errorf("Missing = %v, want %v", report.Missing, want)
+	}
+	if len(report.Insights) != 0 {
+		t.Errorf("missing days were flagged: %+v", report.Insights)
+	}
+	if report.Series != 2 {
+		t.Errorf("Series = %d, want an app and a country", report.Series)
+	}
+}
+
+func TestDetectAnomaliesIgnoresLaterDays(t *testing.T) {
+	// Saturdays jump after the spike on Saturday 33. Factors learned from the
+	// whole series would expect more of every Saturday, flagging the earlier
+	// ones as drops and the spike as ordinary.
+	units := flatUnits(63, 100, map[int]int{33: 200})
+	for day := 40; day < len(units); day += 7 {
+		units[day] = 400
+	}
+
+	days, reports := dailyReports(units)
+	before := appAnomalies(DetectAnomalies(days[:34], reports[:34], AnomalyOptions{}))
+	after := appAnomalies(DetectAnomalies(days, reports, AnomalyOptions{}))
+
+	var earlier []Anomaly
+	for _, anomaly := range after {
+		if !anomaly.Date.After(days[33]) {
+			earlier = append(earlier, anomaly)
+		}
+	}
+	if len(before) != 1 || !reflect.DeepEqual(before, earlier) {
+		t.Errorf("later days changed earlier anomalies:\nbefore %+v\nafter  %+v", before, earlier)
+	}
+}
+
+func TestDetectAnomaliesDrivers(t *testing.T) {
+	units := make([]map[string]int, 30)
+	for i := range units {
+		units[i] = map[string]int{"US": 60, "GB": 40}
+	}
+	units[29] = map[string]int{"US": 160, "GB": 40}
+
+	days := make([]time.Time, len(units))
+	reports := make([]*models.SalesReport, len(units))
+	for i := range units {
+		days[i] = anomalyDays.AddDate(0, 0, i)
+		reports[i] = salesDay(units[i])
+	}
+
+	anomalies := appAnomalies(DetectAnomalies(days, reports, AnomalyOptions{}))
+	if len(anomalies) != 1 {
+		t.Fatalf("got %d anomalies, want 1", len(anomalies))
+	}
+
+	var countries []AnomalyDriver
+	for _, driver := range anomalies[0].Drivers {
+		if driver.Dimension == DimensionCountry {
+			countries = append(countries, driver)
+		}
+	}
+	want := []AnomalyDriver{{Dimension: DimensionCountry, Key: "US", Name: "United States", Units: 160, Expected: 60, Change: 100}}
+	if !reflect.DeepEqual(countries, want) {
+		t.Errorf("country drivers = %+v, want %+v", countries, want)
+	}
+}
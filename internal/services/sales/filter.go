@@ -0,0 +1,534 @@
+package sales
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// Match reports whether a sale of app passes every filter. Numeric limits
+// apply to the sale's own units and total proceeds.
+func (f *FilterOptions) Match(app *models.AppSales, sale *models.Sale) bool {
+	if len(f.Apps) > 0 && !anyFold(appKeys(app, sale), f.Apps) {
+		return false
+	}
+	if len(f.Countries) > 0 && !anyFold([]string{sale.Country}, f.Countries) {
+		return false
+	}
+	if len(f.Regions) > 0 && !anyRegion(sale.Country, f.Regions) {
+		return false
+	}
+	if f.MinUnits > 0 && sale.Units < f.MinUnits {
+		return false
+	}
+	if f.MinProceeds > 0 && sale.Proceeds() < f.MinProceeds {
+		return false
+	}
+	if f.Currency != "" && !strings.EqualFold(sale.DeveloperProceeds.Currency, f.Currency) {
+		return false
+	}
+	if len(f.ProductTypes) > 0 && !anyProductType(sale.ProductType, f.ProductTypes) {
+		return false
+	}
+	if len(f.Platforms) > 0 && !anyFold([]string{sale.Platform}, f.Platforms) {
+		return false
+	}
+	if f.DateRange != nil && !sale.Date.IsZero() &&
+		(sale.Date.Before(startOfDay(f.DateRange.Start)) || sale.Date.After(startOfDay(f.DateRange.End))) {
+		return false
+	}
+	if f.Expression != nil && !f.Expression.Match(app, sale) {
+		return false
+	}
+	return true
+}
+
+// filterReport returns a copy of report holding only the sales that match
+// filter, with every summary recalculated from them
+func (s *Service) filterReport(report *models.SalesReport, filter *FilterOptions) *models.SalesReport {
+	appSales := make([]models.AppSales, 0, len(report.Apps))
+	for i := range report.Apps {
+		app := &report.Apps[i]
+
+		var matching []models.Sale
+		for j := range app.Sales {
+			if filter.Match(app, &app.Sales[j]) {
+				matching = append(matching, app.Sales[j])
+			}
+		}
+		if len(matching) > 0 {
+			appSales = append(appSales, summarizeApp(app.AppID, app.AppName, app.SKU, matching))
+		}
+	}
+
+	filtered := s.newReport(report.Period, report.Date, report.VendorID, appSales)
+	filtered.Summary.Period = report.Summary.Period
+	return filtered
+}
+
+// Expression is a parsed --filter expression, such as
+//
+//	app in (Foo, Bar) and country != CN and product_type = IAP
+//
+// Comparisons are joined with and, or and not, and grouped with parentheses.
+// Operators are =, !=, ~ (contains), <, <=, >, >=, in and not in. Text is
+// compared case-insensitively; values with spaces must be quoted.
+type Expression struct {
+	source string
+	root   filterNode
+}
+
+// filterFields are the fields an expression can compare, and whether each is
+// a number, a date or text
+var filterFields = map[string]string{
+	"app":          "text", // Apple ID, SKU, name or parent SKU
+	"country":      "text",
+	"region":       "region", // Region group such as EU, or Apple finance region such as apple:LL
+	"currency":     "text", // Currency of proceeds
+	"product_type": "text", // Identifier such as IA1, or a category such as iap
+	"platform":     "text",
+	"device":       "text",
+	"promo_code":   "text",
+	"parent":       "text", // Parent identifier of an in-app purchase
+	"category":     "text",
+	"units":        "number",
+	"proceeds":     "number", // Units times proceeds per unit, negative for returns
+	"price":        "number", // Customer price per unit
+	"date":         "date",
+}
+
+// ParseFilter compiles a filter expression
+func ParseFilter(source string) (*Expression, error) {
+	tokens, err := lexFilter(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the expression as written
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Match reports whether a sale of app satisfies the expression
+func (e *Expression) Match(app *models.AppSales, sale *models.Sale) bool {
+	return e.root.match(app, sale)
+}
+
+type filterNode interface {
+	match(app *models.AppSales, sale *models.Sale) bool
+}
+
+type andNode struct{ left, right filterNode }
+type orNode struct{ left, right filterNode }
+type notNode struct{ node filterNode }
+
+func (n andNode) match(app *models.AppSales, sale *models.Sale) bool {
+	return n.left.match(app, sale) && n.right.match(app, sale)
+}
+
+func (n orNode) match(app *models.AppSales, sale *models.Sale) bool {
+	return n.left.match(app, sale) || n.right.match(app, sale)
+}
+
+func (n notNode) match(app *models.AppSales, sale *models.Sale) bool {
+	return !n.node.match(app, sale)
+}
+
+// compareNode compares one field with one or more values
+type compareNode struct {
+	field   string
+	op      string // =, !=, ~, <, <=, >, >=, in, not in
+	values  []string
+	numbers []float64 // Parsed values of number fields, Unix times of dates
+}
+
+func (n compareNode) match(app *models.AppSales, sale *models.Sale) bool {
+	switch n.field {
+	case "units":
+		return n.matchNumber(float64(sale.Units))
+	case "proceeds":
+		return n.matchNumber(sale.Proceeds())
+	case "price":
+		return n.matchNumber(sale.CustomerPrice.Amount)
+	case "date":
+		return n.matchNumber(float64(startOfDay(sale.Date).Unix()))
+	case "region":
+		inRegion := func(value string) bool {
+			return countries.InRegion(sale.Country, value)
+		}
+		return n.matchText(inRegion, inRegion)
+	}
+
+	var candidates []string
+	switch n.field {
+	case "app":
+		candidates = appKeys(app, sale)
+	case "country":
+		candidates = []string{sale.Country}
+	case "currency":
+		candidates = []string{sale.DeveloperProceeds.Currency}
+	case "product_type":
+		return n.matchText(func(value string) bool {
+			return productTypeIs(sale.ProductType, value)
+		}, func(value string) bool {
+			return strings.Contains(strings.ToLower(sale.ProductType), strings.ToLower(value))
+		})
+	case "platform":
+		candidates = []string{sale.Platform}
+	case "device":
+		candidates = []string{sale.Device}
+	case "promo_code":
+		candidates = []string{sale.PromoCode}
+	case "parent":
+		candidates = []string{sale.ParentID}
+	case "category":
+		candidates = []string{sale.Category}
+	}
+	return n.matchText(func(value string) bool {
+		return anyFold(candidates, []string{value})
+	}, func(value string) bool {
+		for _, candidate := range candidates {
+			if strings.Contains(strings.ToLower(candidate), strings.ToLower(value)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// matchText applies a text operator given how to test equality and
+// containment of one value
+func (n compareNode) matchText(equals, contains func(string) bool) bool {
+	test := equals
+	if n.op == "~" {
+		test = contains
+	}
+	found := false
+	for _, value := range n.values {
+		if test(value) {
+			found = true
+			break
+		}
+	}
+	if n.op == "!=" || n.op == "not in" {
+		return !found
+	}
+	return found
+}
+
+// matchNumber applies a numeric operator
+func (n compareNode) matchNumber(value float64) bool {
+	switch n.op {
+	case "in", "not in":
+		found := false
+		for _, operand := range n.numbers {
+			if value == operand {
+				found = true
+				break
+			}
+		}
+		return found == (n.op == "in")
+	case "=":
+		return value == n.numbers[0]
+	case "!=":
+		return value != n.numbers[0]
+	case "<":
+		return value < n.numbers[0]
+	case "<=":
+		return value <= n.numbers[0]
+	case ">":
+		return value > n.numbers[0]
+	case ">=":
+		return value >= n.numbers[0]
+	}
+	return false
+}
+
+// appKeys returns the values an app filter matches: Apple ID, SKU and name,
+// plus the parent SKU of an in-app purchase so apps match their purchases
+func appKeys(app *models.AppSales, sale *models.Sale) []string {
+	return []string{app.AppID, app.SKU, app.AppName, sale.ParentID}
+}
+
+// anyFold reports whether any candidate equals any value, ignoring case
+func anyFold(candidates, values []string) bool {
+	for _, candidate := range candidates {
+		for _, value := range values {
+			if candidate != "" && strings.EqualFold(candidate, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyRegion(country string, regions []string) bool {
+	for _, region := range regions {
+		if countries.InRegion(country, region) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyProductType(id string, values []string) bool {
+	for _, value := range values {
+		if productTypeIs(id, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// productTypeIs reports whether a product type identifier is value, or
+// belongs to the product category value names
+func productTypeIs(id, value string) bool {
+	productType := models.LookupProductType(id)
+	return strings.EqualFold(productType.ID, value) || strings.EqualFold(string(productType.Category), value)
+}
+
+// filterParser is a recursive descent parser over filter tokens
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+type filterToken struct {
+	text   string
+	quoted bool // A quoted value, never a keyword or operator
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// keyword consumes the next token if it's the given bare word
+func (p *filterParser) keyword(word string) bool {
+	if t, ok := p.peek(); ok && !t.quoted && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) next() (filterToken, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, fmt.Errorf("unexpected end of filter")
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.keyword("not") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	}
+	if p.keyword("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	field := strings.ToLower(t.text)
+	kind, ok := filterFields[field]
+	if t.quoted || !ok {
+		return nil, fmt.Errorf("unknown field %q", t.text)
+	}
+
+	node := compareNode{field: field}
+	switch {
+	case p.keyword("in"):
+		node.op = "in"
+	case p.keyword("not"):
+		if !p.keyword("in") {
+			return nil, fmt.Errorf("expected in after %s not", field)
+		}
+		node.op = "not in"
+	default:
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch t.text {
+		case "=", "==":
+			node.op = "="
+		case "!=", "~", "<", "<=", ">", ">=":
+			node.op = t.text
+		}
+		if t.quoted || node.op == "" {
+			return nil, fmt.Errorf("expected an operator after %s, got %q", field, t.text)
+		}
+	}
+
+	if node.op == "in" || node.op == "not in" {
+		if !p.keyword("(") {
+			return nil, fmt.Errorf("expected ( after %s %s", field, node.op)
+		}
+		for {
+			t, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, t.text)
+			if p.keyword(")") {
+				break
+			}
+			if !p.keyword(",") {
+				return nil, fmt.Errorf("expected , or ) in %s list", field)
+			}
+		}
+	} else {
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		node.values = []string{t.text}
+	}
+
+	switch kind {
+	case "number":
+		if node.op == "~" {
+			return nil, fmt.Errorf("%s is a number and can't use ~", field)
+		}
+		for _, value := range node.values {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s needs a number, got %q", field, value)
+			}
+			node.numbers = append(node.numbers, n)
+		}
+	case "region":
+		if node.op != "=" && node.op != "!=" && node.op != "in" && node.op != "not in" {
+			return nil, fmt.Errorf("%s can only use =, !=, in and not in", field)
+		}
+		for _, value := range node.values {
+			if err := countries.ValidateRegion(value); err != nil {
+				return nil, err
+			}
+		}
+	case "date":
+		if node.op == "~" {
+			return nil, fmt.Errorf("%s is a date and can't use ~", field)
+		}
+		for _, value := range node.values {
+			d, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return nil, fmt.Errorf("%s needs a YYYY-MM-DD date, got %q", field, value)
+			}
+			node.numbers = append(node.numbers, float64(d.Unix()))
+		}
+	default:
+		switch node.op {
+		case "<", "<=", ">", ">=":
+			return nil, fmt.Errorf("%s is text and can only use =, !=, ~, in and not in", field)
+		}
+	}
+	return node, nil
+}
+
+// lexFilter splits a filter into words, quoted strings, operators and
+// punctuation
+func lexFilter(source string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')' || c == ',' || c == '~':
+			tokens = append(tokens, filterToken{text: string(c)})
+			i++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(source) && source[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected ! at %d, use !=", i+1)
+			}
+			tokens = append(tokens, filterToken{text: op})
+			i += len(op)
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(source[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i+1)
+			}
+			tokens = append(tokens, filterToken{text: source[i+1 : i+1+end], quoted: true})
+			i += end + 2
+		default:
+			start := i
+			for i < len(source) && !strings.ContainsRune(" \t\n(),~=!<>\"'", rune(source[i])) {
+				i++
+			}
+			tokens = append(tokens, filterToken{text: source[start:i]})
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+	return tokens, nil
+}
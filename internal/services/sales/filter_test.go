@@ -0,0 +1,141 @@
+package sales
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		err    string
+	}{
+		{"empty", "", "empty filter"},
+		{"unknown field", "color = red", `unknown field "color"`},
+		{"quoted field", `"app" = Foo`, `unknown field "app"`},
+		{"missing operator", "app Foo", "expected an operator"},
+		{"missing value", "app =", "unexpected end of filter"},
+		{"bare bang", "app ! Foo", "use !="},
+		{"unterminated string", `app = "Foo`, "unterminated string"},
+		{"missing paren", "(app = Foo", "missing )"},
+		{"trailing token", "app = Foo Bar", `unexpected "Bar"`},
+		{"not without in", "app not Foo", "expected in after app not"},
+		{"in without list", "app in Foo", "expected ( after app in"},
+		{"bad list separator", "app in (Foo Bar)", "expected , or )"},
+		{"text ordering", "app > Foo", "is text"},
+		{"number contains", "units ~ 5", "can't use ~"},
+		{"bad number", "units > many", `units needs a number, got "many"`},
+		{"bad number in list", "units in (1, two)", `got "two"`},
+		{"date contains", "date ~ 2025", "can't use ~"},
+		{"bad date", "date >= 2025/01/01", "needs a YYYY-MM-DD date"},
+		{"region ordering", "region < EU", "can only use =, !=, in and not in"},
+		{"unknown region", "region = Atlantis", "Atlantis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilter(tt.filter)
+			if err == nil {
+				t.Fatalf("ParseFilter(%q) succeeded, want error containing %q", tt.filter, tt.err)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseFilter(%q) error = %q, want it to contain %q", tt.filter, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestExpressionMatch(t *testing.T) {
+	app := &models.AppSales{AppID: "111", AppName: "Foo Camera", SKU: "FOO"}
+	sale := &models.Sale{
+		Date:              time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+		Country:           "FI",
+		Units:             4,
+		CustomerPrice:     models.Money{Amount: 2.99, Currency: "EUR"},
+		DeveloperProceeds: models.Money{Amount: 2.09, Currency: "EUR"},
+		ProductType:       "IA1",
+		Platform:          "iOS",
+		Device:            "iPhone",
+		PromoCode:         "SPRING",
+		ParentID:          "FOO",
+		Category:          "Photography",
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		// Text fields
+		{"app = 111", true},
+		{"app = foo", true},
+		{`app = "foo camera"`, true},
+		{"app ~ camera", true},
+		{"app = Bar", false},
+		{"app != Bar", true},
+		{"country = fi", true},
+		{"country in (US, GB)", false},
+		{"country not in (US, GB)", true},
+		{"currency = EUR", true},
+		{"platform = ios", true},
+		{"device ~ phone", true},
+		{"promo_code = SPRING", true},
+		{"parent = FOO", true},
+		{"category = photography", true},
+
+		// Product types, by identifier or category
+		{"product_type = IA1", true},
+		{"product_type = iap", true},
+		{"product_type = download", false},
+		{"product_type ~ ia", true},
+
+		// Regions
+		{"region = EU", true},
+		{"region != EU", false},
+		{"region in (EU, NA)", true},
+
+		// Numbers
+		{"units = 4", true},
+		{"units > 4", false},
+		{"units >= 4", true},
+		{"units < 5", true},
+		{"units <= 3", false},
+		{"units in (1, 4)", true},
+		{"units not in (1, 4)", false},
+		{"proceeds > 8", true}, // 4 units at 2.09
+		{"proceeds < 2.09", false},
+		{"price < 2.99", false},
+
+		// Dates
+		{"date = 2025-03-14", true},
+		{"date >= 2025-03-15", false},
+		{"date < 2025-04-01", true},
+
+		// Logic and grouping
+		{"country = FI and units > 2", true},
+		{"country = US and units > 2", false},
+		{"country = US or units > 2", true},
+		{"not country = US", true},
+		{"NOT (country = FI or country = US)", false},
+		{"country = US and units > 2 or app = Foo", true},
+		{"country = US and (units > 2 or app = Foo)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := ParseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q): %v", tt.filter, err)
+			}
+			if got := expr.Match(app, sale); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.filter, got, tt.want)
+			}
+			if expr.String() != tt.filter {
+				t.Errorf("String() = %q, want %q", expr.String(), tt.filter)
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package sales
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// seasonLength is the number of months in a seasonal cycle
+const seasonLength = 12
+
+// minSeasonalHistory is the history needed to fit monthly seasonality: two
+// full years, so every month is seen at least twice
+const minSeasonalHistory = 2 * seasonLength
+
+// minForecastHistory is the history needed to fit a trend and still estimate
+// how far actuals scatter around it
+const minForecastHistory = 3
+
+// Forecast models
+const (
+	ModelLinear   = "linear trend"
+	ModelSeasonal = "linear trend + monthly seasonality"
+)
+
+// ForecastOptions configures a forecast of monthly sales
+type ForecastOptions struct {
+	Horizon    int     // Months to forecast
+	Backtest   int     // Known months to hold out and forecast, 0 to skip
+	Confidence float64 // Prediction interval coverage, e.g. 0.95
+}
+
+// ForecastReport forecasts total units, total proceeds per currency and
+// each app's units
+type ForecastReport struct {
+	History    []time.Time // Months the models were fitted on
+	Periods    []time.Time // Forecast months
+	Confidence float64
+	Units      *ForecastSeries
+	Proceeds   map[string]*ForecastSeries // Currency -> Series
+	Apps       []*AppForecast             // Sorted by forecast units
+}
+
+// AppForecast forecasts one app's units
+type AppForecast struct {
+	AppID   string
+	AppName string
+	Units   *ForecastSeries
+}
+
+// ForecastSeries is one forecast series
+type ForecastSeries struct {
+	Model    string
+	History  []float64
+	Points   []ForecastPoint
+	Backtest *Backtest `json:",omitempty"`
+}
+
+// ForecastPoint is a point forecast with its prediction interval
+type ForecastPoint struct {
+	Period time.Time
+	Value  float64
+	Lower  float64
+	Upper  float64
+}
+
+// Total returns the sum of the point forecasts
+func (s *ForecastSeries) Total() float64 {
+	var total float64
+	for _, point := range s.Points {
+		total += point.Value
+	}
+	return total
+}
+
+// Backtest compares forecasts of held-out months with what was sold
+type Backtest struct {
+	Model     string // The forecast's model, refitted without the held-out months
+	Periods   []time.Time
+	Actual    []float64
+	Predicted []ForecastPoint
+	MAE       float64 // Mean absolute error
+	MAPE      float64 // Mean absolute percentage error, over months that sold anything
+	Covered   int     // Months whose actuals fell within the prediction interval
+}
+
+// Forecast fits a model to each series of a monthly trend report and
+// forecasts the months after it. Models are linear trends, with monthly
+// seasonality once there are two years of history.
+func Forecast(trends *TrendReport, options ForecastOptions) (*ForecastReport, error) {
+	if trends == nil || len(trends.Periods) == 0 {
+		return nil, fmt.Errorf("no sales history to forecast from")
+	}
+	if options.Horizon < 1 {
+		return nil, fmt.Errorf("forecast horizon must be at least 1 month")
+	}
+	if options.Backtest < 0 {
+		return nil, fmt.Errorf("backtest months can't be negative")
+	}
+	if options.Confidence <= 0 || options.Confidence >= 1 {
+		options.Confidence = 0.95
+	}
+
+	months := len(trends.Periods)
+	if months-options.Backtest < minForecastHistory {
+		return nil, fmt.Errorf("need at least %d months of history besides the %d backtest months, got %d",
+			minForecastHistory, options.Backtest, months)
+	}
+
+	last := trends.Periods[months-1]
+	last = time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)
+	first := last.AddDate(0, 1-months, 0)
+
+	result := &ForecastReport{
+		History:    make([]time.Time, months),
+		Periods:    make([]time.Time, options.Horizon),
+		Confidence: options.Confidence,
+		Proceeds:   make(map[string]*ForecastSeries),
+	}
+	for i := range result.History {
+		result.History[i] = first.AddDate(0, i, 0)
+	}
+	for i := range result.Periods {
+		result.Periods[i] = last.AddDate(0, i+1, 0)
+	}
+
+	var err error
+	result.Units, err = forecastSeries(intsToFloats(trends.TotalUnits), first, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to forecast units: %w", err)
+	}
+
+	for currency, amounts := range trends.TotalProceeds {
+		series, err := forecastSeries(amounts, first, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to forecast %s proceeds: %w", currency, err)
+		}
+		result.Proceeds[currency] = series
+	}
+
+	for _, app := range trends.AppTrends {
+		series, err := forecastSeries(intsToFloats(app.Units), first, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to forecast %s: %w", app.AppName, err)
+		}
+		result.Apps = append(result.Apps, &AppForecast{
+			AppID:   app.AppID,
+			AppName: app.AppName,
+			Units:   series,
+		})
+	}
+	sort.Slice(result.Apps, func(i, j int) bool {
+		if ti, tj := result.Apps[i].Units.Total(), result.Apps[j].Units.Total(); ti != tj {
+			return ti > tj
+		}
+		return result.Apps[i].AppName < result.Apps[j].AppName
+	})
+
+	return result, nil
+}
+
+// forecastSeries forecasts a monthly series starting in month first, and
+// backtests the same kind of model on its last months
+func forecastSeries(values []float64, first time.Time, options ForecastOptions) (*ForecastSeries, error) {
+	model, err := fitTrend(values, first, len(values) >= minSeasonalHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &ForecastSeries{
+		Model:   model.name(),
+		History: values,
+		Points:  model.forecast(options.Horizon, options.Confidence),
+	}
+
+	if options.Backtest > 0 {
+		known := len(values) - options.Backtest
+		// Refit the forecast's model, so the backtest scores the model that
+		// made the forecast even when the shorter history alone wouldn't be
+		// fitted with seasonality
+		heldOut, err := fitTrend(values[:known], first, model.seasonal)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: %w", err)
+		}
+		series.Backtest = backtest(values[known:], heldOut.forecast(options.Backtest, options.Confidence))
+		series.Backtest.Model = heldOut.name()
+	}
+
+	return series, nil
+}
+
+// backtest scores forecasts against actuals
+func backtest(actual []float64, predicted []ForecastPoint) *Backtest {
+	result := &Backtest{
+		Periods:   make([]time.Time, len(actual)),
+		Actual:    actual,
+		Predicted: predicted,
+	}
+
+	var percentages int
+	for i, value := range actual {
+		point := predicted[i]
+		result.Periods[i] = point.Period
+		result.MAE += math.Abs(value - point.Value)
+		if value != 0 {
+			result.MAPE += math.Abs((value-point.Value)/value) * 100
+			percentages++
+		}
+		if value >= point.Lower && value <= point.Upper {
+			result.Covered++
+		}
+	}
+
+	result.MAE /= float64(len(actual))
+	if percentages > 0 {
+		result.MAPE /= float64(percentages)
+	}
+	return result
+}
+
+// trendModel is a least squares fit of a linear trend, optionally with a
+// level shift for each calendar month
+type trendModel struct {
+	first    time.Time   // Month of the first observation
+	n        int         // Observations fitted
+	seasonal bool        // Whether monthly dummies were fitted
+	beta     []float64   // Coefficients
+	inverse  [][]float64 // (XᵀX)⁻¹, for prediction variances
+	sigma    float64     // Residual standard error
+}
+
+// fitTrend fits a linear trend to values, with monthly seasonality when
+// seasonal is set
+func fitTrend(values []float64, first time.Time, seasonal bool) (*trendModel, error) {
+	if len(values) < minForecastHistory {
+		return nil, fmt.Errorf("need at least %d months of history, got %d", minForecastHistory, len(values))
+	}
+
+	model := &trendModel{
+		first:    first,
+		n:        len(values),
+		seasonal: seasonal,
+	}
+
+	// Leave at least one degree of freedom to estimate the scatter
+	p := model.parameters()
+	if len(values) <= p {
+		return nil, fmt.Errorf("need at least %d months of history to fit a %s, got %d", p+1, model.name(), len(values))
+	}
+	xtx := make([][]float64, p)
+	for i := range xtx {
+		xtx[i] = make([]float64, p)
+	}
+	xty := make([]float64, p)
+
+	for t, y := range values {
+		x := model.regressors(t)
+		for i := range x {
+			xty[i] += x[i] * y
+			for j := range x {
+				xtx[i][j] += x[i] * x[j]
+			}
+		}
+	}
+
+	inverse, err := invert(xtx)
+	if err != nil {
+		return nil, err
+	}
+	model.inverse = inverse
+
+	model.beta = make([]float64, p)
+	for i := range inverse {
+		for j := range xty {
+			model.beta[i] += inverse[i][j] * xty[j]
+		}
+	}
+
+	var sse float64
+	for t, y := range values {
+		residual := y - dot(model.beta, model.regressors(t))
+		sse += residual * residual
+	}
+	if dof := model.n - p; dof > 0 {
+		model.sigma = math.Sqrt(sse / float64(dof))
+	}
+
+	return model, nil
+}
+
+func (m *trendModel) name() string {
+	if m.seasonal {
+		return ModelSeasonal
+	}
+	return ModelLinear
+}
+
+func (m *trendModel) parameters() int {
+	if m.seasonal {
+		return 2 + seasonLength - 1
+	}
+	return 2
+}
+
+// regressors returns the model inputs for the month t months after the first:
+// a constant, t, and with seasonality a dummy for each calendar month but the
+// first month's
+func (m *trendModel) regressors(t int) []float64 {
+	x := make([]float64, m.parameters())
+	x[0] = 1
+	x[1] = float64(t)
+	if m.seasonal {
+		if season := t % seasonLength; season > 0 {
+			x[1+season] = 1
+		}
+	}
+	return x
+}
+
+// forecast predicts the next months with prediction intervals. Sales can't
+// be negative, so neither can forecasts or lower bounds.
+func (m *trendModel) forecast(horizon int, confidence float64) []ForecastPoint {
+	dof := m.n - m.parameters()
+	quantile := studentQuantile((1+confidence)/2, dof)
+
+	points := make([]ForecastPoint, horizon)
+	for h := range points {
+		t := m.n + h
+		x := m.regressors(t)
+		value := dot(m.beta, x)
+
+		// Variance of a new observation: the residual variance plus the
+		// uncertainty of the fitted coefficients
+		var leverage float64
+		for i := range x {
+			for j := range x {
+				leverage += x[i] * m.inverse[i][j] * x[j]
+			}
+		}
+		margin := quantile * m.sigma * math.Sqrt(1+leverage)
+
+		points[h] = ForecastPoint{
+			Period: m.first.AddDate(0, t, 0),
+			Value:  math.Max(value, 0),
+			Lower:  math.Max(value-margin, 0),
+			Upper:  math.Max(value+margin, 0),
+		}
+	}
+	return points
+}
+
+// invert inverts a square matrix by Gauss-Jordan elimination
+func invert(matrix [][]float64) ([][]float64, error) {
+	n := len(matrix)
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, 2*n)
+		copy(a[i], matrix[i])
+		a[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("history is too short to fit the model")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+
+		scale := a[col][col]
+		for j := range a[col] {
+			a[col][j] /= scale
+		}
+		for row := 0; row < n; row++ {
+			if row == col || a[row][col] == 0 {
+				continue
+			}
+			factor := a[row][col]
+			for j := range a[row] {
+				a[row][j] -= factor * a[col][j]
+			}
+		}
+	}
+
+	inverse := make([][]float64, n)
+	for i := range inverse {
+		inverse[i] = a[i][n:]
+	}
+	return inverse, nil
+}
+
+// studentQuantile approximates the p quantile of Student's t distribution
+// with dof degrees of freedom. It's exact for one and two degrees of freedom
+// and uses the Cornish-Fisher expansion around the normal quantile above.
+func studentQuantile(p float64, dof int) float64 {
+	switch {
+	case dof <= 0:
+		return 0
+	case dof == 1:
+		return math.Tan(math.Pi * (p - 0.5))
+	case dof == 2:
+		return (2*p - 1) / math.Sqrt(2*p*(1-p))
+	}
+
+	z := normalQuantile(p)
+	v := float64(dof)
+	z3, z5, z7 := math.Pow(z, 3), math.Pow(z, 5), math.Pow(z, 7)
+	return z +
+		(z3+z)/(4*v) +
+		(5*z5+16*z3+3*z)/(96*v*v) +
+		(3*z7+19*z5+17*z3-15*z)/(384*v*v*v)
+}
+
+// normalQuantile returns the p quantile of the standard normal distribution
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func intsToFloats(values []int) []float64 {
+	floats := make([]float64, len(values))
+	for i, value := range values {
+		floats[i] = float64(value)
+	}
+	return floats
+}
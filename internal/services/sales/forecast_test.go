@@ -0,0 +1,167 @@
+package sales
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// seasonalUnits returns months of units with a linear trend and a fixed
+// level for each calendar month, with no noise
+func seasonalUnits(months int) []int {
+	season := []int{0, 5, -3, 8, 0, 10, -6, 2, 4, -2, 7, 30}
+	units := make([]int, months)
+	for t := range units {
+		units[t] = 100 + 2*t + season[t%seasonLength]
+	}
+	return units
+}
+
+func monthlyTrends(units []int) *TrendReport {
+	first := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	trends := &TrendReport{TotalUnits: units}
+	for i := range units {
+		trends.Periods = append(trends.Periods, first.AddDate(0, i, 0))
+	}
+	return trends
+}
+
+func TestForecastModels(t *testing.T) {
+	tests := []struct {
+		name   string
+		units  []int
+		model  string
+		expect []float64
+	}{
+		{
+			name:   "linear",
+			units:  []int{10, 12, 14, 16, 18, 20},
+			model:  ModelLinear,
+			expect: []float64{22, 24, 26},
+		},
+		{
+			name:   "seasonal",
+			units:  seasonalUnits(36),
+			model:  ModelSeasonal,
+			expect: []float64{172, 179, 173}, // January to March of the fourth year
+		},
+		{
+			name:   "never negative",
+			units:  []int{20, 15, 10, 5},
+			model:  ModelLinear,
+			expect: []float64{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast, err := Forecast(monthlyTrends(tt.units), ForecastOptions{Horizon: len(tt.expect)})
+			if err != nil {
+				t.Fatalf("Forecast: %v", err)
+			}
+			if forecast.Units.Model != tt.model {
+				t.Errorf("model = %q, want %q", forecast.Units.Model, tt.model)
+			}
+			for i, point := range forecast.Units.Points {
+				if math.Abs(point.Value-tt.expect[i]) > 1e-6 {
+					t.Errorf("month %d forecast = %.4f, want %.4f", i+1, point.Value, tt.expect[i])
+				}
+				if point.Lower > point.Value || point.Upper < point.Value || point.Lower < 0 {
+					t.Errorf("month %d interval [%.4f, %.4f] doesn't hold %.4f", i+1, point.Lower, point.Upper, point.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestForecastIntervalsWiden(t *testing.T) {
+	units := make([]int, 12)
+	for i := range units {
+		units[i] = 50 + 3*i + 4*(i%2) // Trend with alternating noise
+	}
+
+	forecast, err := Forecast(monthlyTrends(units), ForecastOptions{Horizon: 6, Confidence: 0.9})
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	width := 0.0
+	for i, point := range forecast.Units.Points {
+		next := point.Upper - point.Lower
+		if next <= width {
+			t.Errorf("month %d interval width %.4f didn't grow from %.4f", i+1, next, width)
+		}
+		width = next
+	}
+}
+
+func TestForecastBacktestKeepsModel(t *testing.T) {
+	// 24 months is just enough for seasonality, and the 21 months left after
+	// holding out 3 would be fitted without it on their own
+	forecast, err := Forecast(monthlyTrends(seasonalUnits(24)), ForecastOptions{Horizon: 1, Backtest: 3})
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+
+	backtest := forecast.Units.Backtest
+	if backtest == nil {
+		t.Fatal("no backtest")
+	}
+	if backtest.Model != ModelSeasonal {
+		t.Errorf("backtest model = %q, want %q", backtest.Model, ModelSeasonal)
+	}
+	if backtest.MAE > 1e-6 {
+		t.Errorf("backtest MAE = %.4f, want 0 for a noise-free seasonal series", backtest.MAE)
+	}
+	for i, period := range backtest.Periods {
+		if want := forecast.History[21+i]; !period.Equal(want) {
+			t.Errorf("backtest period %d = %s, want %s", i, period, want)
+		}
+	}
+}
+
+func TestForecastErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		units   []int
+		options ForecastOptions
+		err     string
+	}{
+		{"no history", nil, ForecastOptions{Horizon: 1}, "no sales history"},
+		{"no horizon", []int{1, 2, 3}, ForecastOptions{}, "horizon must be at least 1"},
+		{"negative backtest", []int{1, 2, 3}, ForecastOptions{Horizon: 1, Backtest: -1}, "can't be negative"},
+		{"short history", []int{1, 2}, ForecastOptions{Horizon: 1}, "need at least 3 months"},
+		{"backtest leaves too little", []int{1, 2, 3, 4}, ForecastOptions{Horizon: 1, Backtest: 2}, "besides the 2 backtest months"},
+		{"seasonal backtest leaves too little", seasonalUnits(24), ForecastOptions{Horizon: 1, Backtest: 12}, "need at least 14 months"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Forecast(monthlyTrends(tt.units), tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Forecast error = %v, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestStudentQuantile(t *testing.T) {
+	tests := []struct {
+		p    float64
+		dof  int
+		want float64
+	}{
+		{0.975, 1, 12.7062},
+		{0.975, 2, 4.3027},
+		{0.975, 5, 2.5706},
+		{0.975, 10, 2.2281},
+		{0.95, 30, 1.6973},
+		{0.975, 1000, 1.9623},
+	}
+
+	for _, tt := range tests {
+		if got := studentQuantile(tt.p, tt.dof); math.Abs(got-tt.want) > 0.01*tt.want {
+			t.Errorf("studentQuantile(%v, %d) = %.4f, want %.4f", tt.p, tt.dof, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package sales
+
+import (
+	"sort"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// NestApps groups in-app purchases and subscriptions under the app they
+// belong to. Apple reports each of them as a product of its own whose Parent
+// Identifier is the parent app's SKU. Children whose parent sold nothing in
+// the report get a placeholder parent named after its SKU.
+//
+// Every returned app has RollUp set. Apps are sorted by rolled-up units and
+// children by units. The apps passed in are left unchanged.
+func NestApps(apps []models.AppSales) []models.AppSales {
+	var parents []models.AppSales
+	bySKU := make(map[string]int)
+	var children []models.AppSales
+
+	for _, app := range apps {
+		app.Children = nil
+		if parentSKU(app) != "" {
+			children = append(children, app)
+			continue
+		}
+		if app.SKU != "" {
+			bySKU[app.SKU] = len(parents)
+		}
+		parents = append(parents, app)
+	}
+
+	for _, child := range children {
+		sku := parentSKU(child)
+		i, ok := bySKU[sku]
+		if !ok {
+			i = len(parents)
+			bySKU[sku] = i
+			parents = append(parents, models.AppSales{AppName: sku, SKU: sku})
+		}
+		parents[i].Children = append(parents[i].Children, child)
+	}
+
+	for i := range parents {
+		parent := &parents[i]
+		sort.SliceStable(parent.Children, func(a, b int) bool {
+			return parent.Children[a].Summary.TotalUnits > parent.Children[b].Summary.TotalUnits
+		})
+
+		sales := parent.Sales
+		if len(parent.Children) > 0 {
+			sales = append([]models.Sale(nil), parent.Sales...)
+			for _, child := range parent.Children {
+				sales = append(sales, child.Sales...)
+			}
+		}
+		rollUp := summarizeApp(parent.AppID, parent.AppName, parent.SKU, sales).Summary
+		parent.RollUp = &rollUp
+	}
+
+	sort.SliceStable(parents, func(i, j int) bool {
+		return parents[i].RollUp.TotalUnits > parents[j].RollUp.TotalUnits
+	})
+
+	return parents
+}
+
+// parentSKU returns the SKU of the app a product belongs to, or "" for apps
+func parentSKU(app models.AppSales) string {
+	for _, sale := range app.Sales {
+		if sale.ParentID != "" {
+			return sale.ParentID
+		}
+	}
+	return ""
+}
@@ -9,7 +9,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/pkg/models"
 )
 
 // Parser handles CSV parsing for sales reports
@@ -33,7 +33,10 @@ func (p *Parser) ParseCSV(data []byte) ([]models.SalesRecord, error) {
 	reader := csv.NewReader(bytes.NewReader(data))
 	reader.Comma = '\t' // Apple uses tab-separated values
 	reader.LazyQuotes = true
-	reader.TrimLeadingSpace = true
+	// Apple leaves optional columns empty; TrimLeadingSpace would swallow
+	// consecutive tabs and shift every later field. Values are trimmed in
+	// parseRecord instead.
+	reader.TrimLeadingSpace = false
 
 	// Read header
 	header, err := reader.Read()
@@ -0,0 +1,286 @@
+package sales
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PeriodKind is a kind of span sales can be aggregated over
+type PeriodKind string
+
+const (
+	PeriodMonth      PeriodKind = "month"
+	PeriodQuarter    PeriodKind = "quarter"
+	PeriodYear       PeriodKind = "year"
+	PeriodAppleMonth PeriodKind = "apple-month" // Apple fiscal month, as used for payments
+)
+
+// Period is a named run of whole days
+type Period struct {
+	Kind  PeriodKind
+	Start time.Time // First day
+	End   time.Time // Last day, included
+	Label string    // e.g. "2025-03", "2025-Q2", "FY2026-Q1", "apple:2025-10"
+}
+
+// DateRange returns the days the period covers
+func (p Period) DateRange() DateRange {
+	return DateRange{Start: p.Start, End: p.End}
+}
+
+// Calendar splits time into months, quarters and years
+type Calendar struct {
+	// FiscalYearStart is the first month of the year. Zero or January means
+	// calendar years. Fiscal years are named after the calendar year they end
+	// in, so with an April start FY2026 runs from April 2025 to March 2026.
+	FiscalYearStart time.Month
+}
+
+// IsFiscal reports whether years start in a month other than January
+func (c Calendar) IsFiscal() bool {
+	return c.FiscalYearStart > time.January
+}
+
+func (c Calendar) start() time.Month {
+	if c.FiscalYearStart < time.January || c.FiscalYearStart > time.December {
+		return time.January
+	}
+	return c.FiscalYearStart
+}
+
+// Period returns the period of the given kind containing t
+func (c Calendar) Period(kind PeriodKind, t time.Time) Period {
+	day := startOfDay(t)
+
+	switch kind {
+	case PeriodAppleMonth:
+		return AppleFiscalMonth(day)
+
+	case PeriodQuarter, PeriodYear:
+		fiscalYear, offset := c.fiscalYear(day)
+		months := 12
+		if kind == PeriodQuarter {
+			months = 3
+			offset -= offset % 3
+		} else {
+			offset = 0
+		}
+		start := time.Date(fiscalYear-1, c.start()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		if !c.IsFiscal() {
+			start = start.AddDate(1, 0, 0)
+		}
+		period := Period{Kind: kind, Start: start, End: start.AddDate(0, months, -1)}
+		period.Label = c.label(fiscalYear, offset/3+1, kind)
+		return period
+
+	default:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Kind: PeriodMonth, Start: start, End: start.AddDate(0, 1, -1), Label: start.Format("2006-01")}
+	}
+}
+
+// Previous returns the period of the same kind just before p
+func (c Calendar) Previous(p Period) Period {
+	return c.Period(p.Kind, p.Start.AddDate(0, 0, -1))
+}
+
+// Periods returns n consecutive periods of the given kind, oldest first,
+// ending with the one containing end
+func (c Calendar) Periods(kind PeriodKind, end time.Time, n int) []Period {
+	if n <= 0 {
+		return nil
+	}
+	periods := make([]Period, n)
+	period := c.Period(kind, end)
+	for i := n - 1; i >= 0; i-- {
+		periods[i] = period
+		period = c.Previous(period)
+	}
+	return periods
+}
+
+// LatestComplete returns the most recent period of the given kind whose
+// sales are all available as of now. Months, quarters and years end with the
+// latest published monthly report; Apple fiscal months end by yesterday.
+func (c Calendar) LatestComplete(kind PeriodKind, now time.Time) Period {
+	available := startOfDay(now).AddDate(0, 0, -1)
+	if kind != PeriodAppleMonth {
+		available = c.Period(PeriodMonth, LatestAvailableMonth(now)).End
+	}
+
+	period := c.Period(kind, available)
+	if period.End.After(available) {
+		period = c.Previous(period)
+	}
+	return period
+}
+
+// fiscalYear returns the fiscal year containing day and how many months into
+// it day falls
+func (c Calendar) fiscalYear(day time.Time) (int, int) {
+	offset := (int(day.Month()) - int(c.start()) + 12) % 12
+	if !c.IsFiscal() || day.Month() < c.start() {
+		return day.Year(), offset
+	}
+	return day.Year() + 1, offset
+}
+
+func (c Calendar) label(fiscalYear, quarter int, kind PeriodKind) string {
+	prefix := ""
+	if c.IsFiscal() {
+		prefix = "FY"
+	}
+	if kind == PeriodYear {
+		return fmt.Sprintf("%s%d", prefix, fiscalYear)
+	}
+	return fmt.Sprintf("%s%d-Q%d", prefix, fiscalYear, quarter)
+}
+
+var periodPattern = regexp.MustCompile(`^(?i)(apple:|FY)?(\d{4})(?:-(Q[1-4]|\d{2}))?$`)
+
+// ParsePeriod parses a period name:
+//
+//	2025-03        calendar month
+//	2025-Q2        calendar quarter
+//	2025           calendar year
+//	FY2026-Q1      fiscal quarter
+//	FY2026         fiscal year
+//	apple:2025-10  Apple fiscal month
+//
+// Fiscal periods follow the calendar's fiscal year start.
+func (c Calendar) ParsePeriod(value string) (Period, error) {
+	match := periodPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return Period{}, fmt.Errorf("invalid period %q: use YYYY-MM, YYYY-QN, YYYY, FYYYYY-QN, FYYYYY or apple:YYYY-MM", value)
+	}
+	prefix := strings.ToLower(match[1])
+	year, _ := strconv.Atoi(match[2])
+	part := strings.ToUpper(match[3])
+
+	calendar := c
+	if prefix != "fy" {
+		calendar = Calendar{}
+	} else if !c.IsFiscal() {
+		return Period{}, fmt.Errorf("invalid period %q: no fiscal year start configured", value)
+	}
+
+	switch {
+	case prefix == "apple:":
+		month, err := strconv.Atoi(part)
+		if err != nil || month < 1 || month > 12 {
+			return Period{}, fmt.Errorf("invalid period %q: use apple:YYYY-MM", value)
+		}
+		return appleFiscalMonthNamed(year, time.Month(month)), nil
+
+	case strings.HasPrefix(part, "Q"):
+		quarter := int(part[1] - '0')
+		// The first month of the quarter, in the year before the fiscal year
+		// ends when years don't start in January
+		first := time.Date(year, calendar.start()+time.Month((quarter-1)*3), 1, 0, 0, 0, 0, time.UTC)
+		if calendar.IsFiscal() {
+			first = first.AddDate(-1, 0, 0)
+		}
+		return calendar.Period(PeriodQuarter, first), nil
+
+	case part != "":
+		if prefix != "" {
+			return Period{}, fmt.Errorf("invalid period %q: fiscal periods are FYYYYY or FYYYYY-QN", value)
+		}
+		month, _ := strconv.Atoi(part)
+		if month < 1 || month > 12 {
+			return Period{}, fmt.Errorf("invalid period %q: month must be 01 to 12", value)
+		}
+		return calendar.Period(PeriodMonth, time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)), nil
+
+	default:
+		// The last month always belongs to the year it names
+		last := time.Date(year, calendar.start(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+		if !calendar.IsFiscal() {
+			last = time.Date(year, time.December, 1, 0, 0, 0, 0, time.UTC)
+		}
+		return calendar.Period(PeriodYear, last), nil
+	}
+}
+
+// ParseMonth parses a month name or number, e.g. "april", "Apr" or "4"
+func ParseMonth(value string) (time.Month, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 1 || n > 12 {
+			return 0, fmt.Errorf("invalid month %q: use 1 to 12", value)
+		}
+		return time.Month(n), nil
+	}
+	for month := time.January; month <= time.December; month++ {
+		name := month.String()
+		if strings.EqualFold(value, name) || strings.EqualFold(value, name[:3]) {
+			return month, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid month %q", value)
+}
+
+// Apple's fiscal year ends on the last Saturday of September. Each quarter has
+// fiscal months of 5, 4 and 4 weeks, named after the calendar month they
+// mostly cover; a 53-week year adds its extra week to December.
+
+// AppleFiscalMonth returns the Apple fiscal month containing t
+func AppleFiscalMonth(t time.Time) Period {
+	day := startOfDay(t)
+	fiscalYear := day.Year()
+	if day.After(appleFiscalYearEnd(fiscalYear)) {
+		fiscalYear++
+	}
+	for _, month := range appleFiscalMonths(fiscalYear) {
+		if !day.After(month.End) {
+			return month
+		}
+	}
+	// Unreachable: the fiscal months cover the whole fiscal year
+	return Period{}
+}
+
+// appleFiscalMonthNamed returns the Apple fiscal month named after a
+// calendar month
+func appleFiscalMonthNamed(year int, month time.Month) Period {
+	fiscalYear := year
+	if month >= time.October {
+		fiscalYear++
+	}
+	return appleFiscalMonths(fiscalYear)[(int(month)-int(time.October)+12)%12]
+}
+
+// appleFiscalMonths returns the twelve fiscal months of an Apple fiscal year,
+// October first
+func appleFiscalMonths(fiscalYear int) []Period {
+	start := appleFiscalYearEnd(fiscalYear-1).AddDate(0, 0, 1)
+	weeks := (int(appleFiscalYearEnd(fiscalYear).Sub(start).Hours()/24) + 1) / 7
+
+	months := make([]Period, 12)
+	for i := range months {
+		length := 4
+		if i%3 == 0 {
+			length = 5
+		}
+		if i == 2 && weeks == 53 {
+			length++
+		}
+		end := start.AddDate(0, 0, 7*length-1)
+		name := time.Date(fiscalYear-1, time.October+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		months[i] = Period{Kind: PeriodAppleMonth, Start: start, End: end, Label: "apple:" + name.Format("2006-01")}
+		start = end.AddDate(0, 0, 1)
+	}
+	return months
+}
+
+// appleFiscalYearEnd returns the last Saturday of September
+func appleFiscalYearEnd(year int) time.Time {
+	day := time.Date(year, time.September, 30, 0, 0, 0, 0, time.UTC)
+	for day.Weekday() != time.Saturday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
@@ -0,0 +1,243 @@
+package sales
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDay parses a YYYY-MM-DD date
+func testDay(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCalendarPeriod(t *testing.T) {
+	april := Calendar{FiscalYearStart: time.April}
+	october := Calendar{FiscalYearStart: time.October}
+
+	tests := []struct {
+		calendar Calendar
+		kind     PeriodKind
+		date     string
+		label    string
+		start    string
+		end      string
+	}{
+		{Calendar{}, PeriodMonth, "2024-02-29", "2024-02", "2024-02-01", "2024-02-29"},
+		{Calendar{}, PeriodQuarter, "2025-05-15", "2025-Q2", "2025-04-01", "2025-06-30"},
+		{Calendar{}, PeriodQuarter, "2025-12-31", "2025-Q4", "2025-10-01", "2025-12-31"},
+		{Calendar{}, PeriodYear, "2025-07-04", "2025", "2025-01-01", "2025-12-31"},
+		{Calendar{FiscalYearStart: time.January}, PeriodQuarter, "2025-01-01", "2025-Q1", "2025-01-01", "2025-03-31"},
+
+		// Fiscal years are named after the year they end in
+		{april, PeriodQuarter, "2025-04-01", "FY2026-Q1", "2025-04-01", "2025-06-30"},
+		{april, PeriodQuarter, "2025-12-31", "FY2026-Q3", "2025-10-01", "2025-12-31"},
+		{april, PeriodQuarter, "2026-03-31", "FY2026-Q4", "2026-01-01", "2026-03-31"},
+		{april, PeriodYear, "2025-03-31", "FY2025", "2024-04-01", "2025-03-31"},
+		{april, PeriodYear, "2025-04-01", "FY2026", "2025-04-01", "2026-03-31"},
+		{october, PeriodQuarter, "2025-10-01", "FY2026-Q1", "2025-10-01", "2025-12-31"},
+		{october, PeriodYear, "2025-09-30", "FY2025", "2024-10-01", "2025-09-30"},
+
+		// Months ignore the fiscal year
+		{april, PeriodMonth, "2025-04-30", "2025-04", "2025-04-01", "2025-04-30"},
+
+		// Apple fiscal months start on Sundays and end on Saturdays
+		{Calendar{}, PeriodAppleMonth, "2024-09-29", "apple:2024-10", "2024-09-29", "2024-11-02"},
+		{Calendar{}, PeriodAppleMonth, "2024-12-28", "apple:2024-12", "2024-12-01", "2024-12-28"},
+		{Calendar{}, PeriodAppleMonth, "2024-12-29", "apple:2025-01", "2024-12-29", "2025-02-01"},
+		{Calendar{}, PeriodAppleMonth, "2025-09-27", "apple:2025-09", "2025-08-31", "2025-09-27"},
+		// FY2023 had 53 weeks, the extra one going to December
+		{Calendar{}, PeriodAppleMonth, "2022-12-31", "apple:2022-12", "2022-11-27", "2022-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind)+" "+tt.date+" "+tt.label, func(t *testing.T) {
+			period := tt.calendar.Period(tt.kind, testDay(tt.date).Add(15*time.Hour))
+			if period.Kind != tt.kind || period.Label != tt.label ||
+				!period.Start.Equal(testDay(tt.start)) || !period.End.Equal(testDay(tt.end)) {
+				t.Errorf("Period = %s %s %s to %s, want %s %s %s to %s",
+					period.Kind, period.Label, period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"),
+					tt.kind, tt.label, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestCalendarPeriods(t *testing.T) {
+	tests := []struct {
+		calendar Calendar
+		kind     PeriodKind
+		end      string
+		n        int
+		labels   []string
+	}{
+		{Calendar{}, PeriodMonth, "2025-03-31", 4, []string{"2024-12", "2025-01", "2025-02", "2025-03"}},
+		{Calendar{}, PeriodQuarter, "2025-02-01", 3, []string{"2024-Q3", "2024-Q4", "2025-Q1"}},
+		{Calendar{FiscalYearStart: time.April}, PeriodQuarter, "2025-04-15", 2, []string{"FY2025-Q4", "FY2026-Q1"}},
+		{Calendar{FiscalYearStart: time.July}, PeriodYear, "2025-08-01", 2, []string{"FY2025", "FY2026"}},
+		{Calendar{}, PeriodAppleMonth, "2024-10-15", 2, []string{"apple:2024-09", "apple:2024-10"}},
+		{Calendar{}, PeriodYear, "2025-01-01", 0, nil},
+	}
+
+	for _, tt := range tests {
+		periods := tt.calendar.Periods(tt.kind, testDay(tt.end), tt.n)
+		var labels []string
+		for i, period := range periods {
+			labels = append(labels, period.Label)
+			if i > 0 && !period.Start.Equal(periods[i-1].End.AddDate(0, 0, 1)) {
+				t.Errorf("%s doesn't start the day after %s ends", period.Label, periods[i-1].Label)
+			}
+		}
+		if !reflect.DeepEqual(labels, tt.labels) {
+			t.Errorf("Periods(%s, %s, %d) = %v, want %v", tt.kind, tt.end, tt.n, labels, tt.labels)
+		}
+	}
+}
+
+func TestAppleFiscalYears(t *testing.T) {
+	for year := 2015; year <= 2035; year++ {
+		months := appleFiscalMonths(year)
+		if want := appleFiscalYearEnd(year-1).AddDate(0, 0, 1); !months[0].Start.Equal(want) {
+			t.Errorf("FY%d starts %s, want %s", year, months[0].Start.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+		if want := appleFiscalYearEnd(year); !months[11].End.Equal(want) {
+			t.Errorf("FY%d ends %s, want %s", year, months[11].End.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+		for i, month := range months {
+			if month.Start.Weekday() != time.Sunday || month.End.Weekday() != time.Saturday {
+				t.Errorf("%s runs %s to %s, not Sunday to Saturday", month.Label, month.Start.Weekday(), month.End.Weekday())
+			}
+			if i > 0 && !month.Start.Equal(months[i-1].End.AddDate(0, 0, 1)) {
+				t.Errorf("%s doesn't follow %s", month.Label, months[i-1].Label)
+			}
+			if named := appleFiscalMonthNamed(month.Start.AddDate(0, 0, 14).Year(), month.Start.AddDate(0, 0, 14).Month()); named != month {
+				t.Errorf("%s named lookup = %s", month.Label, named.Label)
+			}
+			if found := AppleFiscalMonth(month.End); found != month {
+				t.Errorf("AppleFiscalMonth(%s) = %s, want %s", month.End.Format("2006-01-02"), found.Label, month.Label)
+			}
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	april := Calendar{FiscalYearStart: time.April}
+
+	tests := []struct {
+		calendar Calendar
+		value    string
+		label    string
+		start    string
+		end      string
+	}{
+		{Calendar{}, "2025-03", "2025-03", "2025-03-01", "2025-03-31"},
+		{Calendar{}, "2025-q2", "2025-Q2", "2025-04-01", "2025-06-30"},
+		{Calendar{}, " 2025 ", "2025", "2025-01-01", "2025-12-31"},
+		{april, "FY2026-Q1", "FY2026-Q1", "2025-04-01", "2025-06-30"},
+		{april, "fy2026-q4", "FY2026-Q4", "2026-01-01", "2026-03-31"},
+		{april, "FY2026", "FY2026", "2025-04-01", "2026-03-31"},
+		// Unprefixed periods are calendar periods, whatever the fiscal year
+		{april, "2025-Q1", "2025-Q1", "2025-01-01", "2025-03-31"},
+		{april, "2025", "2025", "2025-01-01", "2025-12-31"},
+		{Calendar{}, "apple:2024-10", "apple:2024-10", "2024-09-29", "2024-11-02"},
+		{Calendar{}, "APPLE:2025-09", "apple:2025-09", "2025-08-31", "2025-09-27"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			period, err := tt.calendar.ParsePeriod(tt.value)
+			if err != nil {
+				t.Fatalf("ParsePeriod: %v", err)
+			}
+			if period.Label != tt.label || !period.Start.Equal(testDay(tt.start)) || !period.End.Equal(testDay(tt.end)) {
+				t.Errorf("ParsePeriod = %s %s to %s, want %s %s to %s",
+					period.Label, period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"),
+					tt.label, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParsePeriodErrors(t *testing.T) {
+	tests := []struct {
+		calendar Calendar
+		value    string
+		err      string
+	}{
+		{Calendar{}, "", "invalid period"},
+		{Calendar{}, "March", "use YYYY-MM"},
+		{Calendar{}, "2025-Q5", "use YYYY-MM"},
+		{Calendar{}, "2025-13", "month must be 01 to 12"},
+		{Calendar{}, "FY2026", "no fiscal year start configured"},
+		{Calendar{FiscalYearStart: time.April}, "FY2026-04", "fiscal periods are FYYYYY or FYYYYY-QN"},
+		{Calendar{}, "apple:2025", "use apple:YYYY-MM"},
+		{Calendar{}, "apple:2025-Q1", "use apple:YYYY-MM"},
+		{Calendar{}, "apple:2025-00", "use apple:YYYY-MM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, err := tt.calendar.ParsePeriod(tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParsePeriod(%q) error = %v, want it to contain %q", tt.value, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLatestComplete(t *testing.T) {
+	april := Calendar{FiscalYearStart: time.April}
+
+	tests := []struct {
+		calendar Calendar
+		kind     PeriodKind
+		now      string
+		label    string
+	}{
+		// Monthly reports appear around the fifth of the following month
+		{Calendar{}, PeriodMonth, "2025-03-05", "2025-01"},
+		{Calendar{}, PeriodMonth, "2025-03-06", "2025-02"},
+		{Calendar{}, PeriodMonth, "2025-03-31", "2025-02"},
+		{Calendar{}, PeriodQuarter, "2025-04-05", "2024-Q4"},
+		{Calendar{}, PeriodQuarter, "2025-04-06", "2025-Q1"},
+		{Calendar{}, PeriodYear, "2025-12-31", "2024"},
+		{april, PeriodQuarter, "2025-07-31", "FY2026-Q1"},
+		{april, PeriodYear, "2025-04-06", "FY2025"},
+		// Apple fiscal months only need yesterday's daily report
+		{Calendar{}, PeriodAppleMonth, "2024-11-02", "apple:2024-09"},
+		{Calendar{}, PeriodAppleMonth, "2024-11-03", "apple:2024-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind)+" "+tt.now, func(t *testing.T) {
+			if got := tt.calendar.LatestComplete(tt.kind, testDay(tt.now).Add(12*time.Hour)); got.Label != tt.label {
+				t.Errorf("LatestComplete = %s, want %s", got.Label, tt.label)
+			}
+		})
+	}
+}
+
+func TestLatestAvailableMonth(t *testing.T) {
+	tests := []struct {
+		now   string
+		month string
+	}{
+		{"2025-03-01", "2025-01"},
+		{"2025-03-05", "2025-01"},
+		{"2025-03-06", "2025-02"},
+		{"2025-03-31", "2025-02"}, // Not "February 31"
+		{"2025-01-10", "2024-12"},
+		{"2024-05-31", "2024-04"},
+	}
+
+	for _, tt := range tests {
+		if got := LatestAvailableMonth(testDay(tt.now)).Format("2006-01"); got != tt.month {
+			t.Errorf("LatestAvailableMonth(%s) = %s, want %s", tt.now, got, tt.month)
+		}
+	}
+}
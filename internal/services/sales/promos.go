@@ -0,0 +1,275 @@
+package sales
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/countries"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// PromoReport shows how promo and offer codes performed over a series of
+// reports
+type PromoReport struct {
+	Periods   []time.Time
+	Labels    []string        // Period names, e.g. "2025-03"
+	Totals    PromoTotals     // Every sale made with a code
+	Codes     []*PromoCode    // Sorted by units
+	Apps      []*PromoApp     // Apps with code sales, sorted by code units
+	Countries []*PromoCountry // Sorted by units
+	Rows      []PromoRow      `json:"-"` // Ordered by period, code, app, country and currency
+}
+
+// PromoTotals totals units and proceeds
+type PromoTotals struct {
+	Units    int
+	Proceeds map[string]float64 // Currency -> Amount
+
+	// CurrencyUnits counts units per proceeds currency, for ProceedsPerUnit
+	CurrencyUnits map[string]int
+}
+
+// PromoCode is the sales made with one code
+type PromoCode struct {
+	Code string
+	PromoTotals
+	Apps        []string // App names
+	Countries   []string // Country codes
+	Redemptions []int    // Units per period
+}
+
+// PromoApp compares an app's sales with codes against its sales without
+type PromoApp struct {
+	AppID    string
+	AppName  string
+	Codes    []string
+	Promo    PromoTotals
+	Baseline PromoTotals // Sales without a code, not counting updates
+
+	PromoUnits    []int // Per period
+	BaselineUnits []int // Per period
+}
+
+// PromoCountry is the sales made with codes in one country
+type PromoCountry struct {
+	Country     string
+	CountryName string
+	PromoTotals
+	Codes []string
+}
+
+// PromoRow is a flat view of code sales by period, code, app, country and
+// proceeds currency, for exports
+type PromoRow struct {
+	Period   string  `json:"period"`
+	Code     string  `json:"code"`
+	AppID    string  `json:"appId"`
+	App      string  `json:"app"`
+	Country  string  `json:"country"`
+	Units    int     `json:"units"`
+	Proceeds float64 `json:"proceeds"`
+	Currency string  `json:"currency"`
+}
+
+// PromoShare returns the percentage of the app's units sold with a code
+func (a *PromoApp) PromoShare() float64 {
+	total := a.Promo.Units + a.Baseline.Units
+	if total == 0 {
+		return 0
+	}
+	return float64(a.Promo.Units) / float64(total) * 100
+}
+
+// ProceedsPerUnit returns the average proceeds per unit in a currency
+func (t PromoTotals) ProceedsPerUnit(currency string) float64 {
+	if t.CurrencyUnits[currency] == 0 {
+		return 0
+	}
+	return t.Proceeds[currency] / float64(t.CurrencyUnits[currency])
+}
+
+// PrimaryCurrency returns the currency most units were paid in
+func (t PromoTotals) PrimaryCurrency() string {
+	var primary string
+	for currency, units := range t.CurrencyUnits {
+		if primary == "" || units > t.CurrencyUnits[primary] ||
+			(units == t.CurrencyUnits[primary] && currency < primary) {
+			primary = currency
+		}
+	}
+	return primary
+}
+
+func (t *PromoTotals) add(sale models.Sale) {
+	if t.Proceeds == nil {
+		t.Proceeds = make(map[string]float64)
+		t.CurrencyUnits = make(map[string]int)
+	}
+	currency := sale.DeveloperProceeds.Currency
+	t.Units += sale.Units
+	t.Proceeds[currency] += sale.Proceeds()
+	t.CurrencyUnits[currency] += sale.Units
+}
+
+// AnalyzePromos groups sales made with promo and offer codes by code, app and
+// country, and compares each app's code sales with its sales without a code.
+// reports holds one report per period; nil reports count as periods without
+// sales.
+func AnalyzePromos(periods []time.Time, labels []string, reports []*models.SalesReport) *PromoReport {
+	result := &PromoReport{Periods: periods, Labels: labels}
+
+	codes := make(map[string]*PromoCode)
+	apps := make(map[string]*PromoApp)
+	appTotals := make(map[string]*PromoApp) // Every app, with or without codes
+	countryTotals := make(map[string]*PromoCountry)
+	codeApps := make(map[string]map[string]bool)
+	codeCountries := make(map[string]map[string]bool)
+	appCodes := make(map[string]map[string]bool)
+	countryCodes := make(map[string]map[string]bool)
+
+	type rowKey struct{ code, appID, country, currency string }
+
+	for i, report := range reports {
+		if report == nil {
+			continue
+		}
+
+		rows := make(map[rowKey]*PromoRow)
+		var rowKeys []rowKey
+
+		for _, app := range report.Apps {
+			summary, ok := appTotals[app.AppID]
+			if !ok {
+				summary = &PromoApp{
+					AppID:         app.AppID,
+					AppName:       app.AppName,
+					PromoUnits:    make([]int, len(periods)),
+					BaselineUnits: make([]int, len(periods)),
+				}
+				appTotals[app.AppID] = summary
+			}
+
+			for _, sale := range app.Sales {
+				if sale.PromoCode == "" {
+					if sale.ProductCategory() == models.ProductUpdate {
+						// Updates are free and never redeem codes
+						continue
+					}
+					summary.Baseline.add(sale)
+					summary.BaselineUnits[i] += sale.Units
+					continue
+				}
+
+				summary.Promo.add(sale)
+				summary.PromoUnits[i] += sale.Units
+				apps[app.AppID] = summary
+				result.Totals.add(sale)
+
+				code, ok := codes[sale.PromoCode]
+				if !ok {
+					code = &PromoCode{Code: sale.PromoCode, Redemptions: make([]int, len(periods))}
+					codes[sale.PromoCode] = code
+					codeApps[sale.PromoCode] = make(map[string]bool)
+					codeCountries[sale.PromoCode] = make(map[string]bool)
+				}
+				code.add(sale)
+				code.Redemptions[i] += sale.Units
+				codeApps[sale.PromoCode][app.AppName] = true
+				codeCountries[sale.PromoCode][sale.Country] = true
+
+				if appCodes[app.AppID] == nil {
+					appCodes[app.AppID] = make(map[string]bool)
+				}
+				appCodes[app.AppID][sale.PromoCode] = true
+
+				country, ok := countryTotals[sale.Country]
+				if !ok {
+					country = &PromoCountry{Country: sale.Country, CountryName: countries.Name(sale.Country)}
+					countryTotals[sale.Country] = country
+					countryCodes[sale.Country] = make(map[string]bool)
+				}
+				country.add(sale)
+				countryCodes[sale.Country][sale.PromoCode] = true
+
+				key := rowKey{sale.PromoCode, app.AppID, sale.Country, sale.DeveloperProceeds.Currency}
+				row, ok := rows[key]
+				if !ok {
+					row = &PromoRow{
+						Period:   labels[i],
+						Code:     sale.PromoCode,
+						AppID:    app.AppID,
+						App:      app.AppName,
+						Country:  sale.Country,
+						Currency: sale.DeveloperProceeds.Currency,
+					}
+					rows[key] = row
+					rowKeys = append(rowKeys, key)
+				}
+				row.Units += sale.Units
+				row.Proceeds += sale.Proceeds()
+			}
+		}
+
+		sort.Slice(rowKeys, func(a, b int) bool {
+			x, y := rowKeys[a], rowKeys[b]
+			if x.code != y.code {
+				return x.code < y.code
+			}
+			if rows[x].App != rows[y].App {
+				return rows[x].App < rows[y].App
+			}
+			if x.country != y.country {
+				return x.country < y.country
+			}
+			return x.currency < y.currency
+		})
+		for _, key := range rowKeys {
+			result.Rows = append(result.Rows, *rows[key])
+		}
+	}
+
+	for name, code := range codes {
+		code.Apps = sortedSet(codeApps[name])
+		code.Countries = sortedSet(codeCountries[name])
+		result.Codes = append(result.Codes, code)
+	}
+	sort.Slice(result.Codes, func(i, j int) bool {
+		if result.Codes[i].Units != result.Codes[j].Units {
+			return result.Codes[i].Units > result.Codes[j].Units
+		}
+		return result.Codes[i].Code < result.Codes[j].Code
+	})
+
+	for id, app := range apps {
+		app.Codes = sortedSet(appCodes[id])
+		result.Apps = append(result.Apps, app)
+	}
+	sort.Slice(result.Apps, func(i, j int) bool {
+		if result.Apps[i].Promo.Units != result.Apps[j].Promo.Units {
+			return result.Apps[i].Promo.Units > result.Apps[j].Promo.Units
+		}
+		return result.Apps[i].AppName < result.Apps[j].AppName
+	})
+
+	for code, country := range countryTotals {
+		country.Codes = sortedSet(countryCodes[code])
+		result.Countries = append(result.Countries, country)
+	}
+	sort.Slice(result.Countries, func(i, j int) bool {
+		if result.Countries[i].Units != result.Countries[j].Units {
+			return result.Countries[i].Units > result.Countries[j].Units
+		}
+		return result.Countries[i].Country < result.Countries[j].Country
+	})
+
+	return result
+}
+
+func sortedSet(set map[string]bool) []string {
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
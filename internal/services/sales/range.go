@@ -0,0 +1,197 @@
+package sales
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// RangeOptions configures a sales report over arbitrary days
+type RangeOptions struct {
+	DateRange    DateRange // Whole days, both ends included
+	ReportType   models.ReportType
+	VendorNumber string
+	NoCache      bool
+	Filter       *FilterOptions // Applied to the merged report
+}
+
+// RangeReport is a sales report stitched together from Apple's monthly,
+// weekly and daily reports
+type RangeReport struct {
+	Range       DateRange
+	Report      *models.SalesReport
+	Used        []ReportOptions // Reports merged into Report, oldest first
+	Unavailable []ReportOptions // Reports Apple had no data for, oldest first
+}
+
+// GetRange fetches sales for a date range. Whole months come from monthly
+// reports, whole Monday to Sunday weeks from weekly reports and the remaining
+// days from daily reports. When a report is unavailable, usually because
+// Apple hasn't published it yet, its days are fetched from finer reports
+// instead. Every day is counted once. Only sales reports can be stitched:
+// Apple publishes subscription reports daily only, as snapshots that can't be
+// added up over days.
+func (s *Service) GetRange(ctx context.Context, options RangeOptions) (*RangeReport, error) {
+	if err := options.DateRange.Validate(); err != nil {
+		return nil, err
+	}
+	if options.ReportType != models.ReportTypeSales {
+		return nil, fmt.Errorf("%s reports can't be combined over a date range: Apple only publishes them daily, so fetch single days with sales report --period DAILY", options.ReportType)
+	}
+
+	start := startOfDay(options.DateRange.Start)
+	end := startOfDay(options.DateRange.End)
+	result := &RangeReport{Range: DateRange{Start: start, End: end}}
+
+	base := ReportOptions{
+		ReportType:   options.ReportType,
+		VendorNumber: options.VendorNumber,
+		NoCache:      options.NoCache,
+	}
+
+	var reports []*models.SalesReport
+	pending := planRange(start, end, models.ReportFrequencyMonthly, base)
+	for len(pending) > 0 {
+		fetched, err := s.GetMultipleReports(ctx, pending)
+		if err != nil {
+			return nil, err
+		}
+
+		var retry []ReportOptions
+		for i, report := range fetched {
+			request := pending[i]
+			if report != nil {
+				reports = append(reports, report)
+				result.Used = append(result.Used, request)
+				continue
+			}
+
+			result.Unavailable = append(result.Unavailable, request)
+			if finer := finerPeriod(request.Period); finer != "" {
+				first, last := request.Span()
+				retry = append(retry, planRange(first, last, finer, base)...)
+			}
+		}
+		pending = retry
+	}
+
+	sortBySpan(result.Used)
+	sortBySpan(result.Unavailable)
+	result.Report = s.mergeReports(reports, start, options.VendorNumber)
+	if options.Filter != nil {
+		result.Report = s.filterReport(result.Report, options.Filter)
+	}
+	result.Report.Summary.Period = fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	return result, nil
+}
+
+// planRange covers the days from start to end with as few reports as
+// possible, using no period coarser than coarsest
+func planRange(start, end time.Time, coarsest models.ReportFrequency, base ReportOptions) []ReportOptions {
+	monthly := coarsest == models.ReportFrequencyMonthly
+	weekly := monthly || coarsest == models.ReportFrequencyWeekly
+
+	// wholeMonth reports whether the month starting on day fits in the range
+	wholeMonth := func(day time.Time) bool {
+		return monthly && day.Day() == 1 && !day.AddDate(0, 1, -1).After(end)
+	}
+
+	var plan []ReportOptions
+	add := func(period models.ReportFrequency, date time.Time) {
+		request := base
+		request.Period = period
+		request.Date = date
+		plan = append(plan, request)
+	}
+
+	for day := start; !day.After(end); {
+		if wholeMonth(day) {
+			add(models.ReportFrequencyMonthly, day)
+			day = day.AddDate(0, 1, 0)
+			continue
+		}
+
+		if weekly && day.Weekday() == time.Monday {
+			sunday := day.AddDate(0, 0, 6)
+			nextMonth := time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			// Don't let a week eat into a month a monthly report can cover
+			crossesWholeMonth := !nextMonth.After(sunday) && wholeMonth(nextMonth)
+			if !sunday.After(end) && !crossesWholeMonth {
+				add(models.ReportFrequencyWeekly, sunday)
+				day = sunday.AddDate(0, 0, 1)
+				continue
+			}
+		}
+
+		add(models.ReportFrequencyDaily, day)
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return plan
+}
+
+// finerPeriod returns the next finer period to fall back to, or "" for daily
+func finerPeriod(period models.ReportFrequency) models.ReportFrequency {
+	switch period {
+	case models.ReportFrequencyMonthly:
+		return models.ReportFrequencyWeekly
+	case models.ReportFrequencyWeekly:
+		return models.ReportFrequencyDaily
+	default:
+		return ""
+	}
+}
+
+// mergeReports combines reports covering separate days into one report
+func (s *Service) mergeReports(reports []*models.SalesReport, date time.Time, vendorNumber string) *models.SalesReport {
+	type appEntry struct {
+		name, sku string
+		sales     []models.Sale
+	}
+	apps := make(map[string]*appEntry)
+
+	for _, report := range reports {
+		for _, app := range report.Apps {
+			entry, ok := apps[app.AppID]
+			if !ok {
+				entry = &appEntry{}
+				apps[app.AppID] = entry
+			}
+			if app.AppName != "" {
+				entry.name = app.AppName
+			}
+			if app.SKU != "" {
+				entry.sku = app.SKU
+			}
+			entry.sales = append(entry.sales, app.Sales...)
+		}
+	}
+
+	appSales := make([]models.AppSales, 0, len(apps))
+	for appID, entry := range apps {
+		sort.SliceStable(entry.sales, func(i, j int) bool {
+			return entry.sales[i].Date.Before(entry.sales[j].Date)
+		})
+		appSales = append(appSales, summarizeApp(appID, entry.name, entry.sku, entry.sales))
+	}
+
+	// A range spans several report periods, so it has none of its own
+	return s.newReport("", date, vendorNumber, appSales)
+}
+
+// sortBySpan orders reports by the first day they cover
+func sortBySpan(requests []ReportOptions) {
+	sort.SliceStable(requests, func(i, j int) bool {
+		first, _ := requests[i].Span()
+		other, _ := requests[j].Span()
+		return first.Before(other)
+	})
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,176 @@
+package sales
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// Refund spike defaults
+const (
+	DefaultRefundSpikeFactor = 2.0
+	DefaultRefundMinReturns  = 3
+)
+
+// minRefundRateIncrease is the fewest percentage points a refund rate must
+// rise by to count as a spike, so that tiny baselines don't flag noise
+const minRefundRateIncrease = 1.0
+
+// RefundOptions configures refund spike detection
+type RefundOptions struct {
+	SpikeFactor float64 // How many times its baseline rate an app's latest rate must reach
+	MinReturns  int     // Fewest units returned in the latest period for a spike
+}
+
+// RefundReport separates gross sales from returns over a series of reports,
+// per app and per country
+type RefundReport struct {
+	Periods   []time.Time
+	Labels    []string // Period names, e.g. "2025-03"
+	Totals    models.Refunds
+	Apps      []*AppRefunds     // Apps with paid sales, sorted by units returned
+	Countries []*CountryRefunds // Sorted by units returned
+	Insights  []Insight         // Refund rate spikes
+}
+
+// AppRefunds is an app's gross sales and returns, overall and per period
+type AppRefunds struct {
+	AppID   string
+	AppName string
+	models.Refunds
+	Periods []models.Refunds
+
+	// LatestRate is the refund rate in the last period, and BaselineRate
+	// the rate over the periods before it
+	LatestRate   float64
+	BaselineRate float64
+	Spike        bool
+}
+
+// CountryRefunds is a country's gross sales and returns
+type CountryRefunds struct {
+	Country     string
+	CountryName string
+	models.Refunds
+}
+
+// AnalyzeRefunds totals returns by app and country and flags apps whose
+// refund rate in the last period is SpikeFactor times their rate over the
+// earlier periods. reports holds one report per period; nil reports count as
+// periods without sales.
+func AnalyzeRefunds(periods []time.Time, labels []string, reports []*models.SalesReport, options RefundOptions) *RefundReport {
+	if options.SpikeFactor <= 0 {
+		options.SpikeFactor = DefaultRefundSpikeFactor
+	}
+	if options.MinReturns <= 0 {
+		options.MinReturns = DefaultRefundMinReturns
+	}
+
+	result := &RefundReport{Periods: periods, Labels: labels}
+	apps := make(map[string]*AppRefunds)
+	countryTotals := make(map[string]*CountryRefunds)
+
+	for i, report := range reports {
+		if report == nil {
+			continue
+		}
+		result.Totals.Merge(report.Summary.Refunds)
+
+		for _, app := range report.Apps {
+			refunds, ok := apps[app.AppID]
+			if !ok {
+				refunds = &AppRefunds{
+					AppID:   app.AppID,
+					AppName: app.AppName,
+					Periods: make([]models.Refunds, len(periods)),
+				}
+				apps[app.AppID] = refunds
+			}
+			refunds.Merge(app.Summary.Refunds)
+			refunds.Periods[i].Merge(app.Summary.Refunds)
+		}
+
+		for _, country := range CountryBreakdown(report) {
+			refunds, ok := countryTotals[country.Country]
+			if !ok {
+				refunds = &CountryRefunds{Country: country.Country, CountryName: country.CountryName}
+				countryTotals[country.Country] = refunds
+			}
+			refunds.Merge(country.Refunds)
+		}
+	}
+
+	for _, app := range apps {
+		if app.GrossUnits == 0 && app.ReturnedUnits == 0 {
+			// Free apps have nothing to refund
+			continue
+		}
+		app.detectSpike(options)
+		result.Apps = append(result.Apps, app)
+	}
+	sort.Slice(result.Apps, func(i, j int) bool {
+		if result.Apps[i].ReturnedUnits != result.Apps[j].ReturnedUnits {
+			return result.Apps[i].ReturnedUnits > result.Apps[j].ReturnedUnits
+		}
+		return result.Apps[i].AppName < result.Apps[j].AppName
+	})
+
+	for _, country := range countryTotals {
+		result.Countries = append(result.Countries, country)
+	}
+	sort.Slice(result.Countries, func(i, j int) bool {
+		if result.Countries[i].ReturnedUnits != result.Countries[j].ReturnedUnits {
+			return result.Countries[i].ReturnedUnits > result.Countries[j].ReturnedUnits
+		}
+		return result.Countries[i].Country < result.Countries[j].Country
+	})
+
+	for _, app := range result.Apps {
+		if app.Spike {
+			result.Insights = append(result.Insights, app.insight(labels[len(labels)-1]))
+		}
+	}
+
+	return result
+}
+
+// detectSpike compares the app's refund rate in the last period with its
+// rate over the earlier ones
+func (a *AppRefunds) detectSpike(options RefundOptions) {
+	if len(a.Periods) == 0 {
+		return
+	}
+
+	latest := a.Periods[len(a.Periods)-1]
+	var baseline models.Refunds
+	for _, period := range a.Periods[:len(a.Periods)-1] {
+		baseline.Merge(period)
+	}
+
+	a.LatestRate = latest.Rate()
+	a.BaselineRate = baseline.Rate()
+	a.Spike = baseline.GrossUnits > 0 &&
+		latest.ReturnedUnits >= options.MinReturns &&
+		a.LatestRate >= options.SpikeFactor*a.BaselineRate &&
+		a.LatestRate-a.BaselineRate >= minRefundRateIncrease
+}
+
+func (a *AppRefunds) insight(period string) Insight {
+	latest := a.Periods[len(a.Periods)-1]
+	return Insight{
+		Type:     InsightWarning,
+		Severity: InsightSeverityWarning,
+		Title:    fmt.Sprintf("Refund spike: %s", a.AppName),
+		Description: fmt.Sprintf("%.1f%% of units were returned in %s (%d of %d), up from %.1f%% before",
+			a.LatestRate, period, latest.ReturnedUnits, latest.GrossUnits, a.BaselineRate),
+		Data: map[string]interface{}{
+			"app_id":        a.AppID,
+			"period":        period,
+			"latest_rate":   a.LatestRate,
+			"baseline_rate": a.BaselineRate,
+			"returned":      latest.ReturnedUnits,
+		},
+	}
+}
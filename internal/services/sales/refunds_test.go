@@ -0,0 +1,151 @@
+package sales
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// refundPeriod is one period's paid units sold and returned
+type refundPeriod struct {
+	sold, returned int
+}
+
+// refundReports returns a monthly report per period with a paid app selling
+// and returning units at 1.50 in the US, next to a free app
+func refundReports(periods []refundPeriod) ([]time.Time, []string, []*models.SalesReport) {
+	price := models.Money{Amount: 1.5, Currency: "USD"}
+	first := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	dates := make([]time.Time, len(periods))
+	labels := make([]string, len(periods))
+	reports := make([]*models.SalesReport, len(periods))
+	for i, period := range periods {
+		dates[i] = first.AddDate(0, i, 0)
+		labels[i] = dates[i].Format("2006-01")
+
+		paid := []models.Sale{{Country: "US", Units: period.sold, DeveloperProceeds: price}}
+		if period.returned > 0 {
+			paid = append(paid, models.Sale{Country: "GB", Units: -period.returned, DeveloperProceeds: price})
+		}
+		free := []models.Sale{{Country: "US", Units: 500}, {Country: "US", Units: -2}}
+
+		report := &models.SalesReport{Apps: []models.AppSales{
+			summarizeApp("111", "Foo App", "FOO", paid),
+			summarizeApp("222", "Bar App", "BAR", free),
+		}}
+		for _, app := range report.Apps {
+			report.Summary.Refunds.Merge(app.Summary.Refunds)
+		}
+		reports[i] = report
+	}
+	return dates, labels, reports
+}
+
+func TestAnalyzeRefundsSpikes(t *testing.T) {
+	tests := []struct {
+		name     string
+		periods  []refundPeriod
+		options  RefundOptions
+		latest   float64
+		baseline float64
+		spike    bool
+	}{
+		{
+			name:    "spike",
+			periods: []refundPeriod{{100, 1}, {100, 1}, {100, 1}, {100, 5}},
+			latest:  5, baseline: 1, spike: true,
+		},
+		{
+			name:    "below the spike factor",
+			periods: []refundPeriod{{100, 2}, {100, 2}, {100, 2}, {100, 3}},
+			latest:  3, baseline: 2,
+		},
+		{
+			name:    "custom spike factor",
+			periods: []refundPeriod{{100, 2}, {100, 2}, {100, 2}, {100, 5}},
+			options: RefundOptions{SpikeFactor: 3},
+			latest:  5, baseline: 2,
+		},
+		{
+			name:    "too few returns",
+			periods: []refundPeriod{{100, 0}, {100, 0}, {100, 0}, {100, 2}},
+			latest:  2, baseline: 0,
+		},
+		{
+			name:    "custom minimum returns",
+			periods: []refundPeriod{{100, 0}, {100, 0}, {100, 0}, {100, 2}},
+			options: RefundOptions{MinReturns: 2},
+			latest:  2, baseline: 0, spike: true,
+		},
+		{
+			// Four times the rate, but less than a percentage point more
+			name:    "under one percentage point",
+			periods: []refundPeriod{{1000, 2}, {1000, 2}, {1000, 2}, {1000, 8}},
+			latest:  0.8, baseline: 0.2,
+		},
+		{
+			name:    "no earlier sales",
+			periods: []refundPeriod{{0, 0}, {0, 0}, {100, 10}},
+			latest:  10, baseline: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates, labels, reports := refundReports(tt.periods)
+			result := AnalyzeRefunds(dates, labels, reports, tt.options)
+			if len(result.Apps) != 1 {
+				t.Fatalf("got %d apps, want only the paid one", len(result.Apps))
+			}
+			app := result.Apps[0]
+			if math.Abs(app.LatestRate-tt.latest) > 1e-9 || math.Abs(app.BaselineRate-tt.baseline) > 1e-9 {
+				t.Errorf("rates = %.2f%% latest, %.2f%% baseline, want %.2f%% and %.2f%%",
+					app.LatestRate, app.BaselineRate, tt.latest, tt.baseline)
+			}
+			if app.Spike != tt.spike || len(result.Insights) != map[bool]int{false: 0, true: 1}[tt.spike] {
+				t.Errorf("spike = %v with %d insights, want %v", app.Spike, len(result.Insights), tt.spike)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRefundsTotals(t *testing.T) {
+	dates, labels, reports := refundReports([]refundPeriod{{100, 1}, {60, 3}})
+	reports = append(reports, nil) // A month without a report
+	dates = append(dates, dates[1].AddDate(0, 1, 0))
+	labels = append(labels, "2025-03")
+
+	result := AnalyzeRefunds(dates, labels, reports, RefundOptions{})
+
+	want := models.Refunds{
+		GrossUnits:       160,
+		ReturnedUnits:    4,
+		GrossProceeds:    map[string]float64{"USD": 240},
+		RefundedProceeds: map[string]float64{"USD": 6},
+	}
+	if !reflect.DeepEqual(result.Totals, want) {
+		t.Errorf("Totals = %+v, want %+v", result.Totals, want)
+	}
+
+	// The free app's 500 downloads and its free return are left out
+	if len(result.Apps) != 1 || result.Apps[0].AppID != "111" || result.Apps[0].Rate() != 2.5 {
+		t.Fatalf("Apps = %+v, want Foo App at 2.5%%", result.Apps)
+	}
+	if got := result.Apps[0].Periods; got[0].ReturnedUnits != 1 || got[1].ReturnedUnits != 3 || got[2].GrossUnits != 0 {
+		t.Errorf("app periods = %+v", got)
+	}
+
+	// Returns are counted where they were reported
+	var countries []string
+	for _, country := range result.Countries {
+		countries = append(countries, fmt.Sprintf("%s %d/%d", country.Country, country.ReturnedUnits, country.GrossUnits))
+	}
+	if got, want := fmt.Sprint(countries), "[GB 4/0 US 0/160]"; got != want {
+		t.Errorf("Countries = %s, want %s", got, want)
+	}
+}
@@ -8,14 +8,20 @@ import (
 	"sync"
 	"time"
 
-	"github.com/marcusziade/pomme/internal/api"
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/internal/countries"
 	"github.com/marcusziade/pomme/internal/services/cache"
+	"github.com/marcusziade/pomme/pkg/models"
 )
 
+// ReportFetcher downloads raw, decompressed sales report data. It returns
+// nil data when Apple has no report for the requested period.
+type ReportFetcher interface {
+	GetSalesReport(ctx context.Context, frequency models.ReportFrequency, reportDate string, reportType models.ReportType, vendorNumber string) ([]byte, error)
+}
+
 // Service handles all sales-related operations
 type Service struct {
-	client      *api.Client
+	fetcher     ReportFetcher
 	cache       cache.Cache
 	parser      *Parser
 	analyzer    *Analyzer
@@ -23,9 +29,9 @@ type Service struct {
 }
 
 // NewService creates a new sales service
-func NewService(client *api.Client, cacheService cache.Cache) *Service {
+func NewService(fetcher ReportFetcher, cacheService cache.Cache) *Service {
 	return &Service{
-		client:      client,
+		fetcher:     fetcher,
 		cache:       cacheService,
 		parser:      NewParser(),
 		analyzer:    NewAnalyzer(),
@@ -35,6 +41,30 @@ func NewService(client *api.Client, cacheService cache.Cache) *Service {
 
 // GetReport fetches and processes a sales report
 func (s *Service) GetReport(ctx context.Context, options ReportOptions) (*models.SalesReport, error) {
+	report, err := s.getParsedReport(ctx, options)
+	if err != nil || report == nil {
+		return nil, err
+	}
+
+	// Filter before analysis, so trends reflect the filtered sales
+	if options.Filter != nil {
+		report = s.filterReport(report, options.Filter)
+	}
+
+	// Analyze the data. Unfiltered reports are shared through the cache, so
+	// the trends go on a copy.
+	if options.IncludeAnalysis {
+		analyzed := *report
+		analyzed.Summary.Trends = s.analyzer.AnalyzeTrends(report, options.PreviousPeriod)
+		report = &analyzed
+	}
+
+	return report, nil
+}
+
+// getParsedReport returns the unfiltered report from the cache, or fetches
+// and parses it
+func (s *Service) getParsedReport(ctx context.Context, options ReportOptions) (*models.SalesReport, error) {
 	// Check cache first
 	cacheKey := options.CacheKey()
 	if s.cache != nil && !options.NoCache {
@@ -51,17 +81,17 @@ func (s *Service) GetReport(ctx context.Context, options ReportOptions) (*models
 		return nil, fmt.Errorf("failed to fetch report: %w", err)
 	}
 
+	if len(rawData) == 0 {
+		// No data available for this period
+		return nil, nil
+	}
+
 	// Parse the report concurrently
 	report, err := s.parseReport(ctx, rawData, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse report: %w", err)
 	}
 
-	// Analyze the data
-	if options.IncludeAnalysis {
-		report.Summary.Trends = s.analyzer.AnalyzeTrends(report, options.PreviousPeriod)
-	}
-
 	// Cache the result
 	if s.cache != nil && !options.NoCache {
 		s.cache.Set(cacheKey, report, 24*time.Hour)
@@ -136,12 +166,22 @@ func (s *Service) GetComparison(ctx context.Context, current, previous ReportOpt
 	if previousErr != nil {
 		return nil, fmt.Errorf("failed to get previous report: %w", previousErr)
 	}
+	if currentReport == nil {
+		return nil, fmt.Errorf("no sales data available for %s", current.FormatDate())
+	}
+	if previousReport == nil {
+		return nil, fmt.Errorf("no sales data available for %s", previous.FormatDate())
+	}
 	
 	return s.analyzer.Compare(currentReport, previousReport), nil
 }
 
 // GetTrends analyzes trends over multiple periods
 func (s *Service) GetTrends(ctx context.Context, options TrendOptions) (*TrendReport, error) {
+	if options.Bucket != "" {
+		return s.getBucketedTrends(ctx, options)
+	}
+
 	// Generate report options for each period
 	requests := s.generateTrendRequests(options)
 	
@@ -152,13 +192,101 @@ func (s *Service) GetTrends(ctx context.Context, options TrendOptions) (*TrendRe
 	}
 	
 	// Analyze trends
-	return s.analyzer.AnalyzeTrendSeries(reports, options), nil
+	trend := s.analyzer.AnalyzeTrendSeries(reports, options)
+	if trend != nil {
+		trend.Labels = make([]string, len(requests))
+		for i, request := range requests {
+			// Periods without a report still get their date
+			trend.Periods[i] = request.Date
+			trend.Labels[i] = request.FormatDate()
+		}
+	}
+	return trend, nil
+}
+
+// getBucketedTrends rolls reports up into quarters, years or Apple fiscal
+// months
+func (s *Service) getBucketedTrends(ctx context.Context, options TrendOptions) (*TrendReport, error) {
+	periods := options.Calendar.Periods(options.Bucket, options.EndDate, options.Periods)
+	reports := make([]*models.SalesReport, len(periods))
+	
+	for i, period := range periods {
+		report, err := s.GetPeriod(ctx, period, RangeOptions{
+			ReportType:   options.ReportType,
+			VendorNumber: options.VendorNumber,
+			Filter:       options.Filter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		reports[i] = report
+	}
+	
+	trend := s.analyzer.AnalyzeTrendSeries(reports, options)
+	if trend != nil {
+		trend.Bucket = options.Bucket
+		trend.Labels = make([]string, len(periods))
+		for i, period := range periods {
+			trend.Periods[i] = period.Start
+			trend.Labels[i] = period.Label
+		}
+	}
+	return trend, nil
+}
+
+// GetPeriod fetches sales for a period, rolling up monthly reports where it
+// covers whole months. It returns nil when Apple has no data for any of it.
+// The DateRange of options is ignored.
+func (s *Service) GetPeriod(ctx context.Context, period Period, options RangeOptions) (*models.SalesReport, error) {
+	options.DateRange = period.DateRange()
+	result, err := s.GetRange(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", period.Label, err)
+	}
+	if len(result.Used) == 0 {
+		return nil, nil
+	}
+	
+	result.Report.Summary.Period = period.Label
+	return result.Report, nil
+}
+
+// ComparePeriods compares sales in two periods of any kind
+func (s *Service) ComparePeriods(ctx context.Context, current, previous Period, options RangeOptions) (*Comparison, error) {
+	var currentReport, previousReport *models.SalesReport
+	var currentErr, previousErr error
+	
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		currentReport, currentErr = s.GetPeriod(ctx, current, options)
+	}()
+	go func() {
+		defer wg.Done()
+		previousReport, previousErr = s.GetPeriod(ctx, previous, options)
+	}()
+	wg.Wait()
+	
+	if currentErr != nil {
+		return nil, fmt.Errorf("failed to get current report: %w", currentErr)
+	}
+	if previousErr != nil {
+		return nil, fmt.Errorf("failed to get previous report: %w", previousErr)
+	}
+	if currentReport == nil {
+		return nil, fmt.Errorf("no sales data available for %s", current.Label)
+	}
+	if previousReport == nil {
+		return nil, fmt.Errorf("no sales data available for %s", previous.Label)
+	}
+	
+	return s.analyzer.Compare(currentReport, previousReport), nil
 }
 
 // fetchReport retrieves raw report data from the API
 func (s *Service) fetchReport(ctx context.Context, options ReportOptions) ([]byte, error) {
-	// This would be implemented when we have the proper API client integration
-	return nil, fmt.Errorf("not implemented")
+	return s.fetcher.GetSalesReport(ctx, options.Period, options.FormatDate(), options.ReportType, options.VendorNumber)
 }
 
 // parseReport parses raw CSV data into a structured report
@@ -198,24 +326,25 @@ func (s *Service) parseReport(ctx context.Context, data []byte, options ReportOp
 	
 	wg.Wait()
 	
-	// Sort apps by total units
+	return s.newReport(options.Period, options.Date, options.VendorNumber, appSales), nil
+}
+
+// newReport builds a report from app sales, sorted by total units
+func (s *Service) newReport(period models.ReportFrequency, date time.Time, vendorNumber string, appSales []models.AppSales) *models.SalesReport {
 	sort.Slice(appSales, func(i, j int) bool {
 		return appSales[i].Summary.TotalUnits > appSales[j].Summary.TotalUnits
 	})
 	
-	// Build report
 	report := &models.SalesReport{
-		Period:      options.Period,
-		Date:        options.Date,
-		VendorID:    options.VendorNumber,
+		Period:      period,
+		Date:        date,
+		VendorID:    vendorNumber,
 		Apps:        appSales,
 		GeneratedAt: time.Now(),
 	}
-	
-	// Calculate summary
 	report.Summary = s.calculateReportSummary(report)
 	
-	return report, nil
+	return report
 }
 
 // groupRecordsByApp groups sales records by app ID
@@ -237,14 +366,46 @@ func (s *Service) processAppSales(appID string, records []models.SalesRecord) mo
 	
 	// Use first record for app metadata
 	first := records[0]
+	sales := make([]models.Sale, 0, len(records))
+	for _, record := range records {
+		customerCurrency := record.CustomerCurrency
+		if customerCurrency == "" {
+			customerCurrency = countries.Currency(record.CountryCode)
+		}
+		sales = append(sales, models.Sale{
+			Date:        s.parser.ParseDate(record.BeginDate),
+			Country:     record.CountryCode,
+			CountryName: countries.Name(record.CountryCode),
+			Units:       record.Units,
+			CustomerPrice: models.Money{
+				Amount:   record.CustomerPrice,
+				Currency: customerCurrency,
+			},
+			DeveloperProceeds: models.Money{
+				Amount:   record.DeveloperProceeds,
+				Currency: record.CurrencyOfProceeds,
+			},
+			ProductType: record.ProductTypeID,
+			Platform:    record.SupportedPlatforms,
+			Device:      record.DeviceType,
+			PromoCode:   record.PromoCode,
+			ParentID:    record.ParentID,
+			Category:    record.Category,
+		})
+	}
+
+	return summarizeApp(appID, first.Title, first.SKU, sales)
+}
+
+// summarizeApp builds an app's sales with their summary
+func summarizeApp(appID, name, sku string, sales []models.Sale) models.AppSales {
 	appSales := models.AppSales{
 		AppID:   appID,
-		AppName: first.Title,
-		SKU:     first.SKU,
-		Sales:   make([]models.Sale, 0, len(records)),
+		AppName: name,
+		SKU:     sku,
+		Sales:   sales,
 	}
 	
-	// Convert records to sales
 	summary := models.AppSummary{
 		TotalProceeds: make(map[string]float64),
 		AvgPrice:      make(map[string]float64),
@@ -256,37 +417,17 @@ func (s *Service) processAppSales(appID string, records []models.SalesRecord) mo
 	priceSum := make(map[string]float64)
 	priceCount := make(map[string]int)
 	
-	for _, record := range records {
-		sale := models.Sale{
-			Date:    s.parser.ParseDate(record.BeginDate),
-			Country: record.CountryCode,
-			Units:   record.Units,
-			CustomerPrice: models.Money{
-				Amount:   record.CustomerPrice,
-				Currency: record.CustomerCurrency,
-			},
-			DeveloperProceeds: models.Money{
-				Amount:   record.DeveloperProceeds,
-				Currency: record.CurrencyOfProceeds,
-			},
-			ProductType: record.ProductTypeID,
-			Platform:    record.SupportedPlatforms,
-			Device:      record.DeviceType,
-			PromoCode:   record.PromoCode,
-			ParentID:    record.ParentID,
-			Category:    record.Category,
-		}
-		
-		appSales.Sales = append(appSales.Sales, sale)
-		
+	for _, sale := range sales {
 		// Update summary
 		summary.TotalUnits += sale.Units
+		summary.Breakdown.Add(sale)
+		summary.Refunds.Add(sale)
 		
-		if sale.DeveloperProceeds.Amount > 0 && sale.DeveloperProceeds.Currency != "" {
-			summary.TotalProceeds[sale.DeveloperProceeds.Currency] += sale.DeveloperProceeds.Amount
+		if proceeds := sale.Proceeds(); proceeds != 0 && sale.DeveloperProceeds.Currency != "" {
+			summary.TotalProceeds[sale.DeveloperProceeds.Currency] += proceeds
 		}
 		
-		if sale.CustomerPrice.Amount > 0 && sale.CustomerPrice.Currency != "" {
+		if sale.CustomerPrice.Amount > 0 && sale.CustomerPrice.Currency != "" && !sale.IsReturn() {
 			priceSum[sale.CustomerPrice.Currency] += sale.CustomerPrice.Amount * float64(sale.Units)
 			priceCount[sale.CustomerPrice.Currency] += sale.Units
 		}
@@ -300,17 +441,7 @@ func (s *Service) processAppSales(appID string, records []models.SalesRecord) mo
 		}
 		
 		// Country aggregation
-		if _, exists := countryMap[sale.Country]; !exists {
-			countryMap[sale.Country] = &models.CountrySales{
-				Country:   sale.Country,
-				CountryName: s.getCountryName(sale.Country),
-				Proceeds:  make(map[string]float64),
-			}
-		}
-		countryMap[sale.Country].Units += sale.Units
-		if sale.DeveloperProceeds.Amount > 0 && sale.DeveloperProceeds.Currency != "" {
-			countryMap[sale.Country].Proceeds[sale.DeveloperProceeds.Currency] += sale.DeveloperProceeds.Amount
-		}
+		addCountrySale(countryMap, sale)
 	}
 	
 	// Calculate averages
@@ -354,6 +485,8 @@ func (s *Service) calculateReportSummary(report *models.SalesReport) models.Repo
 	
 	for _, app := range report.Apps {
 		summary.TotalUnits += app.Summary.TotalUnits
+		summary.Breakdown.Merge(app.Summary.Breakdown)
+		summary.Refunds.Merge(app.Summary.Refunds)
 		
 		// Aggregate proceeds
 		for currency, amount := range app.Summary.TotalProceeds {
@@ -368,16 +501,16 @@ func (s *Service) calculateReportSummary(report *models.SalesReport) models.Repo
 	
 	summary.TotalCountries = len(countrySet)
 	
-	// Top apps (already sorted)
-	for i, app := range report.Apps {
+	// Top apps, counting their in-app purchases with them
+	for i, app := range NestApps(report.Apps) {
 		if i >= 5 {
 			break
 		}
 		summary.TopApps = append(summary.TopApps, models.AppRanking{
 			AppID:    app.AppID,
 			AppName:  app.AppName,
-			Units:    app.Summary.TotalUnits,
-			Proceeds: app.Summary.TotalProceeds,
+			Units:    app.RollUp.TotalUnits,
+			Proceeds: app.RollUp.TotalProceeds,
 			Rank:     i + 1,
 		})
 	}
@@ -385,28 +518,7 @@ func (s *Service) calculateReportSummary(report *models.SalesReport) models.Repo
 	return summary
 }
 
-// getCountryName returns the full country name for a country code
-func (s *Service) getCountryName(code string) string {
-	// This would be populated from a proper country code mapping
-	countryNames := map[string]string{
-		"US": "United States",
-		"GB": "United Kingdom",
-		"DE": "Germany",
-		"FR": "France",
-		"JP": "Japan",
-		"CN": "China",
-		"CA": "Canada",
-		"AU": "Australia",
-		// ... add more as needed
-	}
-	
-	if name, ok := countryNames[code]; ok {
-		return name
-	}
-	return code
-}
-
-// generateTrendRequests generates report requests for trend analysis
+// generateTrendRequests generates report requests for trend analysis, oldest first
 func (s *Service) generateTrendRequests(options TrendOptions) []ReportOptions {
 	requests := make([]ReportOptions, options.Periods)
 	
@@ -424,11 +536,12 @@ func (s *Service) generateTrendRequests(options TrendOptions) []ReportOptions {
 			date = date.AddDate(-i, 0, 0)
 		}
 		
-		requests[i] = ReportOptions{
+		requests[options.Periods-1-i] = ReportOptions{
 			Period:       options.Frequency,
 			Date:         date,
 			ReportType:   options.ReportType,
 			VendorNumber: options.VendorNumber,
+			Filter:       options.Filter,
 		}
 	}
 	
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/pkg/models"
 )
 
 // ReportOptions configures a sales report request
@@ -15,6 +15,7 @@ type ReportOptions struct {
 	VendorNumber   string
 	NoCache        bool
 	IncludeAnalysis bool
+	Filter         *FilterOptions // Applied after parsing, before summaries
 	PreviousPeriod *models.SalesReport // For trend analysis
 }
 
@@ -24,7 +25,7 @@ func (o ReportOptions) FormatDate() string {
 	case models.ReportFrequencyDaily:
 		return o.Date.Format("2006-01-02")
 	case models.ReportFrequencyWeekly:
-		// Apple weeks end on Saturday
+		// Apple weeks run Monday to Sunday and are named by their Sunday
 		return o.Date.Format("2006-01-02")
 	case models.ReportFrequencyMonthly:
 		return o.Date.Format("2006-01")
@@ -35,6 +36,29 @@ func (o ReportOptions) FormatDate() string {
 	}
 }
 
+// Span returns the first and last day the report covers. Weekly reports are
+// named by the Sunday they end on.
+func (o ReportOptions) Span() (time.Time, time.Time) {
+	day := startOfDay(o.Date)
+	switch o.Period {
+	case models.ReportFrequencyWeekly:
+		return day.AddDate(0, 0, -6), day
+	case models.ReportFrequencyMonthly:
+		first := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(0, 1, -1)
+	case models.ReportFrequencyYearly:
+		first := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(1, 0, -1)
+	default:
+		return day, day
+	}
+}
+
+// String names the report, e.g. "MONTHLY 2025-03"
+func (o ReportOptions) String() string {
+	return fmt.Sprintf("%s %s", o.Period, o.FormatDate())
+}
+
 // CacheKey generates a unique cache key for the report
 func (o ReportOptions) CacheKey() string {
 	return fmt.Sprintf("sales:%s:%s:%s:%s",
@@ -45,6 +69,22 @@ func (o ReportOptions) CacheKey() string {
 	)
 }
 
+// LatestAvailableMonth returns the most recent month Apple has published a
+// monthly report for as of now
+func LatestAvailableMonth(now time.Time) time.Time {
+	// Count back from the first of the month, as March 31 less a month is
+	// "February 31", which normalizes to March 3
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	// If we're within the first 5 days of the month, go back 2 months
+	if now.Day() <= 5 {
+		return month.AddDate(0, -2, 0)
+	}
+
+	// Otherwise, last month's data should be available
+	return month.AddDate(0, -1, 0)
+}
+
 // TrendOptions configures trend analysis
 type TrendOptions struct {
 	Frequency    models.ReportFrequency
@@ -53,6 +93,12 @@ type TrendOptions struct {
 	ReportType   models.ReportType
 	VendorNumber string
 	GroupBy      string // "app", "country", "platform"
+	Filter       *FilterOptions
+
+	// Bucket rolls reports up into quarters, years or Apple fiscal months,
+	// split by Calendar, instead of one period per Frequency report
+	Bucket   PeriodKind
+	Calendar Calendar
 }
 
 // Comparison represents a comparison between two reports
@@ -85,11 +131,21 @@ type CountryChange struct {
 	ProceedsChange map[string]float64
 }
 
+// ProductTypeSales represents sales of one product type
+type ProductTypeSales struct {
+	models.ProductType
+	Units    int
+	Proceeds map[string]float64 // Currency -> Amount
+}
+
 // TrendReport represents trends over multiple periods
 type TrendReport struct {
 	Periods        []time.Time
+	Labels         []string   // Period names, e.g. "2025-03" or "2025-Q2"
+	Bucket         PeriodKind // Set when reports were rolled up into periods
 	Frequency      models.ReportFrequency
 	TotalUnits     []int
+	Breakdowns     []models.UnitBreakdown // Units by product category per period
 	TotalProceeds  map[string][]float64 // Currency -> Values per period
 	AppTrends      map[string]*AppTrend
 	CountryTrends  map[string]*CountryTrend
@@ -162,16 +218,18 @@ const (
 	ExportPDF   ExportFormat = "pdf"
 )
 
-// FilterOptions allows filtering sales data
+// FilterOptions allows filtering sales data. Every set field must match.
 type FilterOptions struct {
 	Apps         []string
 	Countries    []string
+	Regions      []string // Region groups or Apple finance regions, see countries.InRegion
 	MinUnits     int
 	MinProceeds  float64
 	Currency     string
 	ProductTypes []string
 	Platforms    []string
 	DateRange    *DateRange
+	Expression   *Expression // Parsed --filter expression
 }
 
 // DateRange represents a date range
@@ -180,6 +238,11 @@ type DateRange struct {
 	End   time.Time
 }
 
+// Days returns the number of days in the range, counting both ends
+func (d DateRange) Days() int {
+	return int(startOfDay(d.End).Sub(startOfDay(d.Start)).Hours()/24) + 1
+}
+
 // Validate checks if the date range is valid
 func (d *DateRange) Validate() error {
 	if d.Start.After(d.End) {
@@ -0,0 +1,108 @@
+// Package jsonapi decodes the JSON:API documents returned by the App Store
+// Connect API into typed resources.
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Document is a top-level JSON:API document. T is the type of the primary
+// data, usually Resource[A] for a single resource or []Resource[A] for a
+// collection.
+type Document[T any] struct {
+	Data     T             `json:"data"`
+	Included []RawResource `json:"included,omitempty"`
+	Links    Links         `json:"links,omitempty"`
+	Meta     Meta          `json:"meta,omitempty"`
+}
+
+// Payload is the body of a create or update request
+type Payload[A any] struct {
+	Data Resource[A] `json:"data"`
+}
+
+// Links are the top-level links of a document
+type Links struct {
+	Self  string `json:"self,omitempty"`
+	First string `json:"first,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// Meta holds document metadata
+type Meta struct {
+	Paging Paging `json:"paging,omitempty"`
+}
+
+// Paging describes the position of a page in a collection
+type Paging struct {
+	Total int `json:"total,omitempty"`
+	Limit int `json:"limit,omitempty"`
+}
+
+// Index returns an index of the included resources for resolving
+// relationships
+func (d *Document[T]) Index() Index {
+	return NewIndex(d.Included)
+}
+
+// Decode reads a document from r
+func Decode[T any](r io.Reader) (*Document[T], error) {
+	var doc Document[T]
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding JSON:API document: %w", err)
+	}
+	return &doc, nil
+}
+
+// DecodeResponse reads a document from a successful response, or returns the
+// API's error when the status isn't 2xx. The body is always closed.
+func DecodeResponse[T any](resp *http.Response) (*Document[T], error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, ReadError(resp)
+	}
+	return Decode[T](resp.Body)
+}
+
+// ErrorDocument is the body of a failed request
+type ErrorDocument struct {
+	Errors []Error `json:"errors"`
+}
+
+// Error is a single error object
+type Error struct {
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (e Error) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Detail)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Title)
+}
+
+// ReadError turns a failed response into an error, using the first error
+// object in the body when there is one
+func ReadError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	var doc ErrorDocument
+	if err := json.Unmarshal(body, &doc); err == nil && len(doc.Errors) > 0 {
+		return fmt.Errorf("API error (status %d): %w", resp.StatusCode, doc.Errors[0])
+	}
+	if text := strings.TrimSpace(string(body)); text != "" && len(text) < 200 {
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, text)
+	}
+	return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
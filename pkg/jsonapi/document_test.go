@@ -0,0 +1,203 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type appAttributes struct {
+	Name     string `json:"name"`
+	BundleID string `json:"bundleId"`
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Document[[]Resource[appAttributes]]
+		err  string
+	}{
+		{
+			name: "collection",
+			body: `{"data": [
+				{"type": "apps", "id": "1", "attributes": {"name": "Foo", "bundleId": "com.foo"}},
+				{"type": "apps", "id": "2", "attributes": {"name": "Bar", "bundleId": "com.bar"}}
+			]}`,
+			want: Document[[]Resource[appAttributes]]{Data: []Resource[appAttributes]{
+				{Type: "apps", ID: "1", Attributes: appAttributes{Name: "Foo", BundleID: "com.foo"}},
+				{Type: "apps", ID: "2", Attributes: appAttributes{Name: "Bar", BundleID: "com.bar"}},
+			}},
+		},
+		{
+			name: "first page",
+			body: `{
+				"data": [{"type": "apps", "id": "1", "attributes": {"name": "Foo"}}],
+				"links": {"self": "https://api/v1/apps?limit=1", "next": "https://api/v1/apps?cursor=Mg&limit=1"},
+				"meta": {"paging": {"total": 2, "limit": 1}}
+			}`,
+			want: Document[[]Resource[appAttributes]]{
+				Data:  []Resource[appAttributes]{{Type: "apps", ID: "1", Attributes: appAttributes{Name: "Foo"}}},
+				Links: Links{Self: "https://api/v1/apps?limit=1", Next: "https://api/v1/apps?cursor=Mg&limit=1"},
+				Meta:  Meta{Paging: Paging{Total: 2, Limit: 1}},
+			},
+		},
+		{
+			name: "last page",
+			body: `{
+				"data": [{"type": "apps", "id": "2", "attributes": {"name": "Bar"}}],
+				"links": {"self": "https://api/v1/apps?cursor=Mg&limit=1"},
+				"meta": {"paging": {"total": 2, "limit": 1}}
+			}`,
+			want: Document[[]Resource[appAttributes]]{
+				Data:  []Resource[appAttributes]{{Type: "apps", ID: "2", Attributes: appAttributes{Name: "Bar"}}},
+				Links: Links{Self: "https://api/v1/apps?cursor=Mg&limit=1"},
+				Meta:  Meta{Paging: Paging{Total: 2, Limit: 1}},
+			},
+		},
+		{
+			name: "empty collection",
+			body: `{"data": [], "links": {"self": "https://api/v1/apps"}}`,
+			want: Document[[]Resource[appAttributes]]{
+				Data:  []Resource[appAttributes]{},
+				Links: Links{Self: "https://api/v1/apps"},
+			},
+		},
+		{
+			name: "malformed",
+			body: `{"data": [`,
+			err:  "decoding JSON:API document",
+		},
+		{
+			name: "wrong shape",
+			body: `{"data": {"type": "apps", "id": "1"}}`,
+			err:  "decoding JSON:API document",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Decode[[]Resource[appAttributes]](strings.NewReader(tt.body))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Decode error = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(*doc, tt.want) {
+				t.Errorf("Decode = %+v, want %+v", *doc, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string // Decoded app name
+		err    string
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `{"data": {"type": "apps", "id": "1", "attributes": {"name": "Foo"}}}`,
+			want:   "Foo",
+		},
+		{
+			name:   "created",
+			status: http.StatusCreated,
+			body:   `{"data": {"type": "apps", "id": "1", "attributes": {"name": "Foo"}}}`,
+			want:   "Foo",
+		},
+		{
+			name:   "error document",
+			status: http.StatusNotFound,
+			body:   `{"errors": [{"status": "404", "code": "NOT_FOUND", "title": "Not found", "detail": "There is no app with id 1"}]}`,
+			err:    "API error (status 404): NOT_FOUND: There is no app with id 1",
+		},
+		{
+			name:   "error without detail",
+			status: http.StatusForbidden,
+			body:   `{"errors": [{"code": "FORBIDDEN_ERROR", "title": "Not allowed"}]}`,
+			err:    "API error (status 403): FORBIDDEN_ERROR: Not allowed",
+		},
+		{
+			name:   "plain text error",
+			status: http.StatusBadGateway,
+			body:   "upstream unavailable\n",
+			err:    "unexpected status code: 502: upstream unavailable",
+		},
+		{
+			name:   "empty error",
+			status: http.StatusInternalServerError,
+			err:    "unexpected status code: 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &closeRecorder{Reader: strings.NewReader(tt.body)}
+			resp := &http.Response{StatusCode: tt.status, Body: body}
+
+			doc, err := DecodeResponse[Resource[appAttributes]](resp)
+			if !body.closed {
+				t.Error("response body wasn't closed")
+			}
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("DecodeResponse error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeResponse: %v", err)
+			}
+			if doc.Data.Attributes.Name != tt.want {
+				t.Errorf("name = %q, want %q", doc.Data.Attributes.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadEncode(t *testing.T) {
+	type responseAttributes struct {
+		ResponseBody string `json:"responseBody"`
+	}
+
+	payload := Payload[responseAttributes]{Data: Resource[responseAttributes]{
+		Type:       "customerReviewResponses",
+		Attributes: responseAttributes{ResponseBody: "Thanks!"},
+		Relationships: map[string]Relationship{
+			"review": ToOne("customerReviews", "42"),
+		},
+	}}
+
+	got, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"data":{"type":"customerReviewResponses","attributes":{"responseBody":"Thanks!"},` +
+		`"relationships":{"review":{"data":{"type":"customerReviews","id":"42"}}}}}`
+	if string(got) != want {
+		t.Errorf("Marshal =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// closeRecorder is a response body that records being closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Query builds the query string of a JSON:API request
+type Query struct {
+	values url.Values
+}
+
+// NewQuery creates an empty query
+func NewQuery() *Query {
+	return &Query{values: url.Values{}}
+}
+
+// Fields requests a sparse fieldset: only the given fields of a resource type
+func (q *Query) Fields(resourceType string, fields ...string) *Query {
+	q.values.Set("fields["+resourceType+"]", strings.Join(fields, ","))
+	return q
+}
+
+// Include adds relationships to include in the response as a compound
+// document. Nested paths use dots, e.g. "appStoreVersions.build".
+func (q *Query) Include(relationships ...string) *Query {
+	existing := q.values.Get("include")
+	if existing != "" {
+		relationships = append(strings.Split(existing, ","), relationships...)
+	}
+	q.values.Set("include", strings.Join(relationships, ","))
+	return q
+}
+
+// Filter restricts results to resources whose field matches any of the values
+func (q *Query) Filter(field string, values ...string) *Query {
+	q.values.Set("filter["+field+"]", strings.Join(values, ","))
+	return q
+}
+
+// Sort orders results by the given fields; prefix a field with - to reverse it
+func (q *Query) Sort(fields ...string) *Query {
+	q.values.Set("sort", strings.Join(fields, ","))
+	return q
+}
+
+// Limit sets the page size
+func (q *Query) Limit(n int) *Query {
+	q.values.Set("limit", strconv.Itoa(n))
+	return q
+}
+
+// LimitIncluded sets how many related resources to include per relationship
+func (q *Query) LimitIncluded(relationship string, n int) *Query {
+	q.values.Set("limit["+relationship+"]", strconv.Itoa(n))
+	return q
+}
+
+// Set sets any other parameter, e.g. exists[publishedResponse]
+func (q *Query) Set(key, value string) *Query {
+	q.values.Set(key, value)
+	return q
+}
+
+// Values returns the underlying parameters. Changes to them are reflected in
+// the query.
+func (q *Query) Values() url.Values {
+	return q.values
+}
+
+// Encode returns the query string
+func (q *Query) Encode() string {
+	return q.values.Encode()
+}
@@ -0,0 +1,57 @@
+package jsonapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  string
+	}{
+		{"empty", NewQuery(), ""},
+		{"page size", NewQuery().Limit(200), "limit=200"},
+		{
+			name:  "fields and filters",
+			query: NewQuery().Fields("apps", "name", "bundleId").Filter("bundleId", "com.foo", "com.bar"),
+			want:  "fields[apps]=name,bundleId&filter[bundleId]=com.foo,com.bar",
+		},
+		{
+			name:  "includes accumulate",
+			query: NewQuery().Include("appStoreVersions").Include("builds", "appInfos"),
+			want:  "include=appStoreVersions,builds,appInfos",
+		},
+		{
+			name:  "sort and included limit",
+			query: NewQuery().Sort("-createdDate", "rating").LimitIncluded("response", 1),
+			want:  "limit[response]=1&sort=-createdDate,rating",
+		},
+		{
+			name:  "later values replace earlier ones",
+			query: NewQuery().Limit(50).Limit(100).Set("exists[publishedResponse]", "false"),
+			want:  "exists[publishedResponse]=false&limit=100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := url.QueryUnescape(tt.query.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Encode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryValues(t *testing.T) {
+	query := NewQuery().Limit(10)
+	query.Values().Set("cursor", "Mg")
+	if got := query.Encode(); got != "cursor=Mg&limit=10" {
+		t.Errorf("Encode = %q, want changes to Values reflected", got)
+	}
+}
@@ -0,0 +1,176 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Identifier names a resource by type and ID
+type Identifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Resource is a resource object whose attributes decode into A
+type Resource[A any] struct {
+	Type          string                  `json:"type"`
+	ID            string                  `json:"id,omitempty"`
+	Attributes    A                       `json:"attributes"`
+	Relationships map[string]Relationship `json:"relationships,omitempty"`
+	Links         *ResourceLinks          `json:"links,omitempty"`
+}
+
+// RawResource is a resource whose attributes haven't been decoded yet, as
+// found in the included section of compound documents
+type RawResource = Resource[json.RawMessage]
+
+// ResourceLinks are the links of a resource or relationship
+type ResourceLinks struct {
+	Self    string `json:"self,omitempty"`
+	Related string `json:"related,omitempty"`
+}
+
+// Identifier returns the resource's type and ID
+func (r Resource[A]) Identifier() Identifier {
+	return Identifier{Type: r.Type, ID: r.ID}
+}
+
+// Relationship returns the named relationship, or an empty one
+func (r Resource[A]) Relationship(name string) Relationship {
+	return r.Relationships[name]
+}
+
+// Relationship links a resource to others. Data is only present when the
+// relationship was requested with include or the API always sends it.
+type Relationship struct {
+	Data  *Linkage       `json:"data,omitempty"`
+	Links *ResourceLinks `json:"links,omitempty"`
+}
+
+// ToOne creates a to-one relationship, e.g. for request bodies
+func ToOne(typ, id string) Relationship {
+	return Relationship{Data: &Linkage{IDs: []Identifier{{Type: typ, ID: id}}}}
+}
+
+// ToMany creates a to-many relationship
+func ToMany(ids ...Identifier) Relationship {
+	return Relationship{Data: &Linkage{IDs: ids, Many: true}}
+}
+
+// One returns the related resource of a to-one relationship
+func (r Relationship) One() (Identifier, bool) {
+	if r.Data == nil || r.Data.Many || len(r.Data.IDs) == 0 {
+		return Identifier{}, false
+	}
+	return r.Data.IDs[0], true
+}
+
+// Many returns the related resources of a relationship
+func (r Relationship) Many() []Identifier {
+	if r.Data == nil {
+		return nil
+	}
+	return r.Data.IDs
+}
+
+// Linkage is the data of a relationship: null, one identifier or a list
+type Linkage struct {
+	IDs  []Identifier
+	Many bool
+}
+
+// UnmarshalJSON accepts null, an identifier object or an array of them
+func (l *Linkage) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*l = Linkage{}
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		l.Many = true
+		return json.Unmarshal(data, &l.IDs)
+	default:
+		var id Identifier
+		if err := json.Unmarshal(data, &id); err != nil {
+			return err
+		}
+		*l = Linkage{IDs: []Identifier{id}}
+		return nil
+	}
+}
+
+// MarshalJSON writes the linkage in the shape it was read
+func (l Linkage) MarshalJSON() ([]byte, error) {
+	switch {
+	case l.Many:
+		if l.IDs == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(l.IDs)
+	case len(l.IDs) == 0:
+		return []byte("null"), nil
+	default:
+		return json.Marshal(l.IDs[0])
+	}
+}
+
+// Index looks up included resources by identifier
+type Index map[Identifier]RawResource
+
+// NewIndex indexes the given resources
+func NewIndex(resources []RawResource) Index {
+	index := make(Index, len(resources))
+	for _, resource := range resources {
+		index[resource.Identifier()] = resource
+	}
+	return index
+}
+
+// Lookup decodes the included resource with the given identifier. It returns
+// nil if the document didn't include it.
+func Lookup[A any](index Index, id Identifier) (*Resource[A], error) {
+	raw, ok := index[id]
+	if !ok {
+		return nil, nil
+	}
+
+	resource := Resource[A]{
+		Type:          raw.Type,
+		ID:            raw.ID,
+		Relationships: raw.Relationships,
+		Links:         raw.Links,
+	}
+	if len(raw.Attributes) > 0 {
+		if err := json.Unmarshal(raw.Attributes, &resource.Attributes); err != nil {
+			return nil, fmt.Errorf("decoding included %s %s: %w", raw.Type, raw.ID, err)
+		}
+	}
+	return &resource, nil
+}
+
+// ResolveOne decodes the included target of a to-one relationship. It returns
+// nil if the relationship is empty or its target wasn't included.
+func ResolveOne[A any](index Index, rel Relationship) (*Resource[A], error) {
+	id, ok := rel.One()
+	if !ok {
+		return nil, nil
+	}
+	return Lookup[A](index, id)
+}
+
+// ResolveMany decodes the included targets of a relationship, skipping any
+// that weren't included
+func ResolveMany[A any](index Index, rel Relationship) ([]Resource[A], error) {
+	var resources []Resource[A]
+	for _, id := range rel.Many() {
+		resource, err := Lookup[A](index, id)
+		if err != nil {
+			return nil, err
+		}
+		if resource != nil {
+			resources = append(resources, *resource)
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,154 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinkage(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Linkage
+		one  bool
+	}{
+		{"null", `null`, Linkage{}, false},
+		{"to-one", `{"type":"builds","id":"1"}`, Linkage{IDs: []Identifier{{"builds", "1"}}}, true},
+		{"to-many", `[{"type":"builds","id":"1"},{"type":"builds","id":"2"}]`,
+			Linkage{IDs: []Identifier{{"builds", "1"}, {"builds", "2"}}, Many: true}, false},
+		{"empty to-many", `[]`, Linkage{IDs: []Identifier{}, Many: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rel Relationship
+			if err := json.Unmarshal([]byte(`{"data":`+tt.json+`}`), &rel); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if rel.Data == nil {
+				// A null linkage decodes to no data at all
+				rel.Data = &Linkage{}
+			}
+			if !reflect.DeepEqual(*rel.Data, tt.want) {
+				t.Errorf("linkage = %+v, want %+v", *rel.Data, tt.want)
+			}
+			if _, ok := rel.One(); ok != tt.one {
+				t.Errorf("One() ok = %v, want %v", ok, tt.one)
+			}
+			if !reflect.DeepEqual(rel.Many(), tt.want.IDs) {
+				t.Errorf("Many() = %v, want %v", rel.Many(), tt.want.IDs)
+			}
+
+			// Linkages are written back in the shape they were read
+			encoded, err := json.Marshal(tt.want)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(encoded) != tt.json {
+				t.Errorf("Marshal = %s, want %s", encoded, tt.json)
+			}
+		})
+	}
+}
+
+func TestLinkageErrors(t *testing.T) {
+	for _, body := range []string{`"builds"`, `[1, 2]`, `{"type": 1}`} {
+		var linkage Linkage
+		if err := json.Unmarshal([]byte(body), &linkage); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want an error", body)
+		}
+	}
+}
+
+func TestToMany(t *testing.T) {
+	encoded, err := json.Marshal(ToMany())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"data":[]}`; string(encoded) != want {
+		t.Errorf("empty ToMany = %s, want %s", encoded, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	type versionAttributes struct {
+		VersionString string `json:"versionString"`
+	}
+
+	body := `{
+		"data": {
+			"type": "apps", "id": "1", "attributes": {"name": "Foo"},
+			"relationships": {
+				"appStoreVersions": {"data": [
+					{"type": "appStoreVersions", "id": "10"},
+					{"type": "appStoreVersions", "id": "11"},
+					{"type": "appStoreVersions", "id": "12"}
+				]},
+				"latest": {"data": {"type": "appStoreVersions", "id": "11"}},
+				"missing": {"data": {"type": "appStoreVersions", "id": "99"}},
+				"empty": {"data": null},
+				"broken": {"data": {"type": "builds", "id": "5"}}
+			}
+		},
+		"included": [
+			{"type": "appStoreVersions", "id": "10", "attributes": {"versionString": "1.0"}},
+			{"type": "appStoreVersions", "id": "11", "attributes": {"versionString": "1.1"}},
+			{"type": "builds", "id": "5", "attributes": {"versionString": 5}}
+		]
+	}`
+
+	doc, err := Decode[Resource[appAttributes]](strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := doc.Index()
+	app := doc.Data
+
+	tests := []struct {
+		relationship string
+		want         string // Version string, or "" for no resource
+		err          string
+	}{
+		{"latest", "1.1", ""},
+		{"missing", "", ""},
+		{"empty", "", ""},
+		{"unknown", "", ""},
+		{"broken", "", "decoding included builds 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.relationship, func(t *testing.T) {
+			version, err := ResolveOne[versionAttributes](index, app.Relationship(tt.relationship))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ResolveOne error = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if version != nil {
+				got = version.Attributes.VersionString
+			}
+			if got != tt.want {
+				t.Errorf("ResolveOne = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Versions that weren't included are skipped
+	versions, err := ResolveMany[versionAttributes](index, app.Relationship("appStoreVersions"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, version := range versions {
+		got = append(got, version.ID+"="+version.Attributes.VersionString)
+	}
+	if want := []string{"10=1.0", "11=1.1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveMany = %v, want %v", got, want)
+	}
+}
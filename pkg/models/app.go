@@ -0,0 +1,40 @@
+package models
+
+import "github.com/marcusziade/pomme/pkg/jsonapi"
+
+// App represents an app resource
+type App = jsonapi.Resource[AppAttributes]
+
+// AppAttributes contains app details
+type AppAttributes struct {
+	Name                      string     `json:"name"`
+	BundleID                  string     `json:"bundleId"`
+	SKU                       string     `json:"sku"`
+	PrimaryLocale             string     `json:"primaryLocale"`
+	IsPreReleaseApp           bool       `json:"isPreReleaseApp"`
+	Prices                    []AppPrice `json:"prices,omitempty"`
+	AvailableInNewTerritories bool       `json:"availableInNewTerritories"`
+	ContentRightsDeclaration  string     `json:"contentRightsDeclaration,omitempty"`
+}
+
+// AppPrice represents a price for an app
+type AppPrice struct {
+	Territory string `json:"territory"`
+	Currency  string `json:"currency"`
+	Amount    string `json:"amount"`
+}
+
+// AppInfo represents additional information about an app
+type AppInfo = jsonapi.Resource[AppInfoAttributes]
+
+// AppInfoAttributes contains app info details
+type AppInfoAttributes struct {
+	AppStoreState                  string   `json:"appStoreState"`
+	AppStoreAgeRating              string   `json:"appStoreAgeRating"`
+	BrazilAgeRating                string   `json:"brazilAgeRating,omitempty"`
+	KidsAgeBand                    string   `json:"kidsAgeBand,omitempty"`
+	PrimaryCategory                string   `json:"primaryCategory"`
+	PrimaryCategorySubcategories   []string `json:"primaryCategorySubcategories,omitempty"`
+	SecondaryCategory              string   `json:"secondaryCategory,omitempty"`
+	SecondaryCategorySubcategories []string `json:"secondaryCategorySubcategories,omitempty"`
+}
@@ -1,39 +1,18 @@
 package models
 
-// ResourceLinks represents common link objects in API responses
-type ResourceLinks struct {
-	Self    string `json:"self,omitempty"`
-	Related string `json:"related,omitempty"`
-}
+import "github.com/marcusziade/pomme/pkg/jsonapi"
 
-// PagingInformation represents paging information in API responses
-type PagingInformation struct {
-	Next  string `json:"next,omitempty"`
-	Total int    `json:"total,omitempty"`
-}
+// ResourceLinks represents common link objects in API responses
+type ResourceLinks = jsonapi.ResourceLinks
 
 // Links represents links in API responses
-type Links struct {
-	Self  string `json:"self,omitempty"`
-	First string `json:"first,omitempty"`
-	Next  string `json:"next,omitempty"`
-	Prev  string `json:"prev,omitempty"`
-	Last  string `json:"last,omitempty"`
-}
+type Links = jsonapi.Links
 
 // ErrorResponse represents an API error response
-type ErrorResponse struct {
-	Errors []APIError `json:"errors"`
-}
+type ErrorResponse = jsonapi.ErrorDocument
 
 // APIError represents an individual error in an API response
-type APIError struct {
-	ID     string `json:"id,omitempty"`
-	Status string `json:"status,omitempty"`
-	Code   string `json:"code,omitempty"`
-	Title  string `json:"title,omitempty"`
-	Detail string `json:"detail,omitempty"`
-}
+type APIError = jsonapi.Error
 
 // PlatformType represents app platform types
 type PlatformType string
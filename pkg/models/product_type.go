@@ -0,0 +1,161 @@
+package models
+
+import "strings"
+
+// ProductCategory groups Apple product types by what the customer got
+type ProductCategory string
+
+const (
+	ProductDownload     ProductCategory = "download"
+	ProductUpdate       ProductCategory = "update"
+	ProductRedownload   ProductCategory = "redownload"
+	ProductIAP          ProductCategory = "iap"
+	ProductSubscription ProductCategory = "subscription"
+	ProductOther        ProductCategory = "other"
+)
+
+// ProductCategories lists every category in display order
+var ProductCategories = []ProductCategory{
+	ProductDownload,
+	ProductUpdate,
+	ProductRedownload,
+	ProductIAP,
+	ProductSubscription,
+	ProductOther,
+}
+
+// Label returns the category's display name
+func (c ProductCategory) Label() string {
+	switch c {
+	case ProductDownload:
+		return "New downloads"
+	case ProductUpdate:
+		return "Updates"
+	case ProductRedownload:
+		return "Redownloads"
+	case ProductIAP:
+		return "In-app purchases"
+	case ProductSubscription:
+		return "Subscriptions"
+	default:
+		return "Other"
+	}
+}
+
+// ProductType describes one of Apple's product type identifiers
+type ProductType struct {
+	ID       string
+	Name     string
+	Category ProductCategory
+}
+
+// productTypes is Apple's product type identifier catalog. The -B suffix
+// marks app bundles and -M Mac in-app purchases.
+var productTypes = map[string]ProductType{
+	"1":     {Name: "iPhone app", Category: ProductDownload},
+	"1F":    {Name: "Universal app", Category: ProductDownload},
+	"1T":    {Name: "iPad app", Category: ProductDownload},
+	"1E":    {Name: "Custom iPhone app", Category: ProductDownload},
+	"1EP":   {Name: "Custom iPad app", Category: ProductDownload},
+	"1EU":   {Name: "Custom universal app", Category: ProductDownload},
+	"1-B":   {Name: "App bundle", Category: ProductDownload},
+	"F1":    {Name: "Mac app", Category: ProductDownload},
+	"F1-B":  {Name: "Mac app bundle", Category: ProductDownload},
+	"7":     {Name: "iPhone app update", Category: ProductUpdate},
+	"7F":    {Name: "Universal app update", Category: ProductUpdate},
+	"7T":    {Name: "iPad app update", Category: ProductUpdate},
+	"F7":    {Name: "Mac app update", Category: ProductUpdate},
+	"3":     {Name: "iPhone app redownload", Category: ProductRedownload},
+	"3F":    {Name: "Universal app redownload", Category: ProductRedownload},
+	"3T":    {Name: "iPad app redownload", Category: ProductRedownload},
+	"F3":    {Name: "Mac app redownload", Category: ProductRedownload},
+	"IA1":   {Name: "In-app purchase", Category: ProductIAP},
+	"IA1-M": {Name: "Mac in-app purchase", Category: ProductIAP},
+	"FI1":   {Name: "Mac in-app purchase", Category: ProductIAP},
+	"IA9":   {Name: "Non-renewing subscription", Category: ProductSubscription},
+	"IA9-M": {Name: "Mac non-renewing subscription", Category: ProductSubscription},
+	"IAY":   {Name: "Auto-renewable subscription", Category: ProductSubscription},
+	"IAY-M": {Name: "Mac auto-renewable subscription", Category: ProductSubscription},
+	"IAC":   {Name: "Free subscription", Category: ProductSubscription},
+}
+
+// LookupProductType describes a product type identifier. Unknown identifiers
+// keep their identifier as name; those starting with IA are counted as
+// in-app purchases and the rest as other.
+func LookupProductType(id string) ProductType {
+	key := strings.ToUpper(strings.TrimSpace(id))
+	if productType, ok := productTypes[key]; ok {
+		productType.ID = key
+		return productType
+	}
+
+	productType := ProductType{ID: key, Name: key, Category: ProductOther}
+	if key == "" {
+		productType.Name = "Unknown"
+	} else if strings.HasPrefix(key, "IA") {
+		productType.Category = ProductIAP
+	}
+	return productType
+}
+
+// ProductCategory returns the category of the sale's product type
+func (s Sale) ProductCategory() ProductCategory {
+	return LookupProductType(s.ProductType).Category
+}
+
+// UnitBreakdown splits units by product category
+type UnitBreakdown struct {
+	Downloads     int // New downloads, free or paid
+	Updates       int
+	Redownloads   int
+	IAPs          int // In-app purchases, not counting subscriptions
+	Subscriptions int
+	Other         int
+	RevenueUnits  int // Units that earned proceeds, of any category
+}
+
+// Add counts a sale's units
+func (b *UnitBreakdown) Add(sale Sale) {
+	*b.units(sale.ProductCategory()) += sale.Units
+	if sale.DeveloperProceeds.Amount != 0 {
+		b.RevenueUnits += sale.Units
+	}
+}
+
+// Merge adds another breakdown's units
+func (b *UnitBreakdown) Merge(other UnitBreakdown) {
+	b.Downloads += other.Downloads
+	b.Updates += other.Updates
+	b.Redownloads += other.Redownloads
+	b.IAPs += other.IAPs
+	b.Subscriptions += other.Subscriptions
+	b.Other += other.Other
+	b.RevenueUnits += other.RevenueUnits
+}
+
+// Units returns the units of one category
+func (b UnitBreakdown) Units(category ProductCategory) int {
+	return *b.units(category)
+}
+
+// InAppUnits returns in-app purchase and subscription units
+func (b UnitBreakdown) InAppUnits() int {
+	return b.IAPs + b.Subscriptions
+}
+
+func (b *UnitBreakdown) units(category ProductCategory) *int {
+	switch category {
+	case ProductDownload:
+		return &b.Downloads
+	case ProductUpdate:
+		return &b.Updates
+	case ProductRedownload:
+		return &b.Redownloads
+	case ProductIAP:
+		return &b.IAPs
+	case ProductSubscription:
+		return &b.Subscriptions
+	default:
+		return &b.Other
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import "math"
+
+// IsReturn reports whether the sale is a return. Apple reports returns as
+// rows with negative units.
+func (s Sale) IsReturn() bool {
+	return s.Units < 0
+}
+
+// Proceeds returns the sale's proceeds: Developer Proceeds is per unit, so
+// it's multiplied by the units. Returns are negative whether Apple reported
+// the units, the per-unit proceeds or both as negative.
+func (s Sale) Proceeds() float64 {
+	total := float64(s.Units) * s.DeveloperProceeds.Amount
+	if s.IsReturn() {
+		return -math.Abs(total)
+	}
+	return total
+}
+
+// Refunds separates gross sales from returns. Net units and proceeds are the
+// gross ones less what was returned. Only rows with proceeds count: free
+// downloads, updates and redownloads can't be refunded and would dilute the
+// refund rate.
+type Refunds struct {
+	GrossUnits       int                // Paid units sold, not counting returns
+	ReturnedUnits    int                // Paid units returned, as a positive count
+	GrossProceeds    map[string]float64 // Currency -> Proceeds before refunds
+	RefundedProceeds map[string]float64 // Currency -> Proceeds refunded, as positive amounts
+}
+
+// Add counts a paid sale as sold or returned, and skips free ones
+func (r *Refunds) Add(sale Sale) {
+	if r.GrossProceeds == nil {
+		r.GrossProceeds = make(map[string]float64)
+		r.RefundedProceeds = make(map[string]float64)
+	}
+
+	proceeds := sale.Proceeds()
+	if proceeds == 0 {
+		return
+	}
+	if sale.IsReturn() {
+		r.ReturnedUnits -= sale.Units
+		r.RefundedProceeds[sale.DeveloperProceeds.Currency] -= proceeds
+		return
+	}
+
+	r.GrossUnits += sale.Units
+	r.GrossProceeds[sale.DeveloperProceeds.Currency] += proceeds
+}
+
+// Merge adds another sales total's gross sales and returns
+func (r *Refunds) Merge(other Refunds) {
+	if r.GrossProceeds == nil {
+		r.GrossProceeds = make(map[string]float64)
+		r.RefundedProceeds = make(map[string]float64)
+	}
+
+	r.GrossUnits += other.GrossUnits
+	r.ReturnedUnits += other.ReturnedUnits
+	for currency, amount := range other.GrossProceeds {
+		r.GrossProceeds[currency] += amount
+	}
+	for currency, amount := range other.RefundedProceeds {
+		r.RefundedProceeds[currency] += amount
+	}
+}
+
+// Rate returns the percentage of paid units that were returned
+func (r Refunds) Rate() float64 {
+	if r.GrossUnits == 0 {
+		return 0
+	}
+	return float64(r.ReturnedUnits) / float64(r.GrossUnits) * 100
+}
+
+// NetUnits returns the paid units sold less those returned
+func (r Refunds) NetUnits() int {
+	return r.GrossUnits - r.ReturnedUnits
+}
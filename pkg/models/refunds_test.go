@@ -0,0 +1,117 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func usd(amount float64) Money {
+	return Money{Amount: amount, Currency: "USD"}
+}
+
+func TestSaleProceeds(t *testing.T) {
+	tests := []struct {
+		name string
+		sale Sale
+		want float64
+	}{
+		{"single unit", Sale{Units: 1, DeveloperProceeds: usd(0.75)}, 0.75},
+		{"multiple units", Sale{Units: 4, DeveloperProceeds: usd(0.75)}, 3},
+		{"free", Sale{Units: 25}, 0},
+		{"return", Sale{Units: -2, DeveloperProceeds: usd(0.75)}, -1.5},
+		{"return with negative proceeds", Sale{Units: -2, DeveloperProceeds: usd(-0.75)}, -1.5},
+		{"negative proceeds only", Sale{Units: 2, DeveloperProceeds: usd(-0.75)}, -1.5},
+		{"free return", Sale{Units: -1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sale.Proceeds(); got != tt.want {
+				t.Errorf("Proceeds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefundsAdd(t *testing.T) {
+	eur := func(amount float64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	tests := []struct {
+		name  string
+		sales []Sale
+		want  Refunds
+		rate  float64
+	}{
+		{
+			name:  "multi-unit sales and a return",
+			sales: []Sale{{Units: 3, DeveloperProceeds: usd(0.75)}, {Units: 1, DeveloperProceeds: usd(0.75)}, {Units: -2, DeveloperProceeds: usd(0.75)}},
+			want: Refunds{
+				GrossUnits:       4,
+				ReturnedUnits:    2,
+				GrossProceeds:    map[string]float64{"USD": 3},
+				RefundedProceeds: map[string]float64{"USD": 1.5},
+			},
+			rate: 50,
+		},
+		{
+			name:  "free rows don't count",
+			sales: []Sale{{Units: 96}, {Units: 4, DeveloperProceeds: usd(1.5)}, {Units: -1}},
+			want: Refunds{
+				GrossUnits:       4,
+				GrossProceeds:    map[string]float64{"USD": 6},
+				RefundedProceeds: map[string]float64{},
+			},
+			rate: 0,
+		},
+		{
+			name: "mixed currencies",
+			sales: []Sale{
+				{Units: 2, DeveloperProceeds: usd(0.75)},
+				{Units: 6, DeveloperProceeds: eur(0.5)},
+				{Units: -1, DeveloperProceeds: eur(-0.5)},
+				{Units: -1, DeveloperProceeds: usd(0.75)},
+			},
+			want: Refunds{
+				GrossUnits:       8,
+				ReturnedUnits:    2,
+				GrossProceeds:    map[string]float64{"USD": 1.5, "EUR": 3},
+				RefundedProceeds: map[string]float64{"USD": 0.75, "EUR": 0.5},
+			},
+			rate: 25,
+		},
+		{
+			name:  "only free rows",
+			sales: []Sale{{Units: 10}},
+			want:  Refunds{GrossProceeds: map[string]float64{}, RefundedProceeds: map[string]float64{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var refunds Refunds
+			for _, sale := range tt.sales {
+				refunds.Add(sale)
+			}
+			if !reflect.DeepEqual(refunds, tt.want) {
+				t.Errorf("Refunds = %+v, want %+v", refunds, tt.want)
+			}
+			if rate := refunds.Rate(); rate != tt.rate {
+				t.Errorf("Rate() = %v, want %v", rate, tt.rate)
+			}
+			if net, want := refunds.NetUnits(), tt.want.GrossUnits-tt.want.ReturnedUnits; net != want {
+				t.Errorf("NetUnits() = %d, want %d", net, want)
+			}
+
+			// Merging the totals of each sale gives the same result
+			var merged Refunds
+			for _, sale := range tt.sales {
+				var single Refunds
+				single.Add(sale)
+				merged.Merge(single)
+			}
+			if !reflect.DeepEqual(merged, tt.want) {
+				t.Errorf("merged Refunds = %+v, want %+v", merged, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "github.com/marcusziade/pomme/pkg/jsonapi"
+
+// Review represents a customer review resource
+type Review = jsonapi.Resource[ReviewAttributes]
+
+// ReviewAttributes contains customer review details
+type ReviewAttributes struct {
+	Rating            int                `json:"rating"`
+	Title             string             `json:"title"`
+	Body              string             `json:"body"`
+	ReviewerNickname  string             `json:"reviewerNickname"`
+	CreatedDate       string             `json:"createdDate"`
+	Territory         string             `json:"territory"`
+	PublishedResponse *DeveloperResponse `json:"publishedResponse,omitempty"`
+}
+
+// DeveloperResponse represents a developer response to a customer review
+type DeveloperResponse struct {
+	ID             string `json:"id,omitempty"`
+	ResponseBody   string `json:"responseBody"`
+	LastModifiedDate string `json:"lastModifiedDate"`
+	State          string `json:"state"`
+}
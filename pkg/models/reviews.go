@@ -0,0 +1,263 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusziade/pomme/pkg/jsonapi"
+)
+
+// CustomerReview represents a customer review from the App Store
+type CustomerReview struct {
+	ID         string                   `json:"id"`
+	Type       string                   `json:"type"`
+	Attributes CustomerReviewAttributes `json:"attributes"`
+	Links      *ResourceLinks           `json:"links,omitempty"`
+	Response   *CustomerReviewResponse  `json:"response,omitempty"` // Developer response, if any
+}
+
+// HasResponse reports whether the developer has responded to the review
+func (r CustomerReview) HasResponse() bool {
+	return r.Response != nil
+}
+
+// CustomerReviewAttributes contains review details
+type CustomerReviewAttributes struct {
+	Rating               int       `json:"rating"`
+	Title                string    `json:"title"`
+	Body                 string    `json:"body"`
+	ReviewerNickname     string    `json:"reviewerNickname"`
+	CreatedDate          time.Time `json:"createdDate"`
+	Territory            string    `json:"territory"`
+}
+
+// CustomerReviewResponse represents a developer response to a review
+type CustomerReviewResponse = jsonapi.Resource[CustomerReviewResponseAttributes]
+
+// CustomerReviewResponseAttributes contains response details
+type CustomerReviewResponseAttributes struct {
+	ResponseBody string    `json:"responseBody"`
+	ModifiedDate time.Time `json:"lastModifiedDate"`
+	State        string    `json:"state"` // PENDING_PUBLISH, PUBLISHED
+}
+
+// ReviewSummary provides aggregated review statistics
+type ReviewSummary struct {
+	AppID          string             `json:"appId"`
+	TotalReviews   int                `json:"totalReviews"`
+	AverageRating  float64            `json:"averageRating"`
+	RatingCounts   map[int]int        `json:"ratingCounts"`   // 1-5 star counts
+	TerritoryStats []TerritoryReviews `json:"territoryStats"`
+	RecentReviews  []CustomerReview   `json:"recentReviews"`
+}
+
+// TerritoryReviews contains review stats for a specific territory
+type TerritoryReviews struct {
+	Territory     string  `json:"territory"`
+	ReviewCount   int     `json:"reviewCount"`
+	AverageRating float64 `json:"averageRating"`
+}
+
+// ReviewFilter contains filtering options for reviews
+type ReviewFilter struct {
+	AppID       string    `json:"appId"`
+	Territory   string    `json:"territory,omitempty"`
+	Territories []string  `json:"territories,omitempty"`
+	Rating      int       `json:"rating,omitempty"`      // 1-5
+	MinRating   int       `json:"minRating,omitempty"`   // 1-5, inclusive
+	MaxRating   int       `json:"maxRating,omitempty"`   // 1-5, inclusive
+	HasResponse *bool     `json:"hasResponse,omitempty"` // nil means either
+	Text        string    `json:"text,omitempty"`        // Regular expression matched against title and body
+	Nickname    string    `json:"nickname,omitempty"`    // Case-insensitive substring of the reviewer nickname
+	StartDate   time.Time `json:"startDate,omitempty"`
+	EndDate     time.Time `json:"endDate,omitempty"`
+	Limit       int       `json:"limit,omitempty"`
+	Sort        string    `json:"sort,omitempty"`        // mostRecent, mostCritical, mostHelpful
+}
+
+// AllTerritories returns Territory and Territories combined, uppercased and deduplicated
+func (f ReviewFilter) AllTerritories() []string {
+	seen := make(map[string]bool)
+	var territories []string
+	for _, t := range append([]string{f.Territory}, f.Territories...) {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			territories = append(territories, t)
+		}
+	}
+	sort.Strings(territories)
+	return territories
+}
+
+// Validate checks that every rating bound is unset or between 1 and 5, and
+// that the minimum isn't above the maximum
+func (f ReviewFilter) Validate() error {
+	bounds := []struct {
+		name  string
+		value int
+	}{
+		{"rating", f.Rating},
+		{"minimum rating", f.MinRating},
+		{"maximum rating", f.MaxRating},
+	}
+	for _, bound := range bounds {
+		if bound.value != 0 && (bound.value < 1 || bound.value > 5) {
+			return fmt.Errorf("%s %d is outside 1-5", bound.name, bound.value)
+		}
+	}
+	if f.MinRating > 0 && f.MaxRating > 0 && f.MinRating > f.MaxRating {
+		return fmt.Errorf("minimum rating %d is above maximum rating %d", f.MinRating, f.MaxRating)
+	}
+	return nil
+}
+
+// Ratings returns the star ratings the filter accepts, or nil for all of them
+func (f ReviewFilter) Ratings() []int {
+	if f.Rating > 0 {
+		return []int{f.Rating}
+	}
+	if f.MinRating <= 1 && (f.MaxRating == 0 || f.MaxRating >= 5) {
+		return nil
+	}
+
+	min, max := f.MinRating, f.MaxRating
+	if min < 1 {
+		min = 1
+	}
+	if max == 0 || max > 5 {
+		max = 5
+	}
+
+	ratings := []int{}
+	for r := min; r <= max; r++ {
+		ratings = append(ratings, r)
+	}
+	return ratings
+}
+
+// CacheKey generates a unique cache key covering every filter field
+func (f ReviewFilter) CacheKey() string {
+	hasResponse := ""
+	if f.HasResponse != nil {
+		hasResponse = strconv.FormatBool(*f.HasResponse)
+	}
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("reviews:%s:%s:%v:%s:%q:%q:%s:%s:%d:%s",
+		f.AppID,
+		strings.Join(f.AllTerritories(), ","),
+		f.Ratings(),
+		hasResponse,
+		f.Text,
+		strings.ToLower(f.Nickname),
+		formatTime(f.StartDate),
+		formatTime(f.EndDate),
+		f.Limit,
+		f.Sort,
+	)
+}
+
+// AppStoreVersion represents a released App Store version of an app
+type AppStoreVersion struct {
+	ID          string    `json:"id"`
+	Version     string    `json:"version"`
+	Platform    string    `json:"platform"`
+	State       string    `json:"state"`
+	ReleaseDate time.Time `json:"releaseDate"`
+}
+
+// VersionReviewStats contains review statistics for a single version's release window
+type VersionReviewStats struct {
+	Version         string        `json:"version"`
+	ReleaseDate     time.Time     `json:"releaseDate"`
+	WindowEnd       time.Time     `json:"windowEnd,omitempty"` // Zero for the current version
+	ReviewCount     int           `json:"reviewCount"`
+	AverageRating   float64       `json:"averageRating"`
+	RatingCounts    map[int]int   `json:"ratingCounts"`
+	TopTopics       []ReviewTopic `json:"topTopics"`
+	RatingChange    float64       `json:"ratingChange"` // Versus the previous version
+	PValue          float64       `json:"pValue,omitempty"`
+	SignificantDrop bool          `json:"significantDrop"`
+}
+
+// ReviewTopic is a frequently mentioned term in a set of reviews
+type ReviewTopic struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"` // Number of reviews mentioning the term
+}
+
+// AppReviewStats is one row of the portfolio-wide review dashboard
+type AppReviewStats struct {
+	AppID                string  `json:"appId"`
+	Name                 string  `json:"name"`
+	BundleID             string  `json:"bundleId"`
+	AverageRating        float64 `json:"averageRating"`
+	Reviews              int     `json:"reviews"`
+	Last7Days            int     `json:"last7Days"`
+	Last30Days           int     `json:"last30Days"`
+	Unanswered           int     `json:"unanswered"`
+	WorstTerritory       string  `json:"worstTerritory,omitempty"`
+	WorstTerritoryRating float64 `json:"worstTerritoryRating,omitempty"`
+	Error                string  `json:"error,omitempty"`
+}
+
+// PriorityFactors are the normalized (0-1) inputs to a review's triage score
+type PriorityFactors struct {
+	Rating     float64 `json:"rating"`     // 1 for one star, 0 for five
+	Recency    float64 `json:"recency"`    // Halves every half-life
+	Length     float64 `json:"length"`     // Longer reviews explain more
+	Revenue    float64 `json:"revenue"`    // Territory's share of sales, relative to the top territory
+	Unanswered float64 `json:"unanswered"` // 1 if the review has no response
+}
+
+// PrioritizedReview is a review with its triage score
+type PrioritizedReview struct {
+	Review  CustomerReview  `json:"review"`
+	Score   float64         `json:"score"` // 0-100
+	Factors PriorityFactors `json:"factors"`
+}
+
+// ReviewExportRow is a flat view of a review and its response for exports
+type ReviewExportRow struct {
+	ID               string `json:"id"`
+	Date             string `json:"date"`
+	Rating           int    `json:"rating"`
+	Territory        string `json:"territory"`
+	Nickname         string `json:"nickname"`
+	Title            string `json:"title"`
+	Body             string `json:"body"`
+	Response         string `json:"response,omitempty"`
+	ResponseState    string `json:"responseState,omitempty"` // PENDING_PUBLISH, PUBLISHED
+	ResponseModified string `json:"responseModified,omitempty"`
+}
+
+// NewReviewExportRow flattens a review for export
+func NewReviewExportRow(review CustomerReview) ReviewExportRow {
+	row := ReviewExportRow{
+		ID:        review.ID,
+		Date:      review.Attributes.CreatedDate.Format(time.RFC3339),
+		Rating:    review.Attributes.Rating,
+		Territory: review.Attributes.Territory,
+		Nickname:  review.Attributes.ReviewerNickname,
+		Title:     review.Attributes.Title,
+		Body:      review.Attributes.Body,
+	}
+	if review.Response != nil {
+		row.Response = review.Response.Attributes.ResponseBody
+		row.ResponseState = review.Response.Attributes.State
+		if !review.Response.Attributes.ModifiedDate.IsZero() {
+			row.ResponseModified = review.Response.Attributes.ModifiedDate.Format(time.RFC3339)
+		}
+	}
+	return row
+}
@@ -23,6 +23,11 @@ type AppSales struct {
 	Icon        string // Optional app icon URL
 	Sales       []Sale
 	Summary     AppSummary
+
+	// Set by sales.NestApps: the app's in-app purchases and subscriptions,
+	// and totals for the app and its children together
+	Children []AppSales  `json:",omitempty"`
+	RollUp   *AppSummary `json:",omitempty"`
 }
 
 // Sale represents a single sales transaction
@@ -56,6 +61,8 @@ type AppSummary struct {
 	TopCountries   []CountrySales
 	PlatformSplit  map[string]int // Platform -> Units
 	DeviceSplit    map[string]int // Device -> Units
+	Breakdown      UnitBreakdown  // Units by product category
+	Refunds        Refunds        // Gross sales and returns; units and proceeds above are net
 }
 
 // CountrySales represents sales data for a specific country
@@ -64,6 +71,7 @@ type CountrySales struct {
 	CountryName string
 	Units     int
 	Proceeds  map[string]float64 // Currency -> Amount
+	Refunds   Refunds            // Gross sales and returns; units and proceeds above are net
 }
 
 // ReportSummary provides overall report statistics
@@ -72,6 +80,8 @@ type ReportSummary struct {
 	TotalUnits    int
 	TotalProceeds map[string]float64 // Currency -> Amount
 	TotalCountries int
+	Breakdown     UnitBreakdown // Units by product category
+	Refunds       Refunds       // Gross sales and returns; units and proceeds above are net
 	Period        string
 	TopApps       []AppRanking
 	TopCountries  []CountrySales
@@ -1,6 +1,19 @@
+// Package pomme is a Go client for the App Store Connect API.
+//
+// Create a client with New and use its service handles:
+//
+//	client, err := pomme.New(
+//		pomme.WithCredentials(keyID, issuerID, privateKeyPEM),
+//		pomme.WithVendorNumber("12345678"),
+//	)
+//	apps, err := client.Apps().List(ctx)
+//	report, err := client.Sales().Report(ctx, pomme.SalesReportRequest{Date: lastMonth})
+//
+// The services are interfaces, so code that uses them can be tested with fakes.
 package pomme
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -12,248 +25,296 @@ import (
 
 	"github.com/marcusziade/pomme/internal/api"
 	"github.com/marcusziade/pomme/internal/auth"
-	"github.com/marcusziade/pomme/internal/models"
+	"github.com/marcusziade/pomme/internal/client"
+	"github.com/marcusziade/pomme/internal/services/cache"
+	"github.com/marcusziade/pomme/pkg/jsonapi"
+	"github.com/marcusziade/pomme/pkg/models"
 )
 
 // Client is a high-level client for the App Store Connect API
 type Client struct {
-	apiClient *api.Client
-	keyID     string
-	issuerID  string
+	apiClient    *api.Client
+	vendorNumber string
+
+	apps    AppsService
+	reviews ReviewsService
+	sales   SalesService
+	finance FinanceService
 }
 
-// NewClient creates a new App Store Connect API client
-func NewClient(keyID, issuerID, privateKey string) *Client {
-	authConfig := auth.JWTConfig{
-		KeyID:         keyID,
-		IssuerID:      issuerID,
-		PrivateKeyPEM: privateKey,
-		Expiration:    20 * time.Minute,
+// New creates a client. Credentials are required, either through
+// WithCredentials or WithIndividualKey, or as a WithTokenProvider.
+func New(opts ...Option) (*Client, error) {
+	o := options{
+		baseURL: DefaultBaseURL,
+		retry:   DefaultRetryPolicy,
 	}
-	
-	apiClient := api.NewClient("https://api.appstoreconnect.apple.com", authConfig)
-	
-	return &Client{
-		apiClient: apiClient,
-		keyID:     keyID,
-		issuerID:  issuerID,
+	for _, opt := range opts {
+		opt(&o)
 	}
-}
 
-// GetSalesReport fetches a sales report from the App Store Connect API
-func (c *Client) GetSalesReport(ctx context.Context, frequency models.ReportFrequency, reportDate string, reportType models.ReportType, vendorNumber string) ([]byte, error) {
-	// Construct the report API URL
-	url := fmt.Sprintf("/v1/salesReports?filter[frequency]=%s&filter[reportDate]=%s&filter[reportSubType]=%s&filter[reportType]=%s&filter[vendorNumber]=%s",
-		frequency, reportDate, "SUMMARY", reportType, vendorNumber)
-	
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", c.apiClient.BaseURL+url, nil)
+	tokens, err := o.tokenProvider()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
-	
-	// Get auth token
-	token, err := c.apiClient.GetAuthToken()
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if o.httpClient != nil {
+		copied := *o.httpClient
+		httpClient = &copied
+	}
+	if o.retry.MaxAttempts > 1 {
+		httpClient.Transport = &api.RetryTransport{Base: httpClient.Transport, Policy: o.retry}
+	}
+	httpClient.Transport = &api.ReauthTransport{Base: httpClient.Transport, Tokens: tokens}
+	if o.logger != nil {
+		httpClient.Transport = &api.LoggingTransport{Base: httpClient.Transport, Logger: o.logger}
+	}
+
+	var store cache.Cache = o.cache
+	if o.cache == nil {
+		store = cache.NewMemoryCache()
+	}
+
+	c := &Client{
+		apiClient: &api.Client{
+			// Service paths carry their own /v1 prefix, so use the bare host
+			BaseURL:    strings.TrimSuffix(strings.TrimRight(o.baseURL, "/"), "/v1"),
+			HTTPClient: httpClient,
+			Tokens:     tokens,
+		},
+		vendorNumber: o.vendorNumber,
+	}
+	c.apps = &appsService{client: c}
+	c.reviews = newReviewsService(client.NewFromAPI(c.apiClient), store)
+	c.sales = newSalesService(c, store)
+	c.finance = &financeService{client: c}
+	return c, nil
+}
+
+// tokenProvider returns the configured provider, or one that signs tokens
+// with the configured key
+func (o options) tokenProvider() (TokenProvider, error) {
+	if o.tokens != nil {
+		return o.tokens, nil
+	}
+	if o.keyID == "" || o.privateKey == "" {
+		return nil, fmt.Errorf("pomme: no credentials: use WithCredentials, WithIndividualKey or WithTokenProvider")
+	}
+
+	config := auth.JWTConfig{
+		KeyID:         o.keyID,
+		IssuerID:      o.issuerID,
+		PrivateKeyPEM: o.privateKey,
+		Expiration:    o.tokenTTL,
+	}
+	if o.issuerID == "" {
+		config.KeyType = auth.KeyTypeIndividual
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("pomme: %w", err)
+	}
+	if _, err := auth.ParsePrivateKey(o.privateKey); err != nil {
+		return nil, fmt.Errorf("pomme: %w", err)
+	}
+	return auth.NewJWTProvider(config), nil
+}
+
+// NewClient creates a client with the default options. Pass an empty
+// issuerID for an individual API key.
+//
+// Deprecated: use New with WithCredentials, which reports invalid keys.
+func NewClient(keyID, issuerID, privateKey string) *Client {
+	c, err := New(WithCredentials(keyID, issuerID, privateKey))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get auth token: %w", err)
+		// Keep the old behavior of failing on first use
+		c, _ = New(WithTokenProvider(failingTokens{err}))
 	}
-	
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/a-gzip")
-	
-	// Execute the request
-	resp, err := c.apiClient.HTTPClient.Do(req)
+	return c
+}
+
+// failingTokens reports a configuration error on every request
+type failingTokens struct {
+	err error
+}
+
+func (f failingTokens) Token(ctx context.Context) (string, error) {
+	return "", f.err
+}
+
+// Apps returns the apps service
+func (c *Client) Apps() AppsService {
+	return c.apps
+}
+
+// Reviews returns the customer reviews service
+func (c *Client) Reviews() ReviewsService {
+	return c.reviews
+}
+
+// Sales returns the sales reports service
+func (c *Client) Sales() SalesService {
+	return c.sales
+}
+
+// Finance returns the finance reports service
+func (c *Client) Finance() FinanceService {
+	return c.finance
+}
+
+// GetSalesReport fetches a sales report from the App Store Connect API. It
+// returns nil data when Apple has no report for the period.
+func (c *Client) GetSalesReport(ctx context.Context, frequency models.ReportFrequency, reportDate string, reportType models.ReportType, vendorNumber string) ([]byte, error) {
+	query := jsonapi.NewQuery().
+		Filter("frequency", string(frequency)).
+		Filter("reportDate", reportDate).
+		Filter("reportSubType", string(models.ReportSubTypeSummary)).
+		Filter("reportType", string(reportType)).
+		Filter("vendorNumber", vendorNumber)
+	return c.downloadReport(ctx, "/v1/salesReports?"+query.Encode())
+}
+
+// GetFinancialReport fetches a financial report from the App Store Connect API
+func (c *Client) GetFinancialReport(ctx context.Context, regionCode, fiscalYear, fiscalPeriod, vendorNumber string) ([]byte, error) {
+	return c.Finance().Download(ctx, FinanceReportRequest{
+		RegionCode:   regionCode,
+		ReportDate:   fiscalYear + "-" + fiscalPeriod,
+		VendorNumber: vendorNumber,
+	})
+}
+
+// ListApps retrieves all apps
+func (c *Client) ListApps(ctx context.Context) ([]models.App, error) {
+	return c.Apps().List(ctx)
+}
+
+// GetApps retrieves all apps
+func (c *Client) GetApps(ctx context.Context) ([]models.App, error) {
+	return c.Apps().List(ctx)
+}
+
+// GetApp retrieves a single app
+func (c *Client) GetApp(ctx context.Context, appID string) (*models.App, error) {
+	return c.Apps().Get(ctx, appID)
+}
+
+// GetReviews retrieves the most recent reviews for an app
+//
+// Deprecated: use Reviews().List, which returns typed dates and responses.
+func (c *Client) GetReviews(ctx context.Context, appID string) ([]models.Review, error) {
+	list, err := c.Reviews().List(ctx, models.ReviewFilter{AppID: appID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-	
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		
-		// Try to parse as API error
-		var errorResp struct {
-			Errors []struct {
-				Code   string `json:"code"`
-				Status string `json:"status"`
-				Title  string `json:"title"`
-				Detail string `json:"detail"`
-			} `json:"errors"`
+
+	reviews := make([]models.Review, 0, len(list))
+	for _, r := range list {
+		review := models.Review{
+			Type:  r.Type,
+			ID:    r.ID,
+			Links: r.Links,
+			Attributes: models.ReviewAttributes{
+				Rating:           r.Attributes.Rating,
+				Title:            r.Attributes.Title,
+				Body:             r.Attributes.Body,
+				ReviewerNickname: r.Attributes.ReviewerNickname,
+				CreatedDate:      r.Attributes.CreatedDate.Format(time.RFC3339),
+				Territory:        r.Attributes.Territory,
+			},
 		}
-		
-		if err := json.Unmarshal(bodyBytes, &errorResp); err == nil && len(errorResp.Errors) > 0 {
-			// Special case for "no sales data"
-			if resp.StatusCode == 404 && errorResp.Errors[0].Code == "NOT_FOUND" &&
-				strings.Contains(errorResp.Errors[0].Detail, "no sales") {
-				// Return nil to indicate no data available
-				return nil, nil
+		if r.Response != nil {
+			review.Attributes.PublishedResponse = &models.DeveloperResponse{
+				ID:               r.Response.ID,
+				ResponseBody:     r.Response.Attributes.ResponseBody,
+				LastModifiedDate: r.Response.Attributes.ModifiedDate.Format(time.RFC3339),
+				State:            r.Response.Attributes.State,
 			}
-			return nil, fmt.Errorf("API error: %s - %s", errorResp.Errors[0].Code, errorResp.Errors[0].Detail)
 		}
-		
-		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
-	}
-	
-	// Handle the response based on content type
-	contentType := resp.Header.Get("Content-Type")
-	
-	switch contentType {
-	case "application/a-gzip":
-		// Decompress gzipped data
-		reader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer reader.Close()
-		
-		data, err := io.ReadAll(reader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read gzipped data: %w", err)
-		}
-		
-		return data, nil
-		
-	case "application/json":
-		// This might be an error response
-		var errorResp struct {
-			Errors []struct {
-				Code   string `json:"code"`
-				Status string `json:"status"`
-				Title  string `json:"title"`
-				Detail string `json:"detail"`
-			} `json:"errors"`
-		}
-		
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		if err := json.Unmarshal(bodyBytes, &errorResp); err == nil && len(errorResp.Errors) > 0 {
-			return nil, fmt.Errorf("API error: %s - %s", errorResp.Errors[0].Code, errorResp.Errors[0].Detail)
-		}
-		
-		return nil, fmt.Errorf("unexpected JSON response for sales report")
-		
-	default:
-		// Try to read raw data
-		data, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
-		}
-		
-		// If it's text/plain, it might be uncompressed CSV
-		if strings.HasPrefix(contentType, "text/") || len(data) > 0 {
-			return data, nil
-		}
-		
-		return nil, fmt.Errorf("unexpected content type: %s", contentType)
+		reviews = append(reviews, review)
 	}
+	return reviews, nil
 }
 
-// GetFinancialReport fetches a financial report from the App Store Connect API
-func (c *Client) GetFinancialReport(ctx context.Context, regionCode, fiscalYear, fiscalPeriod, vendorNumber string) ([]byte, error) {
-	// Construct the financial report API URL
-	url := fmt.Sprintf("/v1/financeReports?filter[regionCode]=%s&filter[reportDate]=%s-%s&filter[reportType]=FINANCIAL&filter[vendorNumber]=%s",
-		regionCode, fiscalYear, fiscalPeriod, vendorNumber)
-	
+// get sends an authenticated GET request for a path under the base URL, or
+// an absolute URL such as a pagination link
+func (c *Client) get(ctx context.Context, path, accept string) (*http.Response, error) {
+	url := path
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		url = c.apiClient.BaseURL + path
+	}
+
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", c.apiClient.BaseURL+url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	
+
 	// Get auth token
-	token, err := c.apiClient.GetAuthToken()
+	token, err := c.apiClient.Tokens.Token(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get auth token: %w", err)
 	}
-	
+
 	// Set headers
 	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/a-gzip")
-	
-	// Execute the request
+	req.Header.Set("Accept", accept)
+
 	resp, err := c.apiClient.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
-	defer resp.Body.Close()
-	
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-	
-	// Handle gzipped response
-	if resp.Header.Get("Content-Type") == "application/a-gzip" {
-		reader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer reader.Close()
-		
-		return io.ReadAll(reader)
-	}
-	
-	// Otherwise, read raw response
-	return io.ReadAll(resp.Body)
+	return resp, nil
 }
 
-// ListApps retrieves a list of apps
-func (c *Client) ListApps(ctx context.Context) ([]models.App, error) {
-	url := "/v1/apps"
-	
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", c.apiClient.BaseURL+url, nil)
+// getDocument fetches and decodes a JSON:API document
+func getDocument[T any](ctx context.Context, c *Client, path string) (*jsonapi.Document[T], error) {
+	resp, err := c.get(ctx, path, "application/json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
-	
-	// Get auth token
-	token, err := c.apiClient.GetAuthToken()
+	return jsonapi.DecodeResponse[T](resp)
+}
+
+// downloadReport fetches a gzipped sales or finance report and returns the
+// decompressed TSV. It returns nil when Apple has no report for the period.
+func (c *Client) downloadReport(ctx context.Context, path string) ([]byte, error) {
+	resp, err := c.get(ctx, path, "application/a-gzip")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get auth token: %w", err)
+		return nil, err
 	}
-	
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/json")
-	
-	resp, err := c.apiClient.HTTPClient.Do(req)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-	
-	var result struct {
-		Data []models.App `json:"data"`
-	}
-	
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		var errorResp jsonapi.ErrorDocument
+		if err := json.Unmarshal(data, &errorResp); err == nil && len(errorResp.Errors) > 0 {
+			apiErr := errorResp.Errors[0]
+			// Apple answers 404 when there were no sales in the period
+			if resp.StatusCode == http.StatusNotFound && apiErr.Code == "NOT_FOUND" &&
+				strings.Contains(apiErr.Detail, "no sales") {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Detail)
+		}
+		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
 	}
-	
-	return result.Data, nil
-}
 
-// GetApps retrieves a list of apps (stub)
-func (c *Client) GetApps(ctx context.Context) ([]models.App, error) {
-	return c.ListApps(ctx)
-}
+	// Reports are gzipped, whatever the Content-Type says
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer reader.Close()
 
-// GetApp retrieves a single app (stub)
-func (c *Client) GetApp(ctx context.Context, appID string) (*models.App, error) {
-	// TODO: Implement single app fetch
-	return nil, fmt.Errorf("not implemented")
+		data, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzipped data: %w", err)
+		}
+	}
+	return data, nil
 }
-
-// GetReviews retrieves reviews for an app (stub)
-func (c *Client) GetReviews(ctx context.Context, appID string) ([]models.Review, error) {
-	// TODO: Implement reviews fetch
-	return nil, fmt.Errorf("not implemented")
-}
\ No newline at end of file
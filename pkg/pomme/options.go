@@ -0,0 +1,135 @@
+package pomme
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/api"
+	"github.com/marcusziade/pomme/internal/services/cache"
+)
+
+// DefaultBaseURL is the App Store Connect API host
+const DefaultBaseURL = "https://api.appstoreconnect.apple.com"
+
+// Option configures a Client created with New
+type Option func(*options)
+
+// TokenProvider supplies bearer tokens for API requests. Implementations must
+// be safe for concurrent use.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// Cache stores parsed reports and reviews between calls. Implementations must
+// be safe for concurrent use.
+type Cache interface {
+	Get(key string) (interface{}, error)
+	Set(key string, value interface{}, ttl time.Duration) error
+	Delete(key string) error
+	Clear() error
+}
+
+// NewMemoryCache creates an in-memory cache, e.g. to share between clients
+func NewMemoryCache() Cache {
+	return cache.NewMemoryCache()
+}
+
+// RetryPolicy controls how GET requests that fail with a network error,
+// 429 or 5xx status are retried
+type RetryPolicy = api.RetryPolicy
+
+// DefaultRetryPolicy is used unless WithRetry says otherwise
+var DefaultRetryPolicy = api.DefaultRetryPolicy
+
+type options struct {
+	keyID        string
+	issuerID     string
+	privateKey   string
+	tokenTTL     time.Duration
+	tokens       TokenProvider
+	httpClient   *http.Client
+	baseURL      string
+	logger       *slog.Logger
+	cache        Cache
+	retry        RetryPolicy
+	vendorNumber string
+}
+
+// WithCredentials signs tokens with a team API key
+func WithCredentials(keyID, issuerID, privateKeyPEM string) Option {
+	return func(o *options) {
+		o.keyID = keyID
+		o.issuerID = issuerID
+		o.privateKey = privateKeyPEM
+	}
+}
+
+// WithIndividualKey signs tokens with an individual API key, which has no
+// issuer ID
+func WithIndividualKey(keyID, privateKeyPEM string) Option {
+	return WithCredentials(keyID, "", privateKeyPEM)
+}
+
+// WithTokenTTL sets the lifetime of signed tokens, at most 20 minutes
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.tokenTTL = ttl
+	}
+}
+
+// WithTokenProvider supplies tokens from p instead of signing them from
+// credentials, e.g. to share tokens between clients or use a signing service
+func WithTokenProvider(p TokenProvider) Option {
+	return func(o *options) {
+		o.tokens = p
+	}
+}
+
+// WithHTTPClient sends requests through c. Its transport is wrapped for
+// retries and logging; c itself isn't modified.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+// WithBaseURL points the client at another host, such as a proxy or a test
+// server. A trailing /v1 is accepted.
+func WithBaseURL(url string) Option {
+	return func(o *options) {
+		o.baseURL = url
+	}
+}
+
+// WithLogger logs each request at debug level and failures at warn level
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithCache caches parsed reports and reviews in c instead of a private
+// in-memory cache
+func WithCache(c Cache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// WithRetry sets the retry policy. Use RetryPolicy{MaxAttempts: 1} to
+// disable retries.
+func WithRetry(p RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// WithVendorNumber sets the vendor number used by sales and finance requests
+// that don't name one
+func WithVendorNumber(vendorNumber string) Option {
+	return func(o *options) {
+		o.vendorNumber = vendorNumber
+	}
+}
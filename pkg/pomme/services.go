@@ -0,0 +1,191 @@
+package pomme
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcusziade/pomme/internal/client"
+	"github.com/marcusziade/pomme/internal/services/cache"
+	"github.com/marcusziade/pomme/internal/services/reviews"
+	"github.com/marcusziade/pomme/internal/services/sales"
+	"github.com/marcusziade/pomme/pkg/jsonapi"
+	"github.com/marcusziade/pomme/pkg/models"
+)
+
+// AppsService reads apps
+type AppsService interface {
+	// List returns every app the key can see
+	List(ctx context.Context) ([]models.App, error)
+
+	// Get returns a single app
+	Get(ctx context.Context, appID string) (*models.App, error)
+}
+
+// ReviewsService reads and answers customer reviews
+type ReviewsService interface {
+	// List returns reviews matching the filter, with developer responses
+	List(ctx context.Context, filter models.ReviewFilter) ([]models.CustomerReview, error)
+
+	// Summary returns rating statistics for an app's recent reviews
+	Summary(ctx context.Context, appID string) (*models.ReviewSummary, error)
+
+	// Respond creates or replaces the developer response to a review
+	Respond(ctx context.Context, reviewID, text string) error
+}
+
+// SalesService downloads sales reports
+type SalesService interface {
+	// Report downloads and parses a summary sales report. It returns an empty
+	// report when Apple has no data for the period.
+	Report(ctx context.Context, req SalesReportRequest) (*models.SalesReport, error)
+
+	// Download returns the raw report TSV, or nil when Apple has no data for
+	// the period
+	Download(ctx context.Context, req SalesReportRequest) ([]byte, error)
+}
+
+// FinanceService downloads finance reports
+type FinanceService interface {
+	// Download returns the raw report TSV
+	Download(ctx context.Context, req FinanceReportRequest) ([]byte, error)
+}
+
+// SalesReportRequest selects a sales report
+type SalesReportRequest struct {
+	Frequency    models.ReportFrequency // Defaults to monthly
+	Date         time.Time              // Any day in the period
+	ReportType   models.ReportType      // Defaults to SALES
+	VendorNumber string                 // Defaults to WithVendorNumber
+	NoCache      bool                   // Skip cached reports
+}
+
+// FinanceReportRequest selects a finance report
+type FinanceReportRequest struct {
+	RegionCode   string // Region, e.g. US, or ZZ for all regions combined
+	ReportDate   string // Fiscal month as YYYY-MM
+	ReportType   string // FINANCIAL (default) or FINANCE_DETAIL
+	VendorNumber string // Defaults to WithVendorNumber
+}
+
+type appsService struct {
+	client *Client
+}
+
+func (s *appsService) List(ctx context.Context) ([]models.App, error) {
+	next := "/v1/apps?" + jsonapi.NewQuery().Limit(200).Encode()
+
+	var apps []models.App
+	for next != "" {
+		doc, err := getDocument[[]models.App](ctx, s.client, next)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, doc.Data...)
+		next = doc.Links.Next
+	}
+	return apps, nil
+}
+
+func (s *appsService) Get(ctx context.Context, appID string) (*models.App, error) {
+	doc, err := getDocument[models.App](ctx, s.client, "/v1/apps/"+appID)
+	if err != nil {
+		return nil, err
+	}
+	return &doc.Data, nil
+}
+
+type reviewsService struct {
+	service *reviews.Service
+}
+
+func newReviewsService(c *client.Client, store cache.Cache) *reviewsService {
+	return &reviewsService{service: reviews.NewServiceWithCache(c, store)}
+}
+
+func (s *reviewsService) List(ctx context.Context, filter models.ReviewFilter) ([]models.CustomerReview, error) {
+	return s.service.GetReviews(ctx, filter)
+}
+
+func (s *reviewsService) Summary(ctx context.Context, appID string) (*models.ReviewSummary, error) {
+	return s.service.GetReviewSummary(ctx, appID)
+}
+
+func (s *reviewsService) Respond(ctx context.Context, reviewID, text string) error {
+	return s.service.RespondToReview(ctx, reviewID, text)
+}
+
+type salesService struct {
+	client  *Client
+	service *sales.Service
+}
+
+func newSalesService(c *Client, store cache.Cache) *salesService {
+	return &salesService{client: c, service: sales.NewService(c, store)}
+}
+
+func (s *salesService) Report(ctx context.Context, req SalesReportRequest) (*models.SalesReport, error) {
+	options, err := s.options(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.service.GetReport(ctx, options)
+}
+
+func (s *salesService) Download(ctx context.Context, req SalesReportRequest) ([]byte, error) {
+	options, err := s.options(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.GetSalesReport(ctx, options.Period, options.FormatDate(), options.ReportType, options.VendorNumber)
+}
+
+// options fills in the request defaults
+func (s *salesService) options(req SalesReportRequest) (sales.ReportOptions, error) {
+	options := sales.ReportOptions{
+		Period:       req.Frequency,
+		Date:         req.Date,
+		ReportType:   req.ReportType,
+		VendorNumber: req.VendorNumber,
+		NoCache:      req.NoCache,
+	}
+	if options.Period == "" {
+		options.Period = models.ReportFrequencyMonthly
+	}
+	if options.ReportType == "" {
+		options.ReportType = models.ReportTypeSales
+	}
+	if options.VendorNumber == "" {
+		options.VendorNumber = s.client.vendorNumber
+	}
+	if options.VendorNumber == "" {
+		return options, fmt.Errorf("pomme: vendor number required: set it on the request or use WithVendorNumber")
+	}
+	if options.Date.IsZero() {
+		return options, fmt.Errorf("pomme: report date required")
+	}
+	return options, nil
+}
+
+type financeService struct {
+	client *Client
+}
+
+func (s *financeService) Download(ctx context.Context, req FinanceReportRequest) ([]byte, error) {
+	if req.ReportType == "" {
+		req.ReportType = "FINANCIAL"
+	}
+	if req.VendorNumber == "" {
+		req.VendorNumber = s.client.vendorNumber
+	}
+	if req.VendorNumber == "" {
+		return nil, fmt.Errorf("pomme: vendor number required: set it on the request or use WithVendorNumber")
+	}
+
+	query := jsonapi.NewQuery().
+		Filter("regionCode", req.RegionCode).
+		Filter("reportDate", req.ReportDate).
+		Filter("reportType", req.ReportType).
+		Filter("vendorNumber", req.VendorNumber)
+	return s.client.downloadReport(ctx, "/v1/financeReports?"+query.Encode())
+}
@@ -0,0 +1,66 @@
+package pomme
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type staticTokens struct{}
+
+func (staticTokens) Token(ctx context.Context) (string, error) {
+	return "token", nil
+}
+
+func TestAppsListPages(t *testing.T) {
+	// Three pages of two apps, linked by absolute next URLs as the API sends them
+	var server *httptest.Server
+	var cursors []string
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/apps" || r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+
+		page := map[string]int{"": 0, "Mg": 1, "NA": 2}[cursor]
+		next := ""
+		if page < 2 {
+			next = fmt.Sprintf(`"next": "%s/v1/apps?cursor=%s&limit=200",`, server.URL, []string{"Mg", "NA"}[page])
+		}
+		fmt.Fprintf(w, `{
+			"data": [
+				{"type": "apps", "id": "%d", "attributes": {"name": "App %d"}},
+				{"type": "apps", "id": "%d", "attributes": {"name": "App %d"}}
+			],
+			"links": {%s "self": "%s"},
+			"meta": {"paging": {"total": 6, "limit": 200}}
+		}`, 2*page+1, 2*page+1, 2*page+2, 2*page+2, next, r.URL)
+	}))
+	defer server.Close()
+
+	client, err := New(WithTokenProvider(staticTokens{}), WithBaseURL(server.URL+"/v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apps, err := client.Apps().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, app := range apps {
+		names = append(names, app.ID+" "+app.Attributes.Name)
+	}
+	want := []string{"1 App 1", "2 App 2", "3 App 3", "4 App 4", "5 App 5", "6 App 6"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("apps = %v, want %v", names, want)
+	}
+	if want := []string{"", "Mg", "NA"}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("fetched cursors %q, want %q", cursors, want)
+	}
+}